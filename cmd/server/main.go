@@ -60,17 +60,48 @@ func main() {
 	// 初始化服务
 	categoryService := services.NewCategoryService(db)
 	keywordService := services.NewKeywordService(db, cfg)
-	contentService := services.NewContentService(db, cfg)
+	complianceService := services.NewComplianceService(db)
+	promptService := services.NewPromptService(db, cfg)
+	experimentService := services.NewExperimentService(db)
+	faqService := services.NewFAQService(db)
+	contentService := services.NewContentService(db, cfg, complianceService, promptService, experimentService, faqService)
 	articleService := services.NewArticleService(db)
 	seoService := seo.NewSEOService(cfg)
+	sitemapService := services.NewSitemapService(db, seoService)
+	robotsService := services.NewRobotsService(db, seoService)
+	auditService := services.NewAuditService(db, seoService)
+	linkService := services.NewInternalLinkService(db, cfg, seoService)
+	relatedService := services.NewRelatedService(db)
+	searchService := services.NewSearchService(db)
 	authService := services.NewAuthService(db, cfg)
 	queueService := services.NewQueueService(db, rdb, cfg, contentService)
+	submissionService := services.NewSubmissionService(db, rdb, cfg)
 
 	// 初始化默认分类
 	if err := categoryService.InitDefaultCategories(); err != nil {
 		log.Printf("初始化默认分类失败: %v", err)
 	}
 
+	// 初始化默认合规规则
+	if err := complianceService.InitDefaultRules(); err != nil {
+		log.Printf("初始化默认合规规则失败: %v", err)
+	}
+
+	// 初始化默认关键词屏蔽规则
+	if err := keywordService.InitDefaultBlockRules(); err != nil {
+		log.Printf("初始化默认屏蔽规则失败: %v", err)
+	}
+
+	// 初始化默认robots规则
+	if err := robotsService.InitDefaultRules(); err != nil {
+		log.Printf("初始化默认robots规则失败: %v", err)
+	}
+
+	// 初始化默认提示模板
+	if err := promptService.InitDefaultTemplates(); err != nil {
+		log.Printf("初始化默认提示模板失败: %v", err)
+	}
+
 	// 创建默认管理员用户
 	adminUser := services.RegisterRequest{
 		Username: "admin",
@@ -100,6 +131,17 @@ func main() {
 		seoService,
 		authService,
 		queueService,
+		complianceService,
+		promptService,
+		experimentService,
+		faqService,
+		sitemapService,
+		submissionService,
+		robotsService,
+		auditService,
+		linkService,
+		relatedService,
+		searchService,
 	)
 
 	// 设置路由
@@ -113,6 +155,10 @@ func main() {
 	defer cancel()
 
 	go queueService.ProcessTasks(ctx)
+	go submissionService.ProcessSubmissions(ctx)
+	go keywordService.ProcessMetricsRefresh(ctx)
+	go keywordService.ProcessKeywordLifecycle(ctx)
+	go searchService.ProcessIndexing(ctx)
 
 	// 创建HTTP服务器
 	server := &http.Server{
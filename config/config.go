@@ -10,14 +10,18 @@ import (
 
 // Config 配置结构
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	AI       AIConfig       `mapstructure:"ai"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	API5118  API5118Config  `mapstructure:"api_5118"`
-	Content  ContentConfig  `mapstructure:"content"`
-	SEO      SEOConfig      `mapstructure:"seo"`
+	Server         ServerConfig         `mapstructure:"server"`
+	Database       DatabaseConfig       `mapstructure:"database"`
+	Redis          RedisConfig          `mapstructure:"redis"`
+	AI             AIConfig             `mapstructure:"ai"`
+	Auth           AuthConfig           `mapstructure:"auth"`
+	API5118        API5118Config        `mapstructure:"api_5118"`
+	Content        ContentConfig        `mapstructure:"content"`
+	SEO            SEOConfig            `mapstructure:"seo"`
+	Indexing       IndexingConfig       `mapstructure:"indexing"`
+	KeywordSources KeywordSourcesConfig `mapstructure:"keyword_sources"`
+	KeywordMetrics KeywordMetricsConfig `mapstructure:"keyword_metrics"`
+	KeywordQueue   KeywordQueueConfig   `mapstructure:"keyword_queue"`
 }
 
 // ServerConfig 服务器配置
@@ -54,6 +58,7 @@ type AIConfig struct {
 	DeepseekAPIKey string  `mapstructure:"deepseek_api_key"`
 	DeepseekAPIURL string  `mapstructure:"deepseek_api_url"`
 	OllamaEndpoint string  `mapstructure:"ollama_endpoint"`
+	EmbeddingModel string  `mapstructure:"embedding_model"` // Ollama向量模型，为空时不使用向量相似度
 }
 
 // AuthConfig 认证配置
@@ -71,14 +76,63 @@ type API5118Config struct {
 
 // ContentConfig 内容生成配置
 type ContentConfig struct {
-	ArticleMinLength int `mapstructure:"article_min_length"`
-	ArticleMaxLength int `mapstructure:"article_max_length"`
+	ArticleMinLength int  `mapstructure:"article_min_length"`
+	ArticleMaxLength int  `mapstructure:"article_max_length"`
+	PipelineMode     bool `mapstructure:"pipeline_mode"`
+	MaxSections      int  `mapstructure:"max_sections"`
+	StructuredOutput bool `mapstructure:"structured_output"`
+	JSONMaxRetries   int  `mapstructure:"json_max_retries"`
 }
 
 // SEOConfig SEO配置
 type SEOConfig struct {
-	SiteURL  string `mapstructure:"site_url"`
-	SiteName string `mapstructure:"site_name"`
+	SiteURL         string `mapstructure:"site_url"`
+	SiteName        string `mapstructure:"site_name"`
+	SiteDescription string `mapstructure:"site_description"`
+	FeedSize        int    `mapstructure:"feed_size"`         // 订阅源中的文章数量
+	FeedFullContent bool   `mapstructure:"feed_full_content"` // 订阅源默认输出全文，否则只输出摘要
+	InternalLinkMax int    `mapstructure:"internal_link_max"` // 每篇文章自动添加的最多站内链接数
+}
+
+// IndexingConfig 搜索引擎URL提交配置，令牌或密钥为空时不向对应搜索引擎提交
+type IndexingConfig struct {
+	BaiduEndpoint       string `mapstructure:"baidu_endpoint"`
+	BaiduSite           string `mapstructure:"baidu_site"`
+	BaiduToken          string `mapstructure:"baidu_token"`
+	BaiduDailyQuota     int    `mapstructure:"baidu_daily_quota"`
+	IndexNowEndpoint    string `mapstructure:"indexnow_endpoint"`
+	IndexNowKey         string `mapstructure:"indexnow_key"`
+	IndexNowKeyLocation string `mapstructure:"indexnow_key_location"`
+	IndexNowDailyQuota  int    `mapstructure:"indexnow_daily_quota"`
+	BingEndpoint        string `mapstructure:"bing_endpoint"`
+	BingAPIKey          string `mapstructure:"bing_api_key"`
+	BingDailyQuota      int    `mapstructure:"bing_daily_quota"`
+}
+
+// KeywordSourcesConfig 关键词来源配置，每日配额按请求外部接口的次数计算，0表示不限制
+type KeywordSourcesConfig struct {
+	API5118DailyQuota      int    `mapstructure:"api_5118_daily_quota"`
+	BaiduSuggestEndpoint   string `mapstructure:"baidu_suggest_endpoint"`
+	BaiduSuggestDailyQuota int    `mapstructure:"baidu_suggest_daily_quota"`
+	LLMDailyQuota          int    `mapstructure:"llm_daily_quota"`
+}
+
+// KeywordMetricsConfig 关键词指标定时刷新配置，刷新使用5118的每日配额
+type KeywordMetricsConfig struct {
+	AutoRefresh     bool `mapstructure:"auto_refresh"`
+	RefreshInterval int  `mapstructure:"refresh_interval"` // 刷新间隔（小时）
+	StaleDays       int  `mapstructure:"stale_days"`       // 最新指标超过该天数的关键词需要刷新
+	RefreshBatch    int  `mapstructure:"refresh_batch"`    // 每次最多刷新的关键词数
+	RefreshTrend    bool `mapstructure:"refresh_trend"`    // 同时刷新指数趋势，每个关键词额外调用一次接口
+	QuotaReserve    int  `mapstructure:"quota_reserve"`    // 为手动获取保留的5118每日调用次数
+}
+
+// KeywordQueueConfig 关键词生命周期和选题队列配置
+type KeywordQueueConfig struct {
+	RefreshDays     int  `mapstructure:"refresh_days"`     // 文章超过该天数未更新时关键词进入待更新状态
+	CheckInterval   int  `mapstructure:"check_interval"`   // 检查待更新关键词和重新计算机会得分的间隔（小时）
+	IncludeNew      bool `mapstructure:"include_new"`      // 选题队列包含未审核的新关键词
+	RefreshPriority int  `mapstructure:"refresh_priority"` // 待更新关键词的机会得分按该百分比计算
 }
 
 // LoadConfig 从配置文件和环境变量加载配置
@@ -123,15 +177,53 @@ func LoadConfig() (*Config, error) {
 	viper.Set("ai.deepseek_api_key", viper.GetString("AI_DEEPSEEK_API_KEY"))
 	viper.Set("ai.deepseek_api_url", viper.GetString("AI_DEEPSEEK_API_URL"))
 	viper.Set("ai.ollama_endpoint", viper.GetString("AI_OLLAMA_ENDPOINT"))
+	viper.Set("ai.embedding_model", viper.GetString("AI_EMBEDDING_MODEL"))
 
 	viper.Set("api_5118.key", viper.GetString("API_5118_KEY"))
 	viper.Set("api_5118.base_url", viper.GetString("API_5118_BASE_URL"))
 
 	viper.Set("content.article_min_length", viper.GetInt("ARTICLE_MIN_LENGTH"))
 	viper.Set("content.article_max_length", viper.GetInt("ARTICLE_MAX_LENGTH"))
+	viper.Set("content.pipeline_mode", viper.GetBool("CONTENT_PIPELINE_MODE"))
+	viper.Set("content.max_sections", viper.GetInt("CONTENT_MAX_SECTIONS"))
+	viper.Set("content.structured_output", viper.GetBool("CONTENT_STRUCTURED_OUTPUT"))
+	viper.Set("content.json_max_retries", viper.GetInt("CONTENT_JSON_MAX_RETRIES"))
 
 	viper.Set("seo.site_url", viper.GetString("SITE_URL"))
 	viper.Set("seo.site_name", viper.GetString("SITE_NAME"))
+	viper.Set("seo.site_description", viper.GetString("SITE_DESCRIPTION"))
+	viper.Set("seo.feed_size", viper.GetInt("FEED_SIZE"))
+	viper.Set("seo.feed_full_content", viper.GetBool("FEED_FULL_CONTENT"))
+	viper.Set("seo.internal_link_max", viper.GetInt("INTERNAL_LINK_MAX"))
+
+	viper.Set("indexing.baidu_endpoint", viper.GetString("BAIDU_PUSH_ENDPOINT"))
+	viper.Set("indexing.baidu_site", viper.GetString("BAIDU_PUSH_SITE"))
+	viper.Set("indexing.baidu_token", viper.GetString("BAIDU_PUSH_TOKEN"))
+	viper.Set("indexing.baidu_daily_quota", viper.GetInt("BAIDU_PUSH_DAILY_QUOTA"))
+	viper.Set("indexing.indexnow_endpoint", viper.GetString("INDEXNOW_ENDPOINT"))
+	viper.Set("indexing.indexnow_key", viper.GetString("INDEXNOW_KEY"))
+	viper.Set("indexing.indexnow_key_location", viper.GetString("INDEXNOW_KEY_LOCATION"))
+	viper.Set("indexing.indexnow_daily_quota", viper.GetInt("INDEXNOW_DAILY_QUOTA"))
+	viper.Set("indexing.bing_endpoint", viper.GetString("BING_SUBMIT_ENDPOINT"))
+	viper.Set("indexing.bing_api_key", viper.GetString("BING_API_KEY"))
+	viper.Set("indexing.bing_daily_quota", viper.GetInt("BING_DAILY_QUOTA"))
+
+	viper.Set("keyword_sources.api_5118_daily_quota", viper.GetInt("KEYWORD_5118_DAILY_QUOTA"))
+	viper.Set("keyword_sources.baidu_suggest_endpoint", viper.GetString("BAIDU_SUGGEST_ENDPOINT"))
+	viper.Set("keyword_sources.baidu_suggest_daily_quota", viper.GetInt("BAIDU_SUGGEST_DAILY_QUOTA"))
+	viper.Set("keyword_sources.llm_daily_quota", viper.GetInt("KEYWORD_LLM_DAILY_QUOTA"))
+
+	viper.Set("keyword_metrics.auto_refresh", viper.GetBool("KEYWORD_METRICS_AUTO_REFRESH"))
+	viper.Set("keyword_metrics.refresh_interval", viper.GetInt("KEYWORD_METRICS_REFRESH_INTERVAL"))
+	viper.Set("keyword_metrics.stale_days", viper.GetInt("KEYWORD_METRICS_STALE_DAYS"))
+	viper.Set("keyword_metrics.refresh_batch", viper.GetInt("KEYWORD_METRICS_REFRESH_BATCH"))
+	viper.Set("keyword_metrics.refresh_trend", viper.GetBool("KEYWORD_METRICS_REFRESH_TREND"))
+	viper.Set("keyword_metrics.quota_reserve", viper.GetInt("KEYWORD_METRICS_QUOTA_RESERVE"))
+
+	viper.Set("keyword_queue.refresh_days", viper.GetInt("KEYWORD_REFRESH_DAYS"))
+	viper.Set("keyword_queue.check_interval", viper.GetInt("KEYWORD_QUEUE_CHECK_INTERVAL"))
+	viper.Set("keyword_queue.include_new", viper.GetBool("KEYWORD_QUEUE_INCLUDE_NEW"))
+	viper.Set("keyword_queue.refresh_priority", viper.GetInt("KEYWORD_REFRESH_PRIORITY"))
 
 	viper.Set("auth.jwt_secret", viper.GetString("JWT_SECRET"))
 	viper.Set("auth.access_token_expiry", viper.GetDuration("ACCESS_TOKEN_EXPIRY"))
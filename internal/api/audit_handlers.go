@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditArticle 审核文章的页面SEO并保存结果
+func (h *Handler) AuditArticle(c *gin.Context) {
+	idStr := c.Param("articleId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	audit, err := h.auditService.AuditArticle(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "审核文章失败: "+err.Error())
+		return
+	}
+
+	Success(c, audit)
+}
+
+// GetArticleAudits 获取文章的历史审核结果
+func (h *Handler) GetArticleAudits(c *gin.Context) {
+	idStr := c.Param("articleId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	page, pageSize := auditPagination(c)
+	audits, total, err := h.auditService.GetArticleAudits(uint(id), page, pageSize)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取审核结果失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    audits,
+	})
+}
+
+// RunSiteAudit 审核所有已发布文章并生成全站报告
+func (h *Handler) RunSiteAudit(c *gin.Context) {
+	report, err := h.auditService.RunSiteAudit()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "全站审核失败: "+err.Error())
+		return
+	}
+
+	Success(c, report)
+}
+
+// GetAuditReports 获取全站审核报告列表
+func (h *Handler) GetAuditReports(c *gin.Context) {
+	page, pageSize := auditPagination(c)
+	reports, total, err := h.auditService.GetReports(page, pageSize)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取审核报告失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    reports,
+	})
+}
+
+// GetAuditReport 获取全站审核报告详情
+func (h *Handler) GetAuditReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的报告ID")
+		return
+	}
+
+	report, err := h.auditService.GetReport(uint(id))
+	if err != nil {
+		Error(c, http.StatusNotFound, "审核报告不存在")
+		return
+	}
+
+	Success(c, report)
+}
+
+// auditPagination 解析分页参数
+func auditPagination(c *gin.Context) (int, int) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCannibalizationReport 获取关键词蚕食报告：争夺相同关键词的已发布文章及处理建议
+func (h *Handler) GetCannibalizationReport(c *gin.Context) {
+	groups, err := h.auditService.GetCannibalizationReport()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词蚕食报告失败: "+err.Error())
+		return
+	}
+
+	Success(c, groups)
+}
+
+// ResolveCannibalization 一键处理关键词蚕食：设置规范URL，或301跳转并归档被替换文章
+func (h *Handler) ResolveCannibalization(c *gin.Context) {
+	var req struct {
+		ArticleID uint   `json:"article_id" binding:"required"` // 被替换文章
+		WinnerID  uint   `json:"winner_id" binding:"required"`  // 保留文章
+		Action    string `json:"action" binding:"required"`     // canonical, redirect
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	result, err := h.auditService.ResolveCannibalization(req.ArticleID, req.WinnerID, req.Action)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCannibalResolution):
+			Error(c, http.StatusBadRequest, "处理关键词蚕食失败: "+err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			Error(c, http.StatusNotFound, "处理关键词蚕食失败: "+err.Error())
+		default:
+			Error(c, http.StatusInternalServerError, "处理关键词蚕食失败: "+err.Error())
+		}
+		return
+	}
+
+	// 跳转后被替换文章已归档，站内链接改为指向其他文章，保留文章接手了关键词，需要重新提交
+	if result.Action == seo.CannibalActionRedirect {
+		h.refreshInternalLinks(result.Loser)
+		h.articleChanged(c, result.Loser, "")
+		h.articleChanged(c, result.Winner, seo.SubmitActionUpdate)
+	} else {
+		h.articleChanged(c, result.Loser, seo.SubmitActionUpdate)
+	}
+
+	Success(c, result)
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// complianceRuleRequest 合规规则请求
+type complianceRuleRequest struct {
+	Pattern     string `json:"pattern" binding:"required"`
+	Category    string `json:"category" binding:"required"`
+	Severity    string `json:"severity" binding:"required"`
+	Replacement string `json:"replacement"`
+	Enabled     *bool  `json:"enabled"`
+}
+
+// toModel 转换为规则模型，未指定启用状态时默认启用
+func (r complianceRuleRequest) toModel() models.ComplianceRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return models.ComplianceRule{
+		Pattern:     r.Pattern,
+		Category:    r.Category,
+		Severity:    r.Severity,
+		Replacement: r.Replacement,
+		Enabled:     enabled,
+	}
+}
+
+// GetArticleCompliance 获取文章合规检查结果
+func (h *Handler) GetArticleCompliance(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	findings, err := h.complianceService.GetArticleFindings(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取合规检查结果失败: "+err.Error())
+		return
+	}
+
+	Success(c, findings)
+}
+
+// RecheckArticleCompliance 重新检查文章合规性
+func (h *Handler) RecheckArticleCompliance(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	findings, err := h.complianceService.ReviewArticle(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "合规检查失败: "+err.Error())
+		return
+	}
+
+	Success(c, findings)
+}
+
+// ResolveComplianceFinding 标记合规问题已处理
+func (h *Handler) ResolveComplianceFinding(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的检查结果ID")
+		return
+	}
+
+	// 获取当前用户ID
+	user, exists := c.Get("user")
+	if !exists {
+		Error(c, http.StatusUnauthorized, "未认证")
+		return
+	}
+	userModel := user.(*models.User)
+
+	finding, err := h.complianceService.ResolveFinding(uint(id), userModel.ID)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "更新合规检查结果失败: "+err.Error())
+		return
+	}
+
+	Success(c, finding)
+}
+
+// GetComplianceRules 获取合规规则列表
+func (h *Handler) GetComplianceRules(c *gin.Context) {
+	rules, err := h.complianceService.GetRules(c.Query("category"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取合规规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, rules)
+}
+
+// complianceRuleError 规则无效或词条重复时返回400，其余为服务器错误
+func complianceRuleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrInvalidComplianceRule) || errors.Is(err, services.ErrComplianceRuleExists) {
+		Error(c, http.StatusBadRequest, message+": "+err.Error())
+		return
+	}
+	Error(c, http.StatusInternalServerError, message+": "+err.Error())
+}
+
+// CreateComplianceRule 创建合规规则
+func (h *Handler) CreateComplianceRule(c *gin.Context) {
+	var req complianceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.complianceService.CreateRule(req.toModel())
+	if err != nil {
+		complianceRuleError(c, "创建合规规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// UpdateComplianceRule 更新合规规则
+func (h *Handler) UpdateComplianceRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	var req complianceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.complianceService.UpdateRule(uint(id), req.toModel())
+	if err != nil {
+		complianceRuleError(c, "更新合规规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// DeleteComplianceRule 删除合规规则
+func (h *Handler) DeleteComplianceRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	if err := h.complianceService.DeleteRule(uint(id)); err != nil {
+		Error(c, http.StatusInternalServerError, "删除合规规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, nil)
+}
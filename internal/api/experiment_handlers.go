@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetExperiments 获取提示模板实验列表
+func (h *Handler) GetExperiments(c *gin.Context) {
+	experiments, err := h.experimentService.ListExperiments(c.Query("status"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取实验列表失败: "+err.Error())
+		return
+	}
+
+	Success(c, experiments)
+}
+
+// CreateExperiment 创建提示模板实验
+func (h *Handler) CreateExperiment(c *gin.Context) {
+	var req struct {
+		Name        string                              `json:"name" binding:"required"`
+		Kind        string                              `json:"kind" binding:"required"`
+		Description string                              `json:"description"`
+		Variants    []services.ExperimentVariantRequest `json:"variants" binding:"required,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	experiment, err := h.experimentService.CreateExperiment(req.Name, req.Kind, req.Description, req.Variants)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "创建实验失败: "+err.Error())
+		return
+	}
+
+	Success(c, experiment)
+}
+
+// GetExperiment 获取提示模板实验详情
+func (h *Handler) GetExperiment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的实验ID")
+		return
+	}
+
+	experiment, err := h.experimentService.GetExperiment(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取实验失败: "+err.Error())
+		return
+	}
+
+	Success(c, experiment)
+}
+
+// StartExperiment 开始提示模板实验
+func (h *Handler) StartExperiment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的实验ID")
+		return
+	}
+
+	experiment, err := h.experimentService.StartExperiment(uint(id))
+	if err != nil {
+		Error(c, http.StatusBadRequest, "开始实验失败: "+err.Error())
+		return
+	}
+
+	Success(c, experiment)
+}
+
+// StopExperiment 结束提示模板实验
+func (h *Handler) StopExperiment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的实验ID")
+		return
+	}
+
+	experiment, err := h.experimentService.StopExperiment(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "结束实验失败: "+err.Error())
+		return
+	}
+
+	Success(c, experiment)
+}
+
+// GetExperimentReport 获取实验各分组的效果报告
+func (h *Handler) GetExperimentReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的实验ID")
+		return
+	}
+
+	report, err := h.experimentService.Report(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取实验报告失败: "+err.Error())
+		return
+	}
+
+	Success(c, report)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/pkg/ai"
+	"github.com/gin-gonic/gin"
+)
+
+// GetArticleFAQs 获取文章的常见问题
+func (h *Handler) GetArticleFAQs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	faqs, err := h.faqService.GetArticleFAQs(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取常见问题失败: "+err.Error())
+		return
+	}
+
+	Success(c, faqs)
+}
+
+// UpdateArticleFAQs 按提交的顺序替换文章的常见问题
+func (h *Handler) UpdateArticleFAQs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	var req struct {
+		FAQs []ai.FAQItem `json:"faqs"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	faqs, err := h.faqService.ReplaceArticleFAQs(uint(id), req.FAQs)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "更新常见问题失败: "+err.Error())
+		return
+	}
+
+	// 编辑后的常见问题重新进行合规检查
+	if _, err := h.complianceService.ReviewArticle(uint(id)); err != nil {
+		log.Printf("文章 %d 合规检查失败: %v", id, err)
+	}
+
+	Success(c, faqs)
+}
+
+// ExtractArticleFAQs 从文章正文中重新提取常见问题
+func (h *Handler) ExtractArticleFAQs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	faqs, err := h.faqService.ExtractArticleFAQs(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "提取常见问题失败: "+err.Error())
+		return
+	}
+
+	if _, err := h.complianceService.ReviewArticle(uint(id)); err != nil {
+		log.Printf("文章 %d 合规检查失败: %v", id, err)
+	}
+
+	Success(c, faqs)
+}
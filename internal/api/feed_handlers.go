@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultFeedSize 未配置时订阅源中的文章数量
+const defaultFeedSize = 20
+
+// GetRSSFeed 获取全站RSS订阅源
+func (h *Handler) GetRSSFeed(c *gin.Context) {
+	h.serveFeed(c, nil, false)
+}
+
+// GetAtomFeed 获取全站Atom订阅源
+func (h *Handler) GetAtomFeed(c *gin.Context) {
+	h.serveFeed(c, nil, true)
+}
+
+// GetCategoryRSSFeed 获取分类RSS订阅源
+func (h *Handler) GetCategoryRSSFeed(c *gin.Context) {
+	category, err := h.categoryService.GetCategoryByName(c.Param("name"))
+	if err != nil {
+		Error(c, http.StatusNotFound, "分类不存在")
+		return
+	}
+	h.serveFeed(c, category, false)
+}
+
+// GetCategoryAtomFeed 获取分类Atom订阅源
+func (h *Handler) GetCategoryAtomFeed(c *gin.Context) {
+	category, err := h.categoryService.GetCategoryByName(c.Param("name"))
+	if err != nil {
+		Error(c, http.StatusNotFound, "分类不存在")
+		return
+	}
+	h.serveFeed(c, category, true)
+}
+
+// serveFeed 输出订阅源，mode参数为full或summary时覆盖默认的全文/摘要设置
+func (h *Handler) serveFeed(c *gin.Context, category *models.Category, atom bool) {
+	full := h.config.SEO.FeedFullContent
+	switch c.Query("mode") {
+	case "full":
+		full = true
+	case "summary":
+		full = false
+	}
+
+	size := h.config.SEO.FeedSize
+	if size <= 0 {
+		size = defaultFeedSize
+	}
+
+	var categoryID *uint
+	if category != nil {
+		categoryID = &category.ID
+	}
+
+	articles, err := h.articleService.GetFeedArticles(categoryID, size)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取文章失败: "+err.Error())
+		return
+	}
+
+	// 生成订阅源
+	var feed string
+	contentType := "application/rss+xml; charset=utf-8"
+	if atom {
+		feed, err = h.seoService.GenerateAtom(articles, category, full)
+		contentType = "application/atom+xml; charset=utf-8"
+	} else {
+		feed, err = h.seoService.GenerateRSS(articles, category, full)
+	}
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "生成订阅源失败: "+err.Error())
+		return
+	}
+	c.Header("Content-Type", contentType)
+
+	// 处理If-None-Match和If-Modified-Since条件请求
+	sum := sha1.Sum([]byte(feed))
+	c.Header("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	http.ServeContent(c.Writer, c.Request, "", seo.LatestLastMod(articles), bytes.NewReader([]byte(feed)))
+}
@@ -2,28 +2,42 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
-	"time"
 
 	"github.com/NietzscheX/seo-generate/config"
 	"github.com/NietzscheX/seo-generate/internal/models"
 	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/NietzscheX/seo-generate/pkg/schema"
 	"github.com/NietzscheX/seo-generate/pkg/seo"
 	"github.com/gin-gonic/gin"
 )
 
 // Handler API处理器
 type Handler struct {
-	config          *config.Config
-	keywordService  *services.KeywordService
-	categoryService *services.CategoryService
-	contentService  *services.ContentService
-	articleService  *services.ArticleService
-	seoService      *seo.SEOService
-	authService     *services.AuthService
-	queueService    *services.QueueService
+	config            *config.Config
+	keywordService    *services.KeywordService
+	categoryService   *services.CategoryService
+	contentService    *services.ContentService
+	articleService    *services.ArticleService
+	seoService        *seo.SEOService
+	authService       *services.AuthService
+	queueService      *services.QueueService
+	complianceService *services.ComplianceService
+	promptService     *services.PromptService
+	experimentService *services.ExperimentService
+	faqService        *services.FAQService
+	sitemapService    *services.SitemapService
+	submissionService *services.SubmissionService
+	robotsService     *services.RobotsService
+	auditService      *services.AuditService
+	linkService       *services.InternalLinkService
+	relatedService    *services.RelatedService
+	searchService     *services.SearchService
 }
 
 // NewHandler 创建API处理器
@@ -36,16 +50,38 @@ func NewHandler(
 	seoService *seo.SEOService,
 	authService *services.AuthService,
 	queueService *services.QueueService,
+	complianceService *services.ComplianceService,
+	promptService *services.PromptService,
+	experimentService *services.ExperimentService,
+	faqService *services.FAQService,
+	sitemapService *services.SitemapService,
+	submissionService *services.SubmissionService,
+	robotsService *services.RobotsService,
+	auditService *services.AuditService,
+	linkService *services.InternalLinkService,
+	relatedService *services.RelatedService,
+	searchService *services.SearchService,
 ) *Handler {
 	return &Handler{
-		config:          cfg,
-		keywordService:  keywordService,
-		categoryService: categoryService,
-		contentService:  contentService,
-		articleService:  articleService,
-		seoService:      seoService,
-		authService:     authService,
-		queueService:    queueService,
+		config:            cfg,
+		keywordService:    keywordService,
+		categoryService:   categoryService,
+		contentService:    contentService,
+		articleService:    articleService,
+		seoService:        seoService,
+		authService:       authService,
+		queueService:      queueService,
+		complianceService: complianceService,
+		promptService:     promptService,
+		experimentService: experimentService,
+		faqService:        faqService,
+		sitemapService:    sitemapService,
+		submissionService: submissionService,
+		robotsService:     robotsService,
+		auditService:      auditService,
+		linkService:       linkService,
+		relatedService:    relatedService,
+		searchService:     searchService,
 	}
 }
 
@@ -149,6 +185,9 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	// 分类名称变化会影响分类Sitemap
+	h.sitemapService.Invalidate()
+
 	Success(c, category)
 }
 
@@ -166,14 +205,19 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
+	h.sitemapService.Invalidate()
+
 	Success(c, nil)
 }
 
-// FetchKeywords 获取关键词
+// FetchKeywords 从指定来源获取关键词
 func (h *Handler) FetchKeywords(c *gin.Context) {
 	var req struct {
-		Category string `json:"category" binding:"required"`
+		Source   string `json:"source"`   // 5118, baidu_suggest, related_search, llm，默认为5118
+		Category string `json:"category"` // 种子词，兼容按分类获取
+		Seed     string `json:"seed"`
 		Limit    int    `json:"limit"`
+		HTML     string `json:"html"` // 相关搜索来源使用的搜索结果页HTML
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -181,17 +225,38 @@ func (h *Handler) FetchKeywords(c *gin.Context) {
 		return
 	}
 
+	if req.Seed == "" {
+		req.Seed = req.Category
+	}
 	if req.Limit <= 0 {
 		req.Limit = 100
 	}
 
-	keywords, err := h.keywordService.FetchKeywordsByCategory(req.Category, req.Limit)
+	fetchReq := services.KeywordFetchRequest{
+		Source: req.Source,
+		Seed:   req.Seed,
+		Limit:  req.Limit,
+		HTML:   req.HTML,
+	}
+	if user, exists := c.Get("user"); exists {
+		fetchReq.UserID = &user.(*models.User).ID
+	}
+
+	result, err := h.keywordService.FetchKeywords(fetchReq)
 	if err != nil {
-		Error(c, http.StatusInternalServerError, "获取关键词失败: "+err.Error())
+		switch {
+		case errors.Is(err, services.ErrUnknownKeywordSource), errors.Is(err, services.ErrKeywordSourceDisabled),
+			errors.Is(err, seo.ErrInvalidKeywordQuery):
+			Error(c, http.StatusBadRequest, "获取关键词失败: "+err.Error())
+		case errors.Is(err, services.ErrKeywordQuotaExhausted):
+			Error(c, http.StatusTooManyRequests, "获取关键词失败: "+err.Error())
+		default:
+			Error(c, http.StatusInternalServerError, "获取关键词失败: "+err.Error())
+		}
 		return
 	}
 
-	Success(c, keywords)
+	Success(c, result)
 }
 
 // SearchKeywords 搜索关键词
@@ -210,7 +275,7 @@ func (h *Handler) SearchKeywords(c *gin.Context) {
 		pageSize = 20
 	}
 
-	keywords, total, err := h.keywordService.SearchKeywords(query, page, pageSize)
+	keywords, total, err := h.searchService.SearchKeywords(query, page, pageSize)
 	if err != nil {
 		Error(c, http.StatusInternalServerError, "搜索关键词失败: "+err.Error())
 		return
@@ -247,28 +312,47 @@ func (h *Handler) AssignKeywordToCategory(c *gin.Context) {
 // GenerateArticle 生成文章
 func (h *Handler) GenerateArticle(c *gin.Context) {
 	var req struct {
-		KeywordID   uint   `json:"keyword_id" binding:"required"`
+		KeywordID   uint   `json:"keyword_id"`
+		ClusterID   *uint  `json:"cluster_id"` // 以关键词聚类为目标生成，此时不需要keyword_id
 		CategoryIDs []uint `json:"category_ids"`
+		Mode        string `json:"mode"` // single, pipeline
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
 		return
 	}
-
-	// 获取关键词
-	keyword, err := h.keywordService.GetKeywordByID(req.KeywordID)
-	if err != nil {
-		Error(c, http.StatusInternalServerError, "获取关键词失败: "+err.Error())
+	if req.KeywordID == 0 && req.ClusterID == nil {
+		Error(c, http.StatusBadRequest, "需要指定关键词或关键词聚类")
 		return
 	}
 
+	// 获取关键词，以聚类为目标时使用聚类的主关键词
+	var keyword *models.Keyword
+	var err error
+	if req.ClusterID != nil {
+		keyword, _, err = h.keywordService.ClusterKeywords(*req.ClusterID, 0)
+		if err != nil {
+			Error(c, http.StatusInternalServerError, "获取关键词聚类失败: "+err.Error())
+			return
+		}
+	} else {
+		keyword, err = h.keywordService.GetKeywordByID(req.KeywordID)
+		if err != nil {
+			Error(c, http.StatusInternalServerError, "获取关键词失败: "+err.Error())
+			return
+		}
+	}
+
 	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.config.AI.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.contentService.GenerationTimeout(req.Mode))
 	defer cancel()
 
 	// 生成文章
-	article, err := h.contentService.GenerateArticle(ctx, *keyword, req.CategoryIDs)
+	article, err := h.contentService.GenerateArticleWithOptions(ctx, *keyword, req.CategoryIDs, services.GenerateOptions{
+		Mode:      req.Mode,
+		ClusterID: req.ClusterID,
+	})
 	if err != nil {
 		Error(c, http.StatusInternalServerError, "生成文章失败: "+err.Error())
 		return
@@ -277,6 +361,56 @@ func (h *Handler) GenerateArticle(c *gin.Context) {
 	Success(c, article)
 }
 
+// GetGenerationTask 获取生成任务及分段结果
+func (h *Handler) GetGenerationTask(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的任务ID")
+		return
+	}
+
+	task, err := h.contentService.GetGenerationTask(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取生成任务失败: "+err.Error())
+		return
+	}
+
+	Success(c, task)
+}
+
+// ResumeGenerationTask 继续执行失败的生成任务
+func (h *Handler) ResumeGenerationTask(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的任务ID")
+		return
+	}
+
+	task, err := h.contentService.GetGenerationTask(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取生成任务失败: "+err.Error())
+		return
+	}
+
+	// 创建上下文，设置超时
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.contentService.GenerationTimeout(task.Mode))
+	defer cancel()
+
+	article, err := h.contentService.ResumeArticle(ctx, task.ID)
+	if errors.Is(err, services.ErrTaskNotResumable) {
+		Error(c, http.StatusBadRequest, "继续生成文章失败: "+err.Error())
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "继续生成文章失败: "+err.Error())
+		return
+	}
+
+	Success(c, article)
+}
+
 // GetArticles 获取文章列表
 func (h *Handler) GetArticles(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
@@ -345,17 +479,73 @@ func (h *Handler) GetArticleBySlug(c *gin.Context) {
 		return
 	}
 
+	// 面包屑使用文章的第一个分类
+	var categoryPath []models.Category
+	if len(article.Categories) > 0 {
+		categoryPath, err = h.categoryService.GetCategoryPath(article.Categories[0].ID)
+		if err != nil {
+			log.Printf("文章 %d 获取分类路径失败: %v", article.ID, err)
+		}
+	}
+
 	// 生成结构化数据
-	schema := h.seoService.GenerateArticleSchema(article)
-	schemaJSON, _ := json.Marshal(schema)
+	graph, err := h.seoService.GenerateArticleGraph(article, categoryPath)
+	if err != nil {
+		log.Printf("文章 %d 生成结构化数据失败: %v", article.ID, err)
+	}
+
+	// 署名已写入结构化数据，公开接口不返回作者账号
+	article.User = nil
 
 	// 返回文章和结构化数据
 	Success(c, gin.H{
 		"article": article,
-		"schema":  string(schemaJSON),
+		"schema":  graph,
 	})
 }
 
+// ArticlePage 文章页面，被替换文章的旧地址跳转到保留文章
+func (h *Handler) ArticlePage(c *gin.Context) {
+	slug := c.Param("slug")
+
+	if redirect, err := h.articleService.GetRedirect(slug); err == nil {
+		c.Redirect(redirect.StatusCode, "/health/"+url.PathEscape(redirect.ToSlug))
+		return
+	}
+
+	c.HTML(200, "article.html", gin.H{
+		"slug": slug,
+	})
+}
+
+// CategoryPage 分类页面，列出分类下已发布的文章
+func (h *Handler) CategoryPage(c *gin.Context) {
+	category, err := h.categoryService.GetCategoryByName(c.Param("name"))
+	if err != nil {
+		Error(c, http.StatusNotFound, "分类不存在")
+		return
+	}
+
+	c.HTML(http.StatusOK, "category.html", gin.H{
+		"category":         category,
+		"canonical_url":    h.seoService.CategoryURL(category.Name),
+		"rss_url":          seo.FeedPath(category, "feed.xml"),
+		"atom_url":         seo.FeedPath(category, "atom.xml"),
+		"site_name":        h.config.SEO.SiteName,
+		"site_description": h.config.SEO.SiteDescription,
+	})
+}
+
+// siteSchema 首页的网站和组织结构化数据
+func (h *Handler) siteSchema() template.JS {
+	data, err := schema.MarshalGraph(h.seoService.GenerateWebSiteSchema(), h.seoService.GenerateOrganizationSchema())
+	if err != nil {
+		log.Printf("生成网站结构化数据失败: %v", err)
+		return ""
+	}
+	return template.JS(data)
+}
+
 // UpdateArticle 更新文章
 func (h *Handler) UpdateArticle(c *gin.Context) {
 	idStr := c.Param("id")
@@ -393,9 +583,41 @@ func (h *Handler) UpdateArticle(c *gin.Context) {
 		return
 	}
 
+	// 编辑后的内容重新进行合规检查
+	if _, err := h.complianceService.ReviewArticle(article.ID); err != nil {
+		log.Printf("文章 %d 合规检查失败: %v", article.ID, err)
+	}
+
+	// 记录实验文章的人工编辑量
+	if err := h.experimentService.RecordEdit(article.ID, article.Content); err != nil {
+		log.Printf("文章 %d 记录编辑距离失败: %v", article.ID, err)
+	}
+
+	h.refreshInternalLinks(article)
+
+	// 只有已发布的文章需要重新提交给搜索引擎
+	action := ""
+	if article.Status == "published" {
+		action = seo.SubmitActionUpdate
+	}
+	h.articleChanged(c, article, action)
+
 	Success(c, article)
 }
 
+// articleChanged 文章发布、更新、归档或删除后刷新依赖文章列表的缓存，action不为空时将URL提交给搜索引擎
+func (h *Handler) articleChanged(c *gin.Context, article *models.Article, action string) {
+	h.sitemapService.Invalidate()
+	h.relatedService.Invalidate()
+
+	if action == "" {
+		return
+	}
+	if err := h.submissionService.Enqueue(c.Request.Context(), article.ID, h.seoService.GenerateCanonicalURL(article.Slug), action); err != nil {
+		log.Printf("文章 %d 加入URL提交队列失败: %v", article.ID, err)
+	}
+}
+
 // PublishArticle 发布文章
 func (h *Handler) PublishArticle(c *gin.Context) {
 	idStr := c.Param("id")
@@ -411,6 +633,9 @@ func (h *Handler) PublishArticle(c *gin.Context) {
 		return
 	}
 
+	h.refreshInternalLinks(article)
+	h.articleChanged(c, article, seo.SubmitActionPublish)
+
 	Success(c, article)
 }
 
@@ -429,6 +654,14 @@ func (h *Handler) ArchiveArticle(c *gin.Context) {
 		return
 	}
 
+	// 发布过的文章归档后通知搜索引擎移除
+	action := ""
+	if article.PublishedAt != nil {
+		action = seo.SubmitActionDelete
+	}
+	h.refreshInternalLinks(article)
+	h.articleChanged(c, article, action)
+
 	Success(c, article)
 }
 
@@ -441,39 +674,30 @@ func (h *Handler) DeleteArticle(c *gin.Context) {
 		return
 	}
 
-	if err := h.articleService.DeleteArticle(uint(id)); err != nil {
-		Error(c, http.StatusInternalServerError, "删除文章失败: "+err.Error())
+	// 删除前获取文章地址，用于通知搜索引擎
+	article, err := h.articleService.GetArticleByID(uint(id))
+	if err != nil {
+		Error(c, http.StatusNotFound, "文章不存在")
 		return
 	}
 
-	Success(c, nil)
-}
-
-// GetSitemap 获取Sitemap
-func (h *Handler) GetSitemap(c *gin.Context) {
-	// 获取所有已发布的文章
-	articles, _, err := h.articleService.GetArticles(1, 1000, nil, "published")
-	if err != nil {
-		Error(c, http.StatusInternalServerError, "获取文章失败: "+err.Error())
+	if err := h.articleService.DeleteArticle(uint(id)); err != nil {
+		Error(c, http.StatusInternalServerError, "删除文章失败: "+err.Error())
 		return
 	}
 
-	// 生成Sitemap
-	sitemap, err := h.seoService.GenerateSitemap(articles)
-	if err != nil {
-		Error(c, http.StatusInternalServerError, "生成Sitemap失败: "+err.Error())
-		return
+	// 其他文章中指向该文章的链接需要移除
+	if err := h.linkService.ArticleDeleted(article.ID); err != nil {
+		log.Printf("移除指向文章 %d 的站内链接失败: %v", article.ID, err)
 	}
 
-	c.Header("Content-Type", "application/xml")
-	c.String(http.StatusOK, sitemap)
-}
+	action := ""
+	if article.Status == "published" {
+		action = seo.SubmitActionDelete
+	}
+	h.articleChanged(c, article, action)
 
-// GetRobotsTxt 获取robots.txt
-func (h *Handler) GetRobotsTxt(c *gin.Context) {
-	robotsTxt := h.seoService.GenerateRobotsTxt()
-	c.Header("Content-Type", "text/plain")
-	c.String(http.StatusOK, robotsTxt)
+	Success(c, nil)
 }
 
 // Register 用户注册
@@ -543,14 +767,42 @@ func (h *Handler) GetCurrentUser(c *gin.Context) {
 // BatchGenerateArticles 批量生成文章
 func (h *Handler) BatchGenerateArticles(c *gin.Context) {
 	var req struct {
-		KeywordIDs  []uint `json:"keyword_ids" binding:"required"`
-		CategoryIDs []uint `json:"category_ids"`
+		KeywordIDs     []uint `json:"keyword_ids"`
+		ClusterIDs     []uint `json:"cluster_ids"` // 每个关键词聚类生成一篇文章
+		CategoryIDs    []uint `json:"category_ids"`
+		Next           int    `json:"next"`             // 从选题队列中取选题得分最高的关键词
+		NextCategoryID *uint  `json:"next_category_id"` // 只从该分类中选题
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
 		return
 	}
+	if req.Next < 0 || req.Next > services.MaxNextKeywords {
+		Error(c, http.StatusBadRequest, "选题数量必须在0到"+strconv.Itoa(services.MaxNextKeywords)+"之间")
+		return
+	}
+
+	// 从选题队列中取关键词，聚类的主关键词以整个聚类为目标生成
+	if req.Next > 0 {
+		next, err := h.keywordService.GetNextKeywords(req.Next, req.NextCategoryID)
+		if err != nil {
+			Error(c, http.StatusInternalServerError, "获取选题队列失败: "+err.Error())
+			return
+		}
+		for _, keyword := range next {
+			if keyword.IsClusterLead {
+				req.ClusterIDs = append(req.ClusterIDs, *keyword.ClusterID)
+			} else {
+				req.KeywordIDs = append(req.KeywordIDs, keyword.ID)
+			}
+		}
+	}
+
+	if len(req.KeywordIDs) == 0 && len(req.ClusterIDs) == 0 {
+		Error(c, http.StatusBadRequest, "需要指定关键词或关键词聚类，或选题队列中没有可写的关键词")
+		return
+	}
 
 	// 获取当前用户ID
 	user, exists := c.Get("user")
@@ -567,6 +819,13 @@ func (h *Handler) BatchGenerateArticles(c *gin.Context) {
 		return
 	}
 
+	clusterTaskIDs, err := h.queueService.BatchAddClusterTasks(c.Request.Context(), req.ClusterIDs, req.CategoryIDs, userModel.ID)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "添加生成任务失败: "+err.Error())
+		return
+	}
+	taskIDs = append(taskIDs, clusterTaskIDs...)
+
 	Success(c, gin.H{
 		"task_ids": taskIDs,
 		"message":  "任务已添加到队列",
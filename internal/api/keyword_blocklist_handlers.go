@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/gin-gonic/gin"
+)
+
+// keywordBlockRuleRequest 关键词屏蔽规则请求
+type keywordBlockRuleRequest struct {
+	Pattern   string `json:"pattern" binding:"required"`
+	MatchType string `json:"match_type" binding:"required"` // exact, contains, regex
+	Category  string `json:"category" binding:"required"`   // brand, competitor, adult, dangerous, custom
+	Enabled   *bool  `json:"enabled"`
+}
+
+// toModel 转换为规则模型，未指定启用状态时默认启用
+func (r keywordBlockRuleRequest) toModel() models.KeywordBlockRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return models.KeywordBlockRule{
+		Pattern:   r.Pattern,
+		MatchType: r.MatchType,
+		Category:  r.Category,
+		Enabled:   enabled,
+	}
+}
+
+// blockRuleError 根据错误类型返回屏蔽规则接口的状态码
+func blockRuleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, seo.ErrInvalidBlockRule) {
+		Error(c, http.StatusBadRequest, message+": "+err.Error())
+		return
+	}
+	Error(c, http.StatusInternalServerError, message+": "+err.Error())
+}
+
+// GetKeywordBlockRules 获取关键词屏蔽规则列表
+func (h *Handler) GetKeywordBlockRules(c *gin.Context) {
+	rules, err := h.keywordService.GetBlockRules(c.Query("category"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取屏蔽规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, rules)
+}
+
+// CreateKeywordBlockRule 创建关键词屏蔽规则
+func (h *Handler) CreateKeywordBlockRule(c *gin.Context) {
+	var req keywordBlockRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.keywordService.CreateBlockRule(req.toModel())
+	if err != nil {
+		blockRuleError(c, "创建屏蔽规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// UpdateKeywordBlockRule 更新关键词屏蔽规则
+func (h *Handler) UpdateKeywordBlockRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	var req keywordBlockRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.keywordService.UpdateBlockRule(uint(id), req.toModel())
+	if err != nil {
+		blockRuleError(c, "更新屏蔽规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// DeleteKeywordBlockRule 删除关键词屏蔽规则
+func (h *Handler) DeleteKeywordBlockRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	if err := h.keywordService.DeleteBlockRule(uint(id)); err != nil {
+		Error(c, http.StatusInternalServerError, "删除屏蔽规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, nil)
+}
+
+// ApplyKeywordBlocklist 对已有的新关键词应用屏蔽规则
+func (h *Handler) ApplyKeywordBlocklist(c *gin.Context) {
+	result, err := h.keywordService.ApplyBlocklist()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "应用屏蔽规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// categoryAssignmentError 根据错误类型返回关键词分类接口的状态码
+func categoryAssignmentError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCategoryAssignment):
+		Error(c, http.StatusBadRequest, message+": "+err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		Error(c, http.StatusNotFound, message+": "+err.Error())
+	default:
+		Error(c, http.StatusInternalServerError, message+": "+err.Error())
+	}
+}
+
+// SuggestKeywordCategories 为关键词生成分类建议
+func (h *Handler) SuggestKeywordCategories(c *gin.Context) {
+	var req struct {
+		KeywordIDs    []uint  `json:"keyword_ids"` // 为空时处理所有尚未分类的关键词
+		UseLLM        bool    `json:"use_llm"`
+		MinConfidence float64 `json:"min_confidence"`
+		AutoAccept    float64 `json:"auto_accept"` // 大于0时，置信度达到该值的最佳建议直接分配
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+	if req.MinConfidence < 0 || req.MinConfidence > 1 || req.AutoAccept < 0 || req.AutoAccept > 1 {
+		Error(c, http.StatusBadRequest, "置信度必须在0到1之间")
+		return
+	}
+
+	result, err := h.keywordService.SuggestCategories(services.KeywordCategoryOptions{
+		KeywordIDs:    req.KeywordIDs,
+		UseLLM:        req.UseLLM,
+		MinConfidence: req.MinConfidence,
+		AutoAccept:    req.AutoAccept,
+	})
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "生成分类建议失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
+
+// GetKeywordCategorySuggestions 获取分类建议列表
+func (h *Handler) GetKeywordCategorySuggestions(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	var categoryID *uint
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		id, err := strconv.ParseUint(categoryIDStr, 10, 32)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的分类ID")
+			return
+		}
+		cid := uint(id)
+		categoryID = &cid
+	}
+
+	minConfidence, _ := strconv.ParseFloat(c.Query("min_confidence"), 64)
+	status := c.DefaultQuery("status", services.SuggestionStatusPending)
+
+	suggestions, total, err := h.keywordService.GetCategorySuggestions(page, pageSize, status, categoryID, minConfidence)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取分类建议失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    suggestions,
+	})
+}
+
+// ReviewKeywordCategorySuggestions 批量接受或拒绝分类建议
+func (h *Handler) ReviewKeywordCategorySuggestions(c *gin.Context) {
+	var req struct {
+		IDs           []uint   `json:"ids"`
+		MinConfidence *float64 `json:"min_confidence"` // ids为空时审核所有置信度不低于该值的待审核建议
+		CategoryID    *uint    `json:"category_id"`
+		Accept        bool     `json:"accept"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	result, err := h.keywordService.ReviewCategorySuggestions(services.KeywordCategoryReview{
+		IDs:           req.IDs,
+		MinConfidence: req.MinConfidence,
+		CategoryID:    req.CategoryID,
+		Accept:        req.Accept,
+	})
+	if err != nil {
+		categoryAssignmentError(c, "审核分类建议失败", err)
+		return
+	}
+
+	Success(c, result)
+}
+
+// BatchAssignKeywordCategories 批量将关键词分配到分类
+func (h *Handler) BatchAssignKeywordCategories(c *gin.Context) {
+	var req struct {
+		KeywordIDs  []uint `json:"keyword_ids" binding:"required"`
+		CategoryIDs []uint `json:"category_ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	if err := h.keywordService.AssignKeywordsToCategories(req.KeywordIDs, req.CategoryIDs); err != nil {
+		categoryAssignmentError(c, "批量分配关键词失败", err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// BatchUnassignKeywordCategories 批量将关键词移出分类，category_ids为空时移出所有分类
+func (h *Handler) BatchUnassignKeywordCategories(c *gin.Context) {
+	var req struct {
+		KeywordIDs  []uint `json:"keyword_ids" binding:"required"`
+		CategoryIDs []uint `json:"category_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	removed, err := h.keywordService.UnassignKeywordsFromCategories(req.KeywordIDs, req.CategoryIDs)
+	if err != nil {
+		categoryAssignmentError(c, "批量移出关键词失败", err)
+		return
+	}
+
+	Success(c, gin.H{"removed": removed})
+}
+
+// SetCategoryTerms 设置分类词典，用于自动建议关键词分类
+func (h *Handler) SetCategoryTerms(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的分类ID")
+		return
+	}
+
+	var req struct {
+		Terms []string `json:"terms"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	category, err := h.categoryService.SetCategoryTerms(uint(id), req.Terms)
+	if err != nil {
+		categoryAssignmentError(c, "设置分类词典失败", err)
+		return
+	}
+
+	Success(c, category)
+}
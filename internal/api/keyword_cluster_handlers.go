@@ -0,0 +1,219 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// clusterError 根据错误类型返回聚类接口的状态码
+func clusterError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCluster):
+		Error(c, http.StatusBadRequest, message+": "+err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		Error(c, http.StatusNotFound, message+": "+err.Error())
+	default:
+		Error(c, http.StatusInternalServerError, message+": "+err.Error())
+	}
+}
+
+// RebuildKeywordClusters 重新聚类关键词
+func (h *Handler) RebuildKeywordClusters(c *gin.Context) {
+	var req struct {
+		CategoryID         *uint   `json:"category_id"`
+		LexicalThreshold   float64 `json:"lexical_threshold"`
+		EmbeddingThreshold float64 `json:"embedding_threshold"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+	if req.LexicalThreshold < 0 || req.LexicalThreshold > 1 || req.EmbeddingThreshold < 0 || req.EmbeddingThreshold > 1 {
+		Error(c, http.StatusBadRequest, "相似度阈值必须在0到1之间")
+		return
+	}
+
+	result, err := h.keywordService.RebuildClusters(services.KeywordClusterOptions{
+		CategoryID:         req.CategoryID,
+		LexicalThreshold:   req.LexicalThreshold,
+		EmbeddingThreshold: req.EmbeddingThreshold,
+	})
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "关键词聚类失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
+
+// GetKeywordClusters 获取关键词聚类列表
+func (h *Handler) GetKeywordClusters(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	clusters, total, err := h.keywordService.GetClusters(page, pageSize, c.Query("q"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词聚类失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    clusters,
+	})
+}
+
+// GetKeywordCluster 获取关键词聚类及其关键词
+func (h *Handler) GetKeywordCluster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的聚类ID")
+		return
+	}
+
+	keywordCluster, err := h.keywordService.GetCluster(uint(id))
+	if err != nil {
+		clusterError(c, "获取关键词聚类失败", err)
+		return
+	}
+
+	Success(c, keywordCluster)
+}
+
+// CreateKeywordCluster 手动创建关键词聚类
+func (h *Handler) CreateKeywordCluster(c *gin.Context) {
+	var req struct {
+		Name             string `json:"name"`
+		KeywordIDs       []uint `json:"keyword_ids" binding:"required"`
+		PrimaryKeywordID *uint  `json:"primary_keyword_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	keywordCluster, err := h.keywordService.CreateCluster(req.Name, req.KeywordIDs, req.PrimaryKeywordID)
+	if err != nil {
+		clusterError(c, "创建关键词聚类失败", err)
+		return
+	}
+
+	Success(c, keywordCluster)
+}
+
+// UpdateKeywordCluster 修改关键词聚类的名称、主关键词或锁定状态
+func (h *Handler) UpdateKeywordCluster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的聚类ID")
+		return
+	}
+
+	var req struct {
+		Name             *string `json:"name"`
+		PrimaryKeywordID *uint   `json:"primary_keyword_id"`
+		Locked           *bool   `json:"locked"` // 解除锁定后重新聚类时会重新分组
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	keywordCluster, err := h.keywordService.UpdateCluster(uint(id), req.Name, req.PrimaryKeywordID, req.Locked)
+	if err != nil {
+		clusterError(c, "更新关键词聚类失败", err)
+		return
+	}
+
+	Success(c, keywordCluster)
+}
+
+// DeleteKeywordCluster 删除关键词聚类
+func (h *Handler) DeleteKeywordCluster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的聚类ID")
+		return
+	}
+
+	if err := h.keywordService.DeleteCluster(uint(id)); err != nil {
+		clusterError(c, "删除关键词聚类失败", err)
+		return
+	}
+
+	Success(c, nil)
+}
+
+// AddKeywordClusterKeywords 将关键词加入聚类
+func (h *Handler) AddKeywordClusterKeywords(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的聚类ID")
+		return
+	}
+
+	var req struct {
+		KeywordIDs []uint `json:"keyword_ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	keywordCluster, err := h.keywordService.AddClusterKeywords(uint(id), req.KeywordIDs)
+	if err != nil {
+		clusterError(c, "添加聚类关键词失败", err)
+		return
+	}
+
+	Success(c, keywordCluster)
+}
+
+// RemoveKeywordClusterKeyword 将关键词移出聚类
+func (h *Handler) RemoveKeywordClusterKeyword(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的聚类ID")
+		return
+	}
+
+	keywordIDStr := c.Param("keyword_id")
+	keywordID, err := strconv.ParseUint(keywordIDStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的关键词ID")
+		return
+	}
+
+	if err := h.keywordService.RemoveClusterKeyword(uint(id), uint(keywordID)); err != nil {
+		clusterError(c, "移出聚类关键词失败", err)
+		return
+	}
+
+	Success(c, nil)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/NietzscheX/seo-generate/pkg/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+// maxKeywordImportSize 导入文件的最大字节数
+const maxKeywordImportSize = 10 << 20
+
+// ImportKeywords 从上传的CSV或XLSX表格导入关键词，dry_run=true时只返回预览结果
+func (h *Handler) ImportKeywords(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		Error(c, http.StatusBadRequest, "请上传CSV或XLSX文件: "+err.Error())
+		return
+	}
+	if fileHeader.Size > maxKeywordImportSize {
+		Error(c, http.StatusBadRequest, fmt.Sprintf("文件不能超过%dMB", maxKeywordImportSize>>20))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		Error(c, http.StatusBadRequest, "读取文件失败: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxKeywordImportSize))
+	if err != nil {
+		Error(c, http.StatusBadRequest, "读取文件失败: "+err.Error())
+		return
+	}
+
+	opts := services.KeywordImportOptions{
+		Source:           c.PostForm("source"),
+		CreateCategories: c.PostForm("create_categories") == "true",
+		DryRun:           c.PostForm("dry_run") == "true",
+	}
+
+	// 列映射为JSON，如 {"word":"关键词","search_volume":"B"}
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			Error(c, http.StatusBadRequest, "无效的列映射: "+err.Error())
+			return
+		}
+	}
+
+	if categoryIDStr := c.PostForm("category_id"); categoryIDStr != "" {
+		id, err := strconv.ParseUint(categoryIDStr, 10, 32)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的分类ID")
+			return
+		}
+		categoryID := uint(id)
+		opts.CategoryID = &categoryID
+	}
+
+	result, err := h.keywordService.ImportKeywords(fileHeader.Filename, data, opts)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "导入关键词失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
+
+// ExportKeywords 按条件导出关键词为CSV或XLSX表格
+func (h *Handler) ExportKeywords(c *gin.Context) {
+	format := c.DefaultQuery("format", spreadsheet.FormatCSV)
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		Error(c, http.StatusBadRequest, spreadsheet.ErrUnsupportedFormat.Error())
+		return
+	}
+
+	filter := services.KeywordExportFilter{
+		Source: c.Query("source"),
+		Status: c.Query("status"),
+		Query:  c.Query("q"),
+	}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		id, err := strconv.ParseUint(categoryIDStr, 10, 32)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的分类ID")
+			return
+		}
+		categoryID := uint(id)
+		filter.CategoryID = &categoryID
+	}
+
+	for param, target := range map[string]**int{
+		"min_volume": &filter.MinVolume,
+		"max_volume": &filter.MaxVolume,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的搜索量: "+value)
+			return
+		}
+		*target = &n
+	}
+
+	rows, err := h.keywordService.ExportKeywords(filter)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "导出关键词失败: "+err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(&buf, format, "关键词", rows); err != nil {
+		Error(c, http.StatusInternalServerError, "导出关键词失败: "+err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("keywords-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, spreadsheet.ContentType(format), buf.Bytes())
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ClassifyKeywordIntents 判断关键词的搜索意图和领域内容意图
+func (h *Handler) ClassifyKeywordIntents(c *gin.Context) {
+	var req struct {
+		KeywordIDs []uint  `json:"keyword_ids"` // 为空时分类所有尚未分类的关键词
+		Reclassify bool    `json:"reclassify"`  // 重新分类所有关键词，人工指定的除外
+		UseLLM     bool    `json:"use_llm"`     // 规则置信度较低的关键词交给大模型分类
+		Threshold  float64 `json:"threshold"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+	if req.Threshold < 0 || req.Threshold > 1 {
+		Error(c, http.StatusBadRequest, "置信度阈值必须在0到1之间")
+		return
+	}
+
+	result, err := h.keywordService.ClassifyIntents(services.KeywordIntentOptions{
+		KeywordIDs: req.KeywordIDs,
+		Reclassify: req.Reclassify,
+		UseLLM:     req.UseLLM,
+		Threshold:  req.Threshold,
+	})
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "关键词意图分类失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
+
+// UpdateKeywordIntent 人工指定关键词意图，intent为空时恢复为规则分类
+func (h *Handler) UpdateKeywordIntent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的关键词ID")
+		return
+	}
+
+	var req struct {
+		Intent       string `json:"intent"`
+		DomainIntent string `json:"domain_intent"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	keyword, err := h.keywordService.SetKeywordIntent(uint(id), req.Intent, req.DomainIntent)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidIntent):
+			Error(c, http.StatusBadRequest, "更新关键词意图失败: "+err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			Error(c, http.StatusNotFound, "更新关键词意图失败: "+err.Error())
+		default:
+			Error(c, http.StatusInternalServerError, "更新关键词意图失败: "+err.Error())
+		}
+		return
+	}
+
+	Success(c, keyword)
+}
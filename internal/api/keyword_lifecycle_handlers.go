@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetNextKeywords 获取选题队列中选题得分最高的关键词
+func (h *Handler) GetNextKeywords(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	var categoryID *uint
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		id, err := strconv.ParseUint(categoryIDStr, 10, 32)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的分类ID")
+			return
+		}
+		cid := uint(id)
+		categoryID = &cid
+	}
+
+	keywords, err := h.keywordService.GetNextKeywords(limit, categoryID)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取选题队列失败: "+err.Error())
+		return
+	}
+
+	Success(c, keywords)
+}
+
+// UpdateKeywordStatus 批量修改关键词状态
+func (h *Handler) UpdateKeywordStatus(c *gin.Context) {
+	var req struct {
+		KeywordIDs []uint `json:"keyword_ids" binding:"required"`
+		Status     string `json:"status" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	result, err := h.keywordService.SetKeywordStatus(req.KeywordIDs, req.Status)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidKeywordStatus) {
+			Error(c, http.StatusBadRequest, "修改关键词状态失败: "+err.Error())
+			return
+		}
+		Error(c, http.StatusInternalServerError, "修改关键词状态失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
+
+// RefreshKeywordLifecycle 立即检查关键词生命周期并重新计算选题得分
+func (h *Handler) RefreshKeywordLifecycle(c *gin.Context) {
+	result, err := h.keywordService.RefreshLifecycle()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "检查关键词生命周期失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
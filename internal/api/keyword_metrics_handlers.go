@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RefreshKeywordMetrics 从5118刷新关键词指标
+func (h *Handler) RefreshKeywordMetrics(c *gin.Context) {
+	var req struct {
+		KeywordIDs []uint `json:"keyword_ids" binding:"required"`
+		Trend      bool   `json:"trend"`     // 同时获取指数趋势，每个关键词额外调用一次接口
+		Questions  bool   `json:"questions"` // 同时获取相关问题，每个关键词额外调用一次接口
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	metrics, err := h.keywordService.RefreshMetrics(req.KeywordIDs, services.KeywordMetricsOptions{
+		Trend:     req.Trend,
+		Questions: req.Questions,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrKeywordSourceDisabled):
+			Error(c, http.StatusBadRequest, "刷新关键词指标失败: "+err.Error())
+		case errors.Is(err, services.ErrKeywordQuotaExhausted):
+			Error(c, http.StatusTooManyRequests, "刷新关键词指标失败: "+err.Error())
+		default:
+			Error(c, http.StatusInternalServerError, "刷新关键词指标失败: "+err.Error())
+		}
+		return
+	}
+
+	Success(c, metrics)
+}
+
+// GetKeywordMetric 获取关键词最新的指标
+func (h *Handler) GetKeywordMetric(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的关键词ID")
+		return
+	}
+
+	metric, err := h.keywordService.GetLatestMetric(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			Error(c, http.StatusNotFound, "关键词暂无指标")
+			return
+		}
+		Error(c, http.StatusInternalServerError, "获取关键词指标失败: "+err.Error())
+		return
+	}
+
+	Success(c, metric)
+}
+
+// GetKeywordOpportunities 按机会得分列出关键词
+func (h *Handler) GetKeywordOpportunities(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	var categoryID *uint
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		id, err := strconv.ParseUint(categoryIDStr, 10, 32)
+		if err != nil {
+			Error(c, http.StatusBadRequest, "无效的分类ID")
+			return
+		}
+		cid := uint(id)
+		categoryID = &cid
+	}
+
+	opportunities, total, err := h.keywordService.GetOpportunities(page, pageSize, categoryID)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词机会失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    opportunities,
+	})
+}
+
+// GetKeywordMetricHistory 获取关键词的指标历史和季节性
+func (h *Handler) GetKeywordMetricHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的关键词ID")
+		return
+	}
+
+	months, _ := strconv.Atoi(c.DefaultQuery("months", "24"))
+
+	history, err := h.keywordService.GetMetricHistory(uint(id), months)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			Error(c, http.StatusNotFound, "关键词不存在")
+			return
+		}
+		Error(c, http.StatusInternalServerError, "获取关键词指标历史失败: "+err.Error())
+		return
+	}
+
+	Success(c, history)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetKeywordSources 获取各关键词来源的启用状态和当日配额使用情况
+func (h *Handler) GetKeywordSources(c *gin.Context) {
+	quotas, err := h.keywordService.GetSourceQuotas()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词来源失败: "+err.Error())
+		return
+	}
+
+	Success(c, quotas)
+}
+
+// GetKeywordFetches 获取关键词获取记录
+func (h *Handler) GetKeywordFetches(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	fetches, total, err := h.keywordService.GetFetches(page, pageSize, c.Query("source"), c.Query("status"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词获取记录失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    fetches,
+	})
+}
+
+// GetKeywordAttributions 获取关键词的来源记录
+func (h *Handler) GetKeywordAttributions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的关键词ID")
+		return
+	}
+
+	attributions, err := h.keywordService.GetKeywordAttributions(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取关键词来源失败: "+err.Error())
+		return
+	}
+
+	Success(c, attributions)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// refreshInternalLinks 文章保存、发布或归档后重新计算自动内链，未发布的文章同时从其他文章的链接中移除
+func (h *Handler) refreshInternalLinks(article *models.Article) {
+	if _, err := h.linkService.LinkArticle(article); err != nil {
+		log.Printf("文章 %d 计算站内链接失败: %v", article.ID, err)
+	}
+
+	if article.Status != "published" {
+		if err := h.linkService.RelinkSources(article.ID); err != nil {
+			log.Printf("移除指向文章 %d 的站内链接失败: %v", article.ID, err)
+		}
+	}
+}
+
+// GetArticleLinks 获取文章自动添加的站内链接
+func (h *Handler) GetArticleLinks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	links, err := h.linkService.GetArticleLinks(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取站内链接失败: "+err.Error())
+		return
+	}
+
+	Success(c, links)
+}
+
+// RelinkArticle 重新计算文章的自动内链
+func (h *Handler) RelinkArticle(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	links, err := h.linkService.Relink(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "计算站内链接失败: "+err.Error())
+		return
+	}
+
+	h.sitemapService.Invalidate()
+
+	Success(c, links)
+}
+
+// GetLinkOptOuts 获取自动内链排除列表
+func (h *Handler) GetLinkOptOuts(c *gin.Context) {
+	optOuts, err := h.linkService.GetOptOuts()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取内链排除列表失败: "+err.Error())
+		return
+	}
+
+	Success(c, optOuts)
+}
+
+// AddLinkOptOut 将文章加入自动内链排除列表
+func (h *Handler) AddLinkOptOut(c *gin.Context) {
+	var req struct {
+		ArticleID uint   `json:"article_id" binding:"required"`
+		Reason    string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	optOut, err := h.linkService.AddOptOut(req.ArticleID, req.Reason)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "添加内链排除失败: "+err.Error())
+		return
+	}
+
+	h.sitemapService.Invalidate()
+
+	Success(c, optOut)
+}
+
+// RemoveLinkOptOut 将文章移出自动内链排除列表
+func (h *Handler) RemoveLinkOptOut(c *gin.Context) {
+	idStr := c.Param("articleId")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	if err := h.linkService.RemoveOptOut(uint(id)); err != nil {
+		Error(c, http.StatusInternalServerError, "移除内链排除失败: "+err.Error())
+		return
+	}
+
+	h.sitemapService.Invalidate()
+
+	Success(c, nil)
+}
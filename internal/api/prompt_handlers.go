@@ -0,0 +1,215 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPromptTemplates 获取提示模板列表
+func (h *Handler) GetPromptTemplates(c *gin.Context) {
+	templates, err := h.promptService.ListTemplates(c.Query("kind"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取提示模板失败: "+err.Error())
+		return
+	}
+
+	Success(c, templates)
+}
+
+// GetPromptTemplate 获取提示模板详情及版本历史
+func (h *Handler) GetPromptTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的模板ID")
+		return
+	}
+
+	tmpl, err := h.promptService.GetTemplate(uint(id))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取提示模板失败: "+err.Error())
+		return
+	}
+
+	Success(c, tmpl)
+}
+
+// CreatePromptTemplate 创建提示模板
+func (h *Handler) CreatePromptTemplate(c *gin.Context) {
+	var req struct {
+		Name         string `json:"name" binding:"required"`
+		Kind         string `json:"kind" binding:"required"`
+		Intent       string `json:"intent"` // 适用的关键词意图，为空时适用所有意图
+		Description  string `json:"description"`
+		IsDefault    bool   `json:"is_default"`
+		SystemPrompt string `json:"system_prompt"`
+		Body         string `json:"body" binding:"required"`
+		CategoryIDs  []uint `json:"category_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	tmpl, err := h.promptService.CreateTemplate(req.Name, req.Kind, req.Intent, req.Description, req.IsDefault, req.SystemPrompt, req.Body, req.CategoryIDs)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "创建提示模板失败: "+err.Error())
+		return
+	}
+
+	Success(c, tmpl)
+}
+
+// CreatePromptTemplateVersion 创建提示模板新版本
+func (h *Handler) CreatePromptTemplateVersion(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的模板ID")
+		return
+	}
+
+	var req struct {
+		SystemPrompt string `json:"system_prompt"`
+		Body         string `json:"body" binding:"required"`
+		Note         string `json:"note"`
+		Activate     bool   `json:"activate"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	// 获取当前用户ID
+	user, exists := c.Get("user")
+	if !exists {
+		Error(c, http.StatusUnauthorized, "未认证")
+		return
+	}
+	userModel := user.(*models.User)
+
+	version, err := h.promptService.CreateVersion(uint(id), req.SystemPrompt, req.Body, req.Note, &userModel.ID, req.Activate)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "创建模板版本失败: "+err.Error())
+		return
+	}
+
+	Success(c, version)
+}
+
+// ActivatePromptTemplateVersion 启用提示模板的指定版本
+func (h *Handler) ActivatePromptTemplateVersion(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的模板ID")
+		return
+	}
+
+	var req struct {
+		Version int `json:"version" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	tmpl, err := h.promptService.ActivateVersion(uint(id), req.Version)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "启用模板版本失败: "+err.Error())
+		return
+	}
+
+	Success(c, tmpl)
+}
+
+// AssignPromptTemplateCategories 设置提示模板适用的分类
+func (h *Handler) AssignPromptTemplateCategories(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的模板ID")
+		return
+	}
+
+	var req struct {
+		CategoryIDs []uint `json:"category_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	tmpl, err := h.promptService.AssignCategories(uint(id), req.CategoryIDs)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "设置模板分类失败: "+err.Error())
+		return
+	}
+
+	Success(c, tmpl)
+}
+
+// PreviewPromptTemplate 预览提示模板渲染结果
+func (h *Handler) PreviewPromptTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的模板ID")
+		return
+	}
+
+	var req struct {
+		Version      int                 `json:"version"`       // 为空时使用启用的版本
+		SystemPrompt string              `json:"system_prompt"` // 与body一起用于预览未保存的模板
+		Body         string              `json:"body"`
+		KeywordID    uint                `json:"keyword_id"` // 根据关键词自动填充变量
+		CategoryIDs  []uint              `json:"category_ids"`
+		Variables    services.PromptData `json:"variables"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	data := req.Variables
+	if req.KeywordID > 0 {
+		keyword, err := h.keywordService.GetKeywordByID(req.KeywordID)
+		if err != nil {
+			Error(c, http.StatusInternalServerError, "获取关键词失败: "+err.Error())
+			return
+		}
+
+		built, err := h.promptService.BuildData(*keyword, req.CategoryIDs)
+		if err != nil {
+			Error(c, http.StatusInternalServerError, "构建模板变量失败: "+err.Error())
+			return
+		}
+
+		// 分段生成的变量沿用请求中的值
+		built.Title = data.Title
+		built.Outline = data.Outline
+		built.Heading = data.Heading
+		built.Points = data.Points
+		built.Previous = data.Previous
+		built.MinSections = data.MinSections
+		built.MaxSections = data.MaxSections
+		data = built
+	}
+
+	rendered, err := h.promptService.Preview(uint(id), req.Version, req.SystemPrompt, req.Body, data)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "渲染提示模板失败: "+err.Error())
+		return
+	}
+
+	Success(c, rendered)
+}
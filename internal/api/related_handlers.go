@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRelatedLimit 未指定数量时返回的相关文章数
+const defaultRelatedLimit = 5
+
+// GetRelatedArticles 获取相关文章
+func (h *Handler) GetRelatedArticles(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRelatedLimit)))
+	if limit <= 0 {
+		limit = defaultRelatedLimit
+	}
+
+	articles, err := h.relatedService.GetRelatedArticles(uint(id), limit)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取相关文章失败: "+err.Error())
+		return
+	}
+
+	Success(c, articles)
+}
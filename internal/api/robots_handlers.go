@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/gin-gonic/gin"
+)
+
+// robotsRuleRequest robots规则请求
+type robotsRuleRequest struct {
+	UserAgent string `json:"user_agent" binding:"required"`
+	Directive string `json:"directive" binding:"required"`
+	Value     string `json:"value"`
+	Position  int    `json:"position"`
+	Enabled   *bool  `json:"enabled"`
+}
+
+// toModel 转换为规则模型，未指定启用状态时默认启用
+func (r robotsRuleRequest) toModel() models.RobotsRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return models.RobotsRule{
+		UserAgent: r.UserAgent,
+		Directive: r.Directive,
+		Value:     r.Value,
+		Position:  r.Position,
+		Enabled:   enabled,
+	}
+}
+
+// robotsRuleError 根据错误类型返回robots规则接口的状态码
+func robotsRuleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, seo.ErrInvalidRobotsRule) {
+		Error(c, http.StatusBadRequest, message+": "+err.Error())
+		return
+	}
+	Error(c, http.StatusInternalServerError, message+": "+err.Error())
+}
+
+// GetRobotsTxt 获取robots.txt
+func (h *Handler) GetRobotsTxt(c *gin.Context) {
+	robotsTxt, err := h.robotsService.RobotsTxt()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "生成robots.txt失败: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.String(http.StatusOK, robotsTxt)
+}
+
+// GetRobotsRules 获取robots规则列表
+func (h *Handler) GetRobotsRules(c *gin.Context) {
+	rules, err := h.robotsService.GetRules(c.Query("user_agent"))
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取robots规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, rules)
+}
+
+// CreateRobotsRule 创建robots规则
+func (h *Handler) CreateRobotsRule(c *gin.Context) {
+	var req robotsRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.robotsService.CreateRule(req.toModel())
+	if err != nil {
+		robotsRuleError(c, "创建robots规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// UpdateRobotsRule 更新robots规则
+func (h *Handler) UpdateRobotsRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	var req robotsRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Error(c, http.StatusBadRequest, "无效的请求参数: "+err.Error())
+		return
+	}
+
+	rule, err := h.robotsService.UpdateRule(uint(id), req.toModel(), req.Enabled)
+	if err != nil {
+		robotsRuleError(c, "更新robots规则失败", err)
+		return
+	}
+
+	Success(c, rule)
+}
+
+// DeleteRobotsRule 删除robots规则
+func (h *Handler) DeleteRobotsRule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的规则ID")
+		return
+	}
+
+	if err := h.robotsService.DeleteRule(uint(id)); err != nil {
+		Error(c, http.StatusInternalServerError, "删除robots规则失败: "+err.Error())
+		return
+	}
+
+	Success(c, nil)
+}
+
+// TestRobotsURL 检测爬虫能否抓取URL
+func (h *Handler) TestRobotsURL(c *gin.Context) {
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		Error(c, http.StatusBadRequest, "url不能为空")
+		return
+	}
+
+	result, err := h.robotsService.Test(c.DefaultQuery("user_agent", "*"), rawURL)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "检测URL失败: "+err.Error())
+		return
+	}
+
+	Success(c, result)
+}
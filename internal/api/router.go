@@ -21,10 +21,18 @@ func SetupRouter(handler *Handler) *gin.Engine {
 	// SEO相关
 	r.GET("/robots.txt", handler.GetRobotsTxt)
 	r.GET("/sitemap.xml", handler.GetSitemap)
+	r.GET("/:file", handler.GetRootFile)
+	r.GET("/feed.xml", handler.GetRSSFeed)
+	r.GET("/atom.xml", handler.GetAtomFeed)
+	r.GET("/categories/:name/feed.xml", handler.GetCategoryRSSFeed)
+	r.GET("/categories/:name/atom.xml", handler.GetCategoryAtomFeed)
 
 	// API路由组
 	api := r.Group("/api")
 	{
+		// 全文搜索（公开访问）
+		api.GET("/search", handler.Search)
+
 		// 认证相关
 		auth := api.Group("/auth")
 		{
@@ -45,6 +53,7 @@ func SetupRouter(handler *Handler) *gin.Engine {
 				categories.POST("", handler.CreateCategory)
 				categories.PUT("/:id", handler.UpdateCategory)
 				categories.DELETE("/:id", handler.DeleteCategory)
+				categories.PUT("/:id/terms", handler.SetCategoryTerms)
 			}
 
 			// 分类相关（公开访问）
@@ -61,6 +70,44 @@ func SetupRouter(handler *Handler) *gin.Engine {
 				keywords.POST("/fetch", handler.FetchKeywords)
 				keywords.GET("/search", handler.SearchKeywords)
 				keywords.POST("/assign", handler.AssignKeywordToCategory)
+				keywords.POST("/import", handler.ImportKeywords)
+				keywords.GET("/export", handler.ExportKeywords)
+				keywords.GET("/sources", handler.GetKeywordSources)
+				keywords.GET("/fetches", handler.GetKeywordFetches)
+				keywords.GET("/:id/attributions", handler.GetKeywordAttributions)
+				keywords.POST("/metrics/refresh", handler.RefreshKeywordMetrics)
+				keywords.GET("/opportunities", handler.GetKeywordOpportunities)
+				keywords.GET("/:id/metrics", handler.GetKeywordMetric)
+				keywords.GET("/:id/metrics/history", handler.GetKeywordMetricHistory)
+				keywords.POST("/intents/classify", handler.ClassifyKeywordIntents)
+				keywords.PUT("/:id/intent", handler.UpdateKeywordIntent)
+				keywords.POST("/categories/suggest", handler.SuggestKeywordCategories)
+				keywords.GET("/categories/suggestions", handler.GetKeywordCategorySuggestions)
+				keywords.POST("/categories/suggestions/review", handler.ReviewKeywordCategorySuggestions)
+				keywords.POST("/categories/assign", handler.BatchAssignKeywordCategories)
+				keywords.POST("/categories/unassign", handler.BatchUnassignKeywordCategories)
+				keywords.GET("/next", handler.GetNextKeywords)
+				keywords.PUT("/status", handler.UpdateKeywordStatus)
+				keywords.POST("/lifecycle/refresh", handler.RefreshKeywordLifecycle)
+				keywords.GET("/blocklist", handler.GetKeywordBlockRules)
+				keywords.POST("/blocklist", handler.CreateKeywordBlockRule)
+				keywords.PUT("/blocklist/:id", handler.UpdateKeywordBlockRule)
+				keywords.DELETE("/blocklist/:id", handler.DeleteKeywordBlockRule)
+				keywords.POST("/blocklist/apply", handler.ApplyKeywordBlocklist)
+			}
+
+			// 关键词聚类（需要管理员权限）
+			keywordClusters := authenticated.Group("/keyword-clusters")
+			keywordClusters.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				keywordClusters.GET("", handler.GetKeywordClusters)
+				keywordClusters.POST("", handler.CreateKeywordCluster)
+				keywordClusters.POST("/rebuild", handler.RebuildKeywordClusters)
+				keywordClusters.GET("/:id", handler.GetKeywordCluster)
+				keywordClusters.PUT("/:id", handler.UpdateKeywordCluster)
+				keywordClusters.DELETE("/:id", handler.DeleteKeywordCluster)
+				keywordClusters.POST("/:id/keywords", handler.AddKeywordClusterKeywords)
+				keywordClusters.DELETE("/:id/keywords/:keyword_id", handler.RemoveKeywordClusterKeyword)
 			}
 
 			// 文章相关（需要编辑权限）
@@ -73,6 +120,105 @@ func SetupRouter(handler *Handler) *gin.Engine {
 				articles.PUT("/:id/publish", handler.PublishArticle)
 				articles.PUT("/:id/archive", handler.ArchiveArticle)
 				articles.DELETE("/:id", handler.DeleteArticle)
+				articles.GET("/:id/compliance", handler.GetArticleCompliance)
+				articles.POST("/:id/compliance/recheck", handler.RecheckArticleCompliance)
+				articles.GET("/:id/faqs", handler.GetArticleFAQs)
+				articles.PUT("/:id/faqs", handler.UpdateArticleFAQs)
+				articles.POST("/:id/faqs/extract", handler.ExtractArticleFAQs)
+			}
+
+			// 生成任务相关（需要编辑权限）
+			generationTasks := authenticated.Group("/generation-tasks")
+			generationTasks.Use(handler.authService.RoleMiddleware("admin", "editor"))
+			{
+				generationTasks.GET("/:id", handler.GetGenerationTask)
+				generationTasks.POST("/:id/resume", handler.ResumeGenerationTask)
+			}
+
+			// 提示模板（需要管理员权限）
+			prompts := authenticated.Group("/prompt-templates")
+			prompts.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				prompts.GET("", handler.GetPromptTemplates)
+				prompts.POST("", handler.CreatePromptTemplate)
+				prompts.GET("/:id", handler.GetPromptTemplate)
+				prompts.POST("/:id/versions", handler.CreatePromptTemplateVersion)
+				prompts.PUT("/:id/activate", handler.ActivatePromptTemplateVersion)
+				prompts.PUT("/:id/categories", handler.AssignPromptTemplateCategories)
+				prompts.POST("/:id/preview", handler.PreviewPromptTemplate)
+			}
+
+			// 提示模板实验（需要管理员权限）
+			experiments := authenticated.Group("/experiments")
+			experiments.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				experiments.GET("", handler.GetExperiments)
+				experiments.POST("", handler.CreateExperiment)
+				experiments.GET("/:id", handler.GetExperiment)
+				experiments.PUT("/:id/start", handler.StartExperiment)
+				experiments.PUT("/:id/stop", handler.StopExperiment)
+				experiments.GET("/:id/report", handler.GetExperimentReport)
+			}
+
+			// 搜索引擎URL提交（需要管理员权限）
+			submissions := authenticated.Group("/submissions")
+			submissions.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				submissions.GET("", handler.GetSubmissions)
+				submissions.GET("/quota", handler.GetSubmissionQuotas)
+				submissions.POST("/articles/:id", handler.SubmitArticleURL)
+			}
+
+			// robots.txt规则（需要管理员权限）
+			robots := authenticated.Group("/robots")
+			robots.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				robots.GET("/rules", handler.GetRobotsRules)
+				robots.POST("/rules", handler.CreateRobotsRule)
+				robots.PUT("/rules/:id", handler.UpdateRobotsRule)
+				robots.DELETE("/rules/:id", handler.DeleteRobotsRule)
+				robots.GET("/test", handler.TestRobotsURL)
+			}
+
+			// 自动内链（需要编辑权限）
+			links := authenticated.Group("/internal-links")
+			links.Use(handler.authService.RoleMiddleware("admin", "editor"))
+			{
+				links.GET("/articles/:id", handler.GetArticleLinks)
+				links.POST("/articles/:id", handler.RelinkArticle)
+				links.GET("/opt-outs", handler.GetLinkOptOuts)
+				links.POST("/opt-outs", handler.AddLinkOptOut)
+				links.DELETE("/opt-outs/:articleId", handler.RemoveLinkOptOut)
+			}
+
+			// 页面SEO审核（需要编辑权限）
+			audit := authenticated.Group("/seo/audit")
+			audit.Use(handler.authService.RoleMiddleware("admin", "editor"))
+			{
+				audit.POST("", handler.RunSiteAudit)
+				audit.GET("/reports", handler.GetAuditReports)
+				audit.GET("/reports/:id", handler.GetAuditReport)
+				audit.GET("/cannibalization", handler.GetCannibalizationReport)
+				audit.POST("/cannibalization/resolve", handler.ResolveCannibalization)
+				audit.GET("/:articleId", handler.AuditArticle)
+				audit.GET("/:articleId/history", handler.GetArticleAudits)
+			}
+
+			// 合规检查结果（需要编辑权限）
+			findings := authenticated.Group("/compliance/findings")
+			findings.Use(handler.authService.RoleMiddleware("admin", "editor"))
+			{
+				findings.PUT("/:id/resolve", handler.ResolveComplianceFinding)
+			}
+
+			// 合规规则（需要管理员权限）
+			rules := authenticated.Group("/compliance/rules")
+			rules.Use(handler.authService.RoleMiddleware("admin"))
+			{
+				rules.GET("", handler.GetComplianceRules)
+				rules.POST("", handler.CreateComplianceRule)
+				rules.PUT("/:id", handler.UpdateComplianceRule)
+				rules.DELETE("/:id", handler.DeleteComplianceRule)
 			}
 
 			// 任务相关（需要认证）
@@ -87,22 +233,26 @@ func SetupRouter(handler *Handler) *gin.Engine {
 			{
 				publicArticles.GET("", handler.GetArticles)
 				publicArticles.GET("/:id", handler.GetArticle)
+				publicArticles.GET("/:id/related", handler.GetRelatedArticles)
 				publicArticles.GET("/slug/:slug", handler.GetArticleBySlug)
 			}
 		}
 	}
 
 	// 前端页面路由
-	r.GET("/health/:slug", func(c *gin.Context) {
-		slug := c.Param("slug")
-		c.HTML(200, "article.html", gin.H{
-			"slug": slug,
-		})
-	})
+	r.GET("/health/:slug", handler.ArticlePage)
+
+	// 分类页
+	r.GET("/categories/:name", handler.CategoryPage)
+
+	// 搜索结果页
+	r.GET("/search", handler.SearchPage)
 
 	// 首页
 	r.GET("/", func(c *gin.Context) {
-		c.HTML(200, "index.html", gin.H{})
+		c.HTML(200, "index.html", gin.H{
+			"site_schema": handler.siteSchema(),
+		})
 	})
 
 	return r
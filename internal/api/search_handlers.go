@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search 全文搜索已发布文章
+func (h *Handler) Search(c *gin.Context) {
+	query := c.Query("q")
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "10")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 50 {
+		pageSize = 10
+	}
+
+	if query == "" {
+		Error(c, http.StatusBadRequest, "搜索词不能为空")
+		return
+	}
+
+	results, total, err := h.searchService.SearchArticles(query, page, pageSize)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "搜索失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    results,
+	})
+}
+
+// SearchPage 搜索结果页，结果由页面通过/api/search加载
+func (h *Handler) SearchPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "search.html", gin.H{
+		"query":            c.Query("q"),
+		"site_name":        h.config.SEO.SiteName,
+		"site_description": h.config.SEO.SiteDescription,
+	})
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/NietzscheX/seo-generate/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSitemap 获取Sitemap索引
+func (h *Handler) GetSitemap(c *gin.Context) {
+	h.serveSitemap(c, services.SitemapIndexName)
+}
+
+// GetSitemapFile 获取分页的文章、图片和分类Sitemap，文件名加.gz后缀时返回gzip压缩文件
+func (h *Handler) GetSitemapFile(c *gin.Context) {
+	h.serveSitemap(c, c.Param("file"))
+}
+
+// serveSitemap 输出Sitemap，客户端支持gzip时压缩传输
+func (h *Handler) serveSitemap(c *gin.Context, name string) {
+	gzipped := strings.HasSuffix(name, ".gz")
+	name = strings.TrimSuffix(name, ".gz")
+
+	file, err := h.sitemapService.Get(name)
+	if errors.Is(err, services.ErrSitemapNotFound) {
+		c.String(http.StatusNotFound, "404 page not found")
+		return
+	}
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "生成Sitemap失败: "+err.Error())
+		return
+	}
+
+	data := file.Data
+	switch {
+	case gzipped:
+		c.Header("Content-Type", "application/gzip")
+		data = file.Gzip
+	case strings.Contains(c.GetHeader("Accept-Encoding"), "gzip"):
+		c.Header("Content-Type", "application/xml; charset=utf-8")
+		c.Header("Content-Encoding", "gzip")
+		data = file.Gzip
+	default:
+		c.Header("Content-Type", "application/xml; charset=utf-8")
+	}
+	c.Header("Vary", "Accept-Encoding")
+
+	// 处理If-Modified-Since条件请求
+	http.ServeContent(c.Writer, c.Request, name, file.ModTime, bytes.NewReader(data))
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRootFile 获取站点根目录下的文件：IndexNow密钥文件或分页的Sitemap
+func (h *Handler) GetRootFile(c *gin.Context) {
+	if key := h.submissionService.IndexNowKey(); key != "" && c.Param("file") == key+".txt" {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.String(http.StatusOK, key)
+		return
+	}
+
+	h.GetSitemapFile(c)
+}
+
+// GetSubmissions 获取搜索引擎URL提交记录
+func (h *Handler) GetSubmissions(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+	articleIDStr := c.Query("article_id")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	var articleID uint
+	if articleIDStr != "" {
+		id, err := strconv.ParseUint(articleIDStr, 10, 32)
+		if err == nil {
+			articleID = uint(id)
+		}
+	}
+
+	submissions, total, err := h.submissionService.GetSubmissions(page, pageSize, c.Query("engine"), c.Query("status"), articleID)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取提交记录失败: "+err.Error())
+		return
+	}
+
+	Success(c, PaginationResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Items:    submissions,
+	})
+}
+
+// GetSubmissionQuotas 获取各搜索引擎当日的提交配额
+func (h *Handler) GetSubmissionQuotas(c *gin.Context) {
+	quotas, err := h.submissionService.GetQuotas()
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "获取提交配额失败: "+err.Error())
+		return
+	}
+
+	Success(c, quotas)
+}
+
+// SubmitArticleURL 手动将已发布文章的URL重新加入提交队列
+func (h *Handler) SubmitArticleURL(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		Error(c, http.StatusBadRequest, "无效的文章ID")
+		return
+	}
+
+	article, err := h.articleService.GetArticleByID(uint(id))
+	if err != nil {
+		Error(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	if article.Status != "published" {
+		Error(c, http.StatusBadRequest, "只能提交已发布的文章")
+		return
+	}
+
+	if err := h.submissionService.Enqueue(c.Request.Context(), article.ID, h.seoService.GenerateCanonicalURL(article.Slug), seo.SubmitActionUpdate); err != nil {
+		Error(c, http.StatusInternalServerError, "提交URL失败: "+err.Error())
+		return
+	}
+
+	Success(c, nil)
+}
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"github.com/NietzscheX/seo-generate/pkg/segment"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -16,6 +18,7 @@ type Category struct {
 	Children  []Category     `gorm:"foreignKey:ParentID" json:"children,omitempty"`
 	Keywords  []Keyword      `gorm:"many2many:category_keywords;" json:"keywords,omitempty"`
 	Articles  []Article      `gorm:"many2many:category_articles;" json:"articles,omitempty"`
+	Terms     []string       `gorm:"serializer:json" json:"terms"` // 分类词典，关键词包含这些词时建议归入该分类
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -23,53 +26,296 @@ type Category struct {
 
 // Keyword 关键词模型
 type Keyword struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Word         string         `gorm:"size:200;not null;uniqueIndex" json:"word"`
-	SearchVolume int            `gorm:"default:0" json:"search_volume"`
-	Categories   []Category     `gorm:"many2many:category_keywords;" json:"categories,omitempty"`
-	Articles     []Article      `gorm:"many2many:keyword_articles;" json:"articles,omitempty"`
-	Source       string         `gorm:"size:50;default:'5118'" json:"source"`
-	Status       string         `gorm:"size:20;default:'active'" json:"status"` // active, inactive, pending
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint                 `gorm:"primaryKey" json:"id"`
+	Word         string               `gorm:"size:200;not null;uniqueIndex" json:"word"`
+	SearchVolume int                  `gorm:"default:0" json:"search_volume"`
+	Categories   []Category           `gorm:"many2many:category_keywords;" json:"categories,omitempty"`
+	Articles     []Article            `gorm:"many2many:keyword_articles;" json:"articles,omitempty"`
+	Source       string               `gorm:"size:50;default:'5118'" json:"source"`
+	Status       string               `gorm:"size:20;default:'new';index" json:"status"`         // new, approved, queued, covered, refresh_due, blocked
+	Priority     float64              `gorm:"default:0;index" json:"priority"`                   // 按搜索量和优化难度计算的选题得分，0-100，与5118指标的机会得分不同
+	BlockReason  string               `gorm:"size:200;default:''" json:"block_reason,omitempty"` // 屏蔽原因，状态为blocked时有值
+	Attributions []KeywordAttribution `gorm:"foreignKey:KeywordID" json:"attributions,omitempty"`
+	ClusterID    *uint                `gorm:"index" json:"cluster_id"`
+	Intent       string               `gorm:"size:20;default:'';index" json:"intent"`        // informational, commercial, navigational, transactional
+	DomainIntent string               `gorm:"size:20;default:'';index" json:"domain_intent"` // symptom, recipe, exercise, theory，为空时表示通用内容
+	IntentSource string               `gorm:"size:20;default:''" json:"intent_source"`       // rule, llm, manual，manual不会被自动分类覆盖
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+// KeywordFetch 从关键词来源获取关键词的记录，同时用于统计各来源的当日配额
+type KeywordFetch struct {
+	ID              uint             `gorm:"primaryKey" json:"id"`
+	Source          string           `gorm:"size:50;not null;index:idx_keyword_fetch_source_created" json:"source"` // 5118, baidu_suggest, related_search, llm
+	Seed            string           `gorm:"size:200" json:"seed"`
+	Requested       int              `json:"requested"` // 请求获取的关键词数
+	APICalls        int              `json:"api_calls"` // 请求外部接口的次数
+	Returned        int              `json:"returned"`  // 来源返回的关键词数
+	Saved           int              `json:"saved"`     // 清洗后保存的关键词数
+	Created         int              `json:"created"`   // 其中新建的关键词数
+	Invalid         int              `json:"invalid"`   // 为空、过长或重复被清洗掉的关键词数
+	Blocked         int              `json:"blocked"`   // 命中屏蔽规则的关键词数
+	BlockedKeywords []BlockedKeyword `gorm:"serializer:json" json:"blocked_keywords,omitempty"`
+	Status          string           `gorm:"size:20;not null;index" json:"status"` // success, failed
+	Error           string           `gorm:"type:text" json:"error"`
+	UserID          *uint            `json:"user_id"`
+	CreatedAt       time.Time        `gorm:"index:idx_keyword_fetch_source_created" json:"created_at"`
+}
+
+// BlockedKeyword 获取关键词时命中屏蔽规则的关键词
+type BlockedKeyword struct {
+	Word   string `json:"word"`
+	RuleID uint   `json:"rule_id"`
+	Reason string `json:"reason"`
+}
+
+// KeywordBlockRule 关键词屏蔽规则模型，获取和导入关键词时命中规则的关键词标记为已屏蔽
+type KeywordBlockRule struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Pattern   string    `gorm:"size:200;not null;uniqueIndex:idx_keyword_block_rule" json:"pattern"`
+	MatchType string    `gorm:"size:20;not null;default:'contains';uniqueIndex:idx_keyword_block_rule" json:"match_type"` // exact, contains, regex
+	Category  string    `gorm:"size:20;not null;default:'custom';index" json:"category"`                                  // brand, competitor, adult, dangerous, custom
+	Enabled   bool      `gorm:"default:true" json:"enabled"`
+	Hits      int       `gorm:"default:0" json:"hits"` // 命中的关键词数
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// KeywordAttribution 关键词的来源记录，同一关键词可以被多个来源或多次获取发现
+type KeywordAttribution struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	KeywordID    uint      `gorm:"not null;uniqueIndex:idx_keyword_attribution_fetch" json:"keyword_id"`
+	FetchID      uint      `gorm:"not null;uniqueIndex:idx_keyword_attribution_fetch;index" json:"fetch_id"`
+	Source       string    `gorm:"size:50;not null;index" json:"source"`
+	Seed         string    `gorm:"size:200" json:"seed"`
+	Rank         int       `json:"rank"`          // 在来源返回结果中的位置，从1开始
+	SearchVolume int       `json:"search_volume"` // 来源返回的搜索量
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// KeywordCluster 关键词主题聚类，一个关键词最多属于一个聚类，以主关键词为目标生成一篇文章，其余关键词作为次要关键词
+type KeywordCluster struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Name             string    `gorm:"size:200;not null" json:"name"`
+	PrimaryKeywordID *uint     `gorm:"index" json:"primary_keyword_id"`
+	PrimaryKeyword   *Keyword  `gorm:"foreignKey:PrimaryKeywordID" json:"primary_keyword,omitempty"`
+	Keywords         []Keyword `gorm:"foreignKey:ClusterID" json:"keywords,omitempty"`
+	Method           string    `gorm:"size:20;not null" json:"method"`      // lexical, embedding, manual
+	Locked           bool      `gorm:"default:false" json:"locked"`         // 手动编辑过，重新聚类时保持不变
+	KeywordCount     int       `gorm:"->;-:migration" json:"keyword_count"` // 查询列表时统计
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// KeywordCategorySuggestion 关键词的分类建议，审核通过后关联到分类
+type KeywordCategorySuggestion struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	KeywordID  uint      `gorm:"not null;uniqueIndex:idx_keyword_category_suggestion" json:"keyword_id"`
+	Keyword    *Keyword  `gorm:"foreignKey:KeywordID" json:"keyword,omitempty"`
+	CategoryID uint      `gorm:"not null;uniqueIndex:idx_keyword_category_suggestion;index" json:"category_id"`
+	Category   *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Method     string    `gorm:"size:20;not null" json:"method"` // 置信度最高的方式：dictionary, similarity, llm
+	Confidence float64   `gorm:"index" json:"confidence"`        // 各方式综合后的置信度，0-1
+	Reason     string    `gorm:"size:500" json:"reason"`
+	Status     string    `gorm:"size:20;default:'pending';index" json:"status"` // pending, accepted, rejected
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// KeywordEmbedding 关键词的向量缓存，模型变化时重新计算
+type KeywordEmbedding struct {
+	KeywordID uint      `gorm:"primaryKey;autoIncrement:false" json:"keyword_id"`
+	Model     string    `gorm:"size:100;not null" json:"model"`
+	Vector    []float64 `gorm:"serializer:json" json:"vector"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TrendPoint 关键词指数趋势中的一个月
+type TrendPoint struct {
+	Month  string `json:"month"` // 2006-01
+	Volume int    `json:"volume"`
+}
+
+// KeywordMetric 关键词指标快照，每次从5118获取指标时新增一条，按获取时间构成指标历史
+type KeywordMetric struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	KeywordID     uint         `gorm:"not null;index:idx_keyword_metric_keyword_fetched" json:"keyword_id"`
+	Source        string       `gorm:"size:50;not null" json:"source"`
+	SearchVolume  int          `json:"search_volume"`
+	PCVolume      int          `json:"pc_volume"`
+	MobileVolume  int          `json:"mobile_volume"`
+	Competition   int          `json:"competition"`   // 竞价激烈程度，0-100
+	Difficulty    int          `json:"difficulty"`    // 自然排名优化难度，0-100
+	BidCompanies  int          `json:"bid_companies"` // 竞价公司数量
+	CPC           float64      `json:"cpc"`           // 竞价点击单价（元）
+	LongTailCount int          `json:"long_tail_count"`
+	Trend         []TrendPoint `gorm:"serializer:json" json:"trend"`     // 月度指数趋势，未获取时为空
+	Questions     []string     `gorm:"serializer:json" json:"questions"` // 相关问题，未获取时为空
+	Opportunity   float64      `gorm:"index" json:"opportunity"`         // 机会得分，0-100
+	FetchedAt     time.Time    `gorm:"index:idx_keyword_metric_keyword_fetched" json:"fetched_at"`
 }
 
 // Article 文章模型
 type Article struct {
-	ID          uint       `json:"id" gorm:"primarykey"`
-	Title       string     `json:"title" gorm:"not null"`
-	Slug        string     `json:"slug" gorm:"uniqueIndex"`
-	Content     string     `json:"content" gorm:"type:text"`
-	Summary     string     `json:"summary"`
-	MetaTitle   string     `json:"meta_title"`
-	MetaDesc    string     `json:"meta_desc"`
-	Status      string     `json:"status" gorm:"default:draft"`
-	ViewCount   int        `json:"view_count" gorm:"default:0"`
-	PublishedAt *time.Time `json:"published_at"`
-	UserID      *uint      `json:"user_id"`
-	User        *User      `json:"user,omitempty"`
-	Categories  []Category `json:"categories" gorm:"many2many:article_categories;"`
-	Keywords    []Keyword  `json:"keywords" gorm:"many2many:article_keywords;"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"index"`
+	ID           uint         `json:"id" gorm:"primarykey"`
+	Title        string       `json:"title" gorm:"not null"`
+	Slug         string       `json:"slug" gorm:"uniqueIndex"`
+	Content      string       `json:"content" gorm:"type:text"`
+	Summary      string       `json:"summary"`
+	MetaTitle    string       `json:"meta_title"`
+	MetaDesc     string       `json:"meta_desc"`
+	CanonicalURL string       `json:"canonical_url,omitempty" gorm:"size:500;default:''"` // 为空时使用文章自己的地址
+	Tags         []string     `json:"tags" gorm:"serializer:json"`
+	Status       string       `json:"status" gorm:"default:draft"`
+	ViewCount    int          `json:"view_count" gorm:"default:0"`
+	PublishedAt  *time.Time   `json:"published_at"`
+	UserID       *uint        `json:"user_id"`
+	User         *User        `json:"user,omitempty"`
+	Categories   []Category   `json:"categories" gorm:"many2many:article_categories;"`
+	Keywords     []Keyword    `json:"keywords" gorm:"many2many:article_keywords;"`
+	FAQs         []ArticleFAQ `json:"faqs,omitempty" gorm:"foreignKey:ArticleID"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// ArticleFAQ 文章常见问题模型
+type ArticleFAQ struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"index;not null" json:"article_id"`
+	Position  int       `gorm:"default:0" json:"position"`
+	Question  string    `gorm:"size:500;not null" json:"question"`
+	Answer    string    `gorm:"type:text;not null" json:"answer"`
+	Source    string    `gorm:"size:20;default:'generated'" json:"source"` // generated, extracted, manual
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ArticleRedirect 文章地址跳转模型，合并或替换文章后旧地址跳转到保留文章
+type ArticleRedirect struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FromSlug   string    `gorm:"size:255;uniqueIndex;not null" json:"from_slug"`
+	ToSlug     string    `gorm:"size:255;index;not null" json:"to_slug"`
+	StatusCode int       `gorm:"default:301" json:"status_code"`
+	ArticleID  *uint     `gorm:"index" json:"article_id"` // 被替换的文章
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // GenerationTask 内容生成任务模型
 type GenerationTask struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	KeywordID    uint           `json:"keyword_id"`
-	Keyword      Keyword        `gorm:"foreignKey:KeywordID" json:"keyword"`
-	Status       string         `gorm:"size:20;default:'pending'" json:"status"` // pending, processing, completed, failed
-	ArticleID    *uint          `json:"article_id"`
-	Article      *Article       `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
-	Prompt       string         `gorm:"type:text" json:"prompt"`
-	ErrorMessage string         `gorm:"type:text" json:"error_message"`
-	ModelUsed    string         `gorm:"size:50" json:"model_used"` // deepseek, ollama
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                  uint                     `gorm:"primaryKey" json:"id"`
+	KeywordID           uint                     `json:"keyword_id"`
+	Keyword             Keyword                  `gorm:"foreignKey:KeywordID" json:"keyword"`
+	Status              string                   `gorm:"size:20;default:'pending'" json:"status"` // pending, processing, completed, failed
+	ArticleID           *uint                    `json:"article_id"`
+	Article             *Article                 `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+	Prompt              string                   `gorm:"type:text" json:"prompt"`
+	ErrorMessage        string                   `gorm:"type:text" json:"error_message"`
+	ModelUsed           string                   `gorm:"size:50" json:"model_used"`            // deepseek, ollama
+	Mode                string                   `gorm:"size:20;default:'single'" json:"mode"` // single, pipeline
+	SystemPrompt        string                   `gorm:"type:text" json:"system_prompt"`
+	TemplateVersionID   *uint                    `gorm:"index" json:"template_version_id"`
+	TemplateVersion     *PromptTemplateVersion   `gorm:"foreignKey:TemplateVersionID" json:"template_version,omitempty"`
+	ExperimentVariantID *uint                    `gorm:"index" json:"experiment_variant_id"`
+	ExperimentVariant   *PromptExperimentVariant `gorm:"foreignKey:ExperimentVariantID" json:"experiment_variant,omitempty"`
+	CategoryIDs         []uint                   `gorm:"serializer:json" json:"category_ids"`
+	ClusterID           *uint                    `gorm:"index" json:"cluster_id"` // 以聚类为目标生成时，关键词为聚类的主关键词
+	StructuredOutput    bool                     `gorm:"default:false" json:"structured_output"`
+	Output              string                   `gorm:"type:text" json:"output,omitempty"` // 结构化输出通过校验的JSON
+	Sections            []GenerationSection      `gorm:"foreignKey:TaskID" json:"sections,omitempty"`
+	CreatedAt           time.Time                `json:"created_at"`
+	UpdatedAt           time.Time                `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt           `gorm:"index" json:"-"`
+}
+
+// GenerationSection 分段生成的中间结果模型
+type GenerationSection struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	TaskID            uint      `gorm:"index;not null" json:"task_id"`
+	Position          int       `json:"position"`
+	Kind              string    `gorm:"size:20;not null" json:"kind"` // outline, intro, section, conclusion
+	Heading           string    `gorm:"size:200" json:"heading"`
+	Content           string    `gorm:"type:text" json:"content"`
+	Status            string    `gorm:"size:20;default:'pending'" json:"status"` // pending, completed, failed
+	ErrorMessage      string    `gorm:"type:text" json:"error_message"`
+	ModelUsed         string    `gorm:"size:50" json:"model_used"`
+	TemplateVersionID *uint     `json:"template_version_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PromptTemplate 提示模板模型
+type PromptTemplate struct {
+	ID            uint                    `gorm:"primaryKey" json:"id"`
+	Name          string                  `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Kind          string                  `gorm:"size:20;not null;index" json:"kind"` // article, outline, section, intro, conclusion
+	Description   string                  `gorm:"size:500" json:"description"`
+	Intent        string                  `gorm:"size:20;default:'';index" json:"intent"` // 适用的关键词意图，可以是搜索意图或领域内容意图，为空时适用所有意图
+	IsDefault     bool                    `gorm:"default:false" json:"is_default"`        // 分类没有指定模板时使用，每种类型和意图各一个
+	ActiveVersion int                     `gorm:"default:1" json:"active_version"`
+	Categories    []Category              `gorm:"many2many:prompt_template_categories;" json:"categories,omitempty"`
+	Versions      []PromptTemplateVersion `gorm:"foreignKey:TemplateID" json:"versions,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// PromptTemplateVersion 提示模板版本模型，创建后不再修改
+type PromptTemplateVersion struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TemplateID   uint      `gorm:"not null;uniqueIndex:idx_template_version" json:"template_id"`
+	Version      int       `gorm:"not null;uniqueIndex:idx_template_version" json:"version"`
+	SystemPrompt string    `gorm:"type:text" json:"system_prompt"`
+	Body         string    `gorm:"type:text;not null" json:"body"` // Go text/template 模板
+	Note         string    `gorm:"size:500" json:"note"`
+	CreatedBy    *uint     `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PromptExperiment 提示模板A/B实验模型
+type PromptExperiment struct {
+	ID          uint                      `gorm:"primaryKey" json:"id"`
+	Name        string                    `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Kind        string                    `gorm:"size:20;not null;index" json:"kind"` // 参与实验的模板类型
+	Description string                    `gorm:"size:500" json:"description"`
+	Status      string                    `gorm:"size:20;default:'draft'" json:"status"` // draft, running, stopped
+	StartedAt   *time.Time                `json:"started_at"`
+	EndedAt     *time.Time                `json:"ended_at"`
+	Variants    []PromptExperimentVariant `gorm:"foreignKey:ExperimentID" json:"variants,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt            `gorm:"index" json:"-"`
+}
+
+// PromptExperimentVariant 实验分组模型
+type PromptExperimentVariant struct {
+	ID                uint                   `gorm:"primaryKey" json:"id"`
+	ExperimentID      uint                   `gorm:"index;not null" json:"experiment_id"`
+	Experiment        *PromptExperiment      `gorm:"foreignKey:ExperimentID" json:"experiment,omitempty"`
+	Name              string                 `gorm:"size:50;not null" json:"name"`
+	TemplateVersionID uint                   `gorm:"not null" json:"template_version_id"`
+	TemplateVersion   *PromptTemplateVersion `gorm:"foreignKey:TemplateVersionID" json:"template_version,omitempty"`
+	Weight            int                    `gorm:"default:1" json:"weight"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// ExperimentAssignment 文章所属实验分组及效果指标模型
+type ExperimentAssignment struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	ArticleID        uint       `gorm:"uniqueIndex;not null" json:"article_id"`
+	ExperimentID     uint       `gorm:"index;not null" json:"experiment_id"`
+	VariantID        uint       `gorm:"index;not null" json:"variant_id"`
+	GeneratedContent string     `gorm:"type:text" json:"-"` // 生成时的原始内容，用于计算编辑距离
+	QualityScore     float64    `json:"quality_score"`
+	EditDistance     int        `json:"edit_distance"`
+	EditRatio        float64    `json:"edit_ratio"` // 编辑距离占原文长度的比例
+	EditedAt         *time.Time `json:"edited_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // APILog API调用日志模型
@@ -84,19 +330,132 @@ type APILog struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// URLSubmission 搜索引擎URL提交记录，同时用于统计当日配额
+type URLSubmission struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ArticleID  uint      `gorm:"index" json:"article_id"`
+	Engine     string    `gorm:"size:20;not null;index:idx_url_submission_engine_created" json:"engine"` // baidu, indexnow, bing
+	URL        string    `gorm:"size:500;not null" json:"url"`
+	Action     string    `gorm:"size:20;not null" json:"action"`       // publish, update, delete
+	Status     string    `gorm:"size:20;not null;index" json:"status"` // success, failed, skipped
+	Attempt    int       `gorm:"default:1" json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Remaining  *int      `json:"remaining"` // 搜索引擎返回的当日剩余配额
+	Response   string    `gorm:"type:text" json:"response"`
+	Error      string    `gorm:"type:text" json:"error"`
+	CreatedAt  time.Time `gorm:"index:idx_url_submission_engine_created" json:"created_at"`
+}
+
+// RobotsRule robots.txt规则模型，同一UserAgent的规则组成一组
+type RobotsRule struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserAgent string    `gorm:"size:100;not null;index" json:"user_agent"` // *, Baiduspider, Googlebot, GPTBot等
+	Directive string    `gorm:"size:20;not null" json:"directive"`         // allow, disallow, crawl-delay
+	Value     string    `gorm:"size:500" json:"value"`
+	Position  int       `gorm:"default:0" json:"position"`
+	Enabled   bool      `gorm:"default:true" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ArticleLink 自动添加的站内链接模型
+type ArticleLink struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SourceID  uint      `gorm:"index;not null" json:"source_id"` // 包含链接的文章
+	TargetID  uint      `gorm:"index;not null" json:"target_id"` // 链接指向的文章
+	Keyword   string    `gorm:"size:200;not null" json:"keyword"`
+	URL       string    `gorm:"size:500;not null" json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LinkOptOut 不参与自动内链的文章，既不添加链接也不被链接
+type LinkOptOut struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"uniqueIndex;not null" json:"article_id"`
+	Reason    string    `gorm:"size:500" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SEOAuditReport 全站SEO审核报告模型
+type SEOAuditReport struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Pages        int            `json:"pages"`
+	AverageScore float64        `json:"average_score"`
+	IssueCounts  map[string]int `gorm:"serializer:json" json:"issue_counts"` // 各检查项有问题的页面数
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// SEOAuditIssue SEO审核发现的问题
+type SEOAuditIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"` // error, warning
+	Message  string `json:"message"`
+}
+
+// SEOAudit 文章SEO审核结果模型
+type SEOAudit struct {
+	ID               uint            `gorm:"primaryKey" json:"id"`
+	ReportID         *uint           `gorm:"index" json:"report_id"` // 为空表示单独审核
+	ArticleID        uint            `gorm:"index;not null" json:"article_id"`
+	Score            int             `json:"score"`
+	TitleWidth       int             `json:"title_width"`
+	DescWidth        int             `json:"desc_width"`
+	ContentLength    int             `json:"content_length"`
+	H2Count          int             `json:"h2_count"`
+	MissingKeywords  []string        `gorm:"serializer:json" json:"missing_keywords"`
+	InternalLinks    int             `json:"internal_links"`
+	InboundLinks     int             `json:"inbound_links"`
+	Images           int             `json:"images"`
+	ImagesWithoutAlt int             `json:"images_without_alt"`
+	Issues           []SEOAuditIssue `gorm:"serializer:json" json:"issues"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// ComplianceRule 内容合规规则模型
+type ComplianceRule struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Pattern     string         `gorm:"size:100;not null;uniqueIndex:idx_compliance_rules_active_pattern,where:deleted_at IS NULL" json:"pattern"`
+	Category    string         `gorm:"size:30;not null;index" json:"category"`          // ad_law, cure_claim, dosage, superstition
+	Severity    string         `gorm:"size:20;not null;default:'flag'" json:"severity"` // block, flag, rewrite
+	Replacement string         `gorm:"size:100" json:"replacement"`
+	Enabled     bool           `gorm:"default:true" json:"enabled"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ComplianceFinding 文章合规检查结果模型
+type ComplianceFinding struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ArticleID   uint      `gorm:"index;not null" json:"article_id"`
+	RuleID      uint      `gorm:"index" json:"rule_id"`
+	Field       string    `gorm:"size:20;default:'content'" json:"field"` // title, content, summary, meta_title, meta_desc, faq
+	Term        string    `gorm:"size:100;not null" json:"term"`
+	Category    string    `gorm:"size:30" json:"category"`
+	Severity    string    `gorm:"size:20" json:"severity"`
+	Offset      int       `json:"offset"`
+	Context     string    `gorm:"type:text" json:"context"`
+	Replacement string    `gorm:"size:100" json:"replacement"`
+	Resolved    bool      `gorm:"default:false" json:"resolved"`
+	ResolvedBy  *uint     `json:"resolved_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Username  string         `gorm:"size:50;not null;uniqueIndex" json:"username"`
-	Email     string         `gorm:"size:100;not null;uniqueIndex" json:"email"`
-	Password  string         `gorm:"size:100;not null" json:"-"`         // 不在JSON中返回密码
-	Role      string         `gorm:"size:20;default:'user'" json:"role"` // admin, editor, user
-	Active    bool           `gorm:"default:true" json:"active"`
-	LastLogin *time.Time     `json:"last_login"`
-	Tokens    []Token        `json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Username    string         `gorm:"size:50;not null;uniqueIndex" json:"username"`
+	DisplayName string         `gorm:"size:50" json:"display_name"` // 文章的公开署名，为空时以网站署名，不公开登录名
+	Email       string         `gorm:"size:100;not null;uniqueIndex" json:"email"`
+	Password    string         `gorm:"size:100;not null" json:"-"`         // 不在JSON中返回密码
+	Role        string         `gorm:"size:20;default:'user'" json:"role"` // admin, editor, user
+	Active      bool           `gorm:"default:true" json:"active"`
+	LastLogin   *time.Time     `json:"last_login"`
+	Tokens      []Token        `json:"-"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // Token 认证令牌模型
@@ -130,13 +489,173 @@ func (u *User) CheckPassword(password string) bool {
 
 // AutoMigrate 自动迁移数据库表结构
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := migrateKeywordPriority(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
 		&Category{},
 		&Keyword{},
+		&KeywordFetch{},
+		&KeywordBlockRule{},
+		&KeywordAttribution{},
+		&KeywordMetric{},
+		&KeywordCluster{},
+		&KeywordEmbedding{},
+		&KeywordCategorySuggestion{},
 		&Article{},
+		&ArticleRedirect{},
 		&GenerationTask{},
+		&GenerationSection{},
+		&PromptTemplate{},
+		&PromptTemplateVersion{},
+		&PromptExperiment{},
+		&PromptExperimentVariant{},
+		&ExperimentAssignment{},
+		&ArticleFAQ{},
 		&APILog{},
+		&URLSubmission{},
+		&RobotsRule{},
+		&ArticleLink{},
+		&LinkOptOut{},
+		&SEOAuditReport{},
+		&SEOAudit{},
+		&ComplianceRule{},
+		&ComplianceFinding{},
 		&User{},
 		&Token{},
-	)
+	); err != nil {
+		return err
+	}
+
+	if err := migrateSearch(db); err != nil {
+		return err
+	}
+	if err := migrateComplianceRulePattern(db); err != nil {
+		return err
+	}
+	return migrateKeywordStatus(db)
+}
+
+// migrateSearch 创建全文检索使用的tsvector列和GIN索引。分词在Go中进行，由后台批量建立索引；
+// 文本被修改时触发器清空检索向量，等待重新索引，保存时不需要额外查询
+func migrateSearch(db *gorm.DB) error {
+	statements := []string{
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector",
+		"CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector)",
+		"ALTER TABLE keywords ADD COLUMN IF NOT EXISTS search_vector tsvector",
+		"CREATE INDEX IF NOT EXISTS idx_keywords_search_vector ON keywords USING GIN (search_vector)",
+		`CREATE OR REPLACE FUNCTION reset_article_search_vector() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.title IS DISTINCT FROM OLD.title OR NEW.summary IS DISTINCT FROM OLD.summary OR NEW.content IS DISTINCT FROM OLD.content THEN
+				NEW.search_vector := NULL;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		"DROP TRIGGER IF EXISTS articles_reset_search_vector ON articles",
+		"CREATE TRIGGER articles_reset_search_vector BEFORE UPDATE ON articles FOR EACH ROW EXECUTE PROCEDURE reset_article_search_vector()",
+		`CREATE OR REPLACE FUNCTION reset_keyword_search_vector() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.word IS DISTINCT FROM OLD.word THEN
+				NEW.search_vector := NULL;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		"DROP TRIGGER IF EXISTS keywords_reset_search_vector ON keywords",
+		"CREATE TRIGGER keywords_reset_search_vector BEFORE UPDATE ON keywords FOR EACH ROW EXECUTE PROCEDURE reset_keyword_search_vector()",
+		"CREATE INDEX IF NOT EXISTS idx_articles_search_vector_missing ON articles (id) WHERE search_vector IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_keywords_search_vector_missing ON keywords (id) WHERE search_vector IS NULL",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateComplianceRulePattern 删除合规规则词条上包含已删除规则的旧唯一索引，改由只约束未删除规则的部分索引保证唯一
+func migrateComplianceRulePattern(db *gorm.DB) error {
+	return db.Exec("DROP INDEX IF EXISTS idx_compliance_rules_pattern").Error
+}
+
+// migrateKeywordPriority 将关键词旧的opportunity列改名为priority，保留已计算的选题得分；需在自动迁移前执行，避免新建空列
+func migrateKeywordPriority(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Keyword{}) || !migrator.HasColumn(&Keyword{}, "opportunity") || migrator.HasColumn(&Keyword{}, "priority") {
+		return nil
+	}
+	return migrator.RenameColumn(&Keyword{}, "opportunity", "priority")
+}
+
+// migrateKeywordStatus 将旧的关键词状态转换为生命周期状态：active为已审核，pending为新关键词，inactive为已屏蔽，
+// 已有发布文章或待发布草稿的关键词为已覆盖，只有已归档文章的重新审核；转换后不再有旧状态，重复执行不会修改数据
+func migrateKeywordStatus(db *gorm.DB) error {
+	statements := []string{
+		"UPDATE keywords SET status = 'blocked' WHERE status = 'inactive'",
+		"UPDATE keywords SET status = 'new' WHERE status = 'pending'",
+		`UPDATE keywords SET status = CASE
+			WHEN EXISTS (SELECT 1 FROM article_keywords JOIN articles ON articles.id = article_keywords.article_id
+				WHERE article_keywords.keyword_id = keywords.id AND articles.status IN ('published', 'draft')) THEN 'covered'
+			ELSE 'approved' END
+		WHERE status = 'active'`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateArticleSearchVectors 对文章分词后批量更新全文检索向量，标题权重为A，摘要为B，正文为C。
+// 读取后被修改过的文章（更新时间不同）跳过，由下一轮重新索引
+func UpdateArticleSearchVectors(db *gorm.DB, articles []Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(articles))
+	args := make([]interface{}, 0, len(articles)*5)
+	for _, article := range articles {
+		values = append(values, "(?::bigint, ?::timestamptz, ?::text, ?::text, ?::text)")
+		args = append(args,
+			article.ID,
+			article.UpdatedAt,
+			segment.SearchText(article.Title),
+			segment.SearchText(article.Summary),
+			segment.SearchText(article.Content),
+		)
+	}
+
+	return db.Exec(`UPDATE articles SET search_vector =
+		setweight(to_tsvector('simple', v.title), 'A') ||
+		setweight(to_tsvector('simple', v.summary), 'B') ||
+		setweight(to_tsvector('simple', v.content), 'C')
+		FROM (VALUES `+strings.Join(values, ", ")+`) AS v(id, updated_at, title, summary, content)
+		WHERE articles.id = v.id AND articles.updated_at = v.updated_at`,
+		args...,
+	).Error
+}
+
+// UpdateKeywordSearchVectors 对关键词分词后批量更新全文检索向量，读取后词语被修改的关键词跳过
+func UpdateKeywordSearchVectors(db *gorm.DB, keywords []Keyword) error {
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(keywords))
+	args := make([]interface{}, 0, len(keywords)*3)
+	for _, keyword := range keywords {
+		values = append(values, "(?::bigint, ?::text, ?::text)")
+		args = append(args, keyword.ID, keyword.Word, segment.SearchText(keyword.Word))
+	}
+
+	return db.Exec(`UPDATE keywords SET search_vector = to_tsvector('simple', v.text)
+		FROM (VALUES `+strings.Join(values, ", ")+`) AS v(id, word, text)
+		WHERE keywords.id = v.id AND keywords.word = v.word`,
+		args...,
+	).Error
 }
@@ -20,10 +20,20 @@ func NewArticleService(db *gorm.DB) *ArticleService {
 	}
 }
 
+// orderFAQs 按顺序加载常见问题
+func orderFAQs(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// selectAuthor 只加载作者的公开署名，不读取登录名、邮箱等账号信息
+func selectAuthor(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "display_name")
+}
+
 // GetArticleByID 根据ID获取文章
 func (s *ArticleService) GetArticleByID(id uint) (*models.Article, error) {
 	var article models.Article
-	if err := s.db.Preload("Keywords").Preload("Categories").First(&article, id).Error; err != nil {
+	if err := s.db.Preload("Keywords").Preload("Categories").Preload("FAQs", orderFAQs).First(&article, id).Error; err != nil {
 		return nil, fmt.Errorf("查询文章失败: %w", err)
 	}
 	return &article, nil
@@ -32,16 +42,25 @@ func (s *ArticleService) GetArticleByID(id uint) (*models.Article, error) {
 // GetArticleBySlug 根据Slug获取文章
 func (s *ArticleService) GetArticleBySlug(slug string) (*models.Article, error) {
 	var article models.Article
-	if err := s.db.Preload("Keywords").Preload("Categories").Where("slug = ?", slug).First(&article).Error; err != nil {
+	if err := s.db.Preload("Keywords").Preload("Categories").Preload("FAQs", orderFAQs).Preload("User", selectAuthor).Where("slug = ?", slug).First(&article).Error; err != nil {
 		return nil, fmt.Errorf("查询文章失败: %w", err)
 	}
 
-	// 更新浏览次数
-	s.db.Model(&article).Update("view_count", article.ViewCount+1)
+	// 更新浏览次数，不修改updated_at以免影响Sitemap的lastmod
+	s.db.Model(&article).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
 
 	return &article, nil
 }
 
+// GetRedirect 获取文章旧地址的跳转
+func (s *ArticleService) GetRedirect(slug string) (*models.ArticleRedirect, error) {
+	var redirect models.ArticleRedirect
+	if err := s.db.Where("from_slug = ?", slug).First(&redirect).Error; err != nil {
+		return nil, fmt.Errorf("查询跳转失败: %w", err)
+	}
+	return &redirect, nil
+}
+
 // GetArticles 获取文章列表
 func (s *ArticleService) GetArticles(page, pageSize int, categoryID *uint, status string) ([]models.Article, int64, error) {
 	var articles []models.Article
@@ -78,30 +97,25 @@ func (s *ArticleService) GetArticles(page, pageSize int, categoryID *uint, statu
 	return articles, total, nil
 }
 
-// SearchArticles 搜索文章
-func (s *ArticleService) SearchArticles(query string, page, pageSize int) ([]models.Article, int64, error) {
-	var articles []models.Article
-	var total int64
-
-	// 构建查询
-	dbQuery := s.db.Model(&models.Article{}).
-		Where("title LIKE ? OR content LIKE ?", "%"+query+"%", "%"+query+"%")
+// GetFeedArticles 获取订阅源中最新发布的文章，categoryID不为空时只返回该分类的文章
+func (s *ArticleService) GetFeedArticles(categoryID *uint, limit int) ([]models.Article, error) {
+	query := s.db.Model(&models.Article{}).Where("articles.status = ?", "published")
 
-	// 统计总数
-	if err := dbQuery.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("统计文章数量失败: %w", err)
+	// 按分类筛选
+	if categoryID != nil {
+		query = query.Joins("JOIN article_categories ON article_categories.article_id = articles.id").
+			Where("article_categories.category_id = ?", *categoryID)
 	}
 
-	// 分页查询
-	offset := (page - 1) * pageSize
-	if err := dbQuery.Preload("Keywords").Preload("Categories").
-		Order("created_at DESC").
-		Offset(offset).Limit(pageSize).
+	var articles []models.Article
+	if err := query.Preload("Categories").Preload("User", selectAuthor).
+		Order("articles.published_at DESC, articles.id DESC").
+		Limit(limit).
 		Find(&articles).Error; err != nil {
-		return nil, 0, fmt.Errorf("搜索文章失败: %w", err)
+		return nil, fmt.Errorf("查询订阅文章失败: %w", err)
 	}
 
-	return articles, total, nil
+	return articles, nil
 }
 
 // UpdateArticle 更新文章
@@ -162,15 +176,41 @@ func (s *ArticleService) PublishArticle(id uint) (*models.Article, error) {
 		return nil, fmt.Errorf("查询文章失败: %w", err)
 	}
 
+	// 存在未处理的阻止级合规问题时不允许发布
+	var blocking int64
+	if err := s.db.Model(&models.ComplianceFinding{}).
+		Where("article_id = ? AND severity = ? AND resolved = ?", id, "block", false).
+		Count(&blocking).Error; err != nil {
+		return nil, fmt.Errorf("检查合规问题失败: %w", err)
+	}
+	if blocking > 0 {
+		return nil, fmt.Errorf("文章存在%d处未处理的违规内容，请修改后再发布", blocking)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
 	// 设置发布状态和时间
 	now := time.Now()
 	article.Status = "published"
 	article.PublishedAt = &now
 
-	if err := s.db.Save(&article).Error; err != nil {
+	if err := tx.Save(&article).Error; err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("发布文章失败: %w", err)
 	}
 
+	// 重新发布被替换的文章时删除旧地址的跳转
+	if err := tx.Where("from_slug = ?", article.Slug).Delete(&models.ArticleRedirect{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("删除文章跳转失败: %w", err)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
 	return &article, nil
 }
 
@@ -208,6 +248,12 @@ func (s *ArticleService) DeleteArticle(id uint) error {
 		return fmt.Errorf("删除文章与分类的关联失败: %w", err)
 	}
 
+	// 删除文章的常见问题
+	if err := tx.Where("article_id = ?", id).Delete(&models.ArticleFAQ{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除文章的常见问题失败: %w", err)
+	}
+
 	// 删除文章
 	if err := tx.Delete(&models.Article{}, id).Error; err != nil {
 		tx.Rollback()
@@ -221,43 +267,3 @@ func (s *ArticleService) DeleteArticle(id uint) error {
 
 	return nil
 }
-
-// GetRelatedArticles 获取相关文章
-func (s *ArticleService) GetRelatedArticles(articleID uint, limit int) ([]models.Article, error) {
-	var article models.Article
-	if err := s.db.Preload("Keywords").First(&article, articleID).Error; err != nil {
-		return nil, fmt.Errorf("查询文章失败: %w", err)
-	}
-
-	// 如果文章没有关键词，返回最新文章
-	if len(article.Keywords) == 0 {
-		var articles []models.Article
-		if err := s.db.Where("id != ? AND status = ?", articleID, "published").
-			Order("published_at DESC").
-			Limit(limit).
-			Find(&articles).Error; err != nil {
-			return nil, fmt.Errorf("查询最新文章失败: %w", err)
-		}
-		return articles, nil
-	}
-
-	// 提取关键词ID
-	keywordIDs := make([]uint, len(article.Keywords))
-	for i, kw := range article.Keywords {
-		keywordIDs[i] = kw.ID
-	}
-
-	// 查询具有相同关键词的文章
-	var articles []models.Article
-	if err := s.db.Distinct("articles.*").
-		Joins("JOIN keyword_articles ON keyword_articles.article_id = articles.id").
-		Where("articles.id != ? AND articles.status = ? AND keyword_articles.keyword_id IN ?",
-			articleID, "published", keywordIDs).
-		Order("articles.published_at DESC").
-		Limit(limit).
-		Find(&articles).Error; err != nil {
-		return nil, fmt.Errorf("查询相关文章失败: %w", err)
-	}
-
-	return articles, nil
-}
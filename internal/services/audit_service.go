@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// SEOAuditReportDetail 全站审核报告及各文章的审核结果
+type SEOAuditReportDetail struct {
+	Report *models.SEOAuditReport `json:"report"`
+	Audits []models.SEOAudit      `json:"audits"`
+}
+
+// AuditService SEO审核服务
+type AuditService struct {
+	db         *gorm.DB
+	seoService *seo.SEOService
+}
+
+// NewAuditService 创建SEO审核服务
+func NewAuditService(db *gorm.DB, seoService *seo.SEOService) *AuditService {
+	return &AuditService{
+		db:         db,
+		seoService: seoService,
+	}
+}
+
+// loadPages 加载所有已发布文章作为审核页面
+func (s *AuditService) loadPages() ([]seo.AuditPage, error) {
+	var articles []models.Article
+	if err := s.db.Preload("Keywords").Where("status = ?", "published").Order("id").Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("查询已发布文章失败: %w", err)
+	}
+
+	pages := make([]seo.AuditPage, 0, len(articles))
+	for i := range articles {
+		pages = append(pages, auditPage(&articles[i]))
+	}
+	return pages, nil
+}
+
+// auditPage 将文章转换为审核页面
+func auditPage(article *models.Article) seo.AuditPage {
+	keywords := make([]string, 0, len(article.Keywords))
+	for _, keyword := range article.Keywords {
+		keywords = append(keywords, keyword.Word)
+	}
+
+	return seo.AuditPage{
+		ArticleID: article.ID,
+		Slug:      article.Slug,
+		Title:     article.Title,
+		MetaTitle: article.MetaTitle,
+		MetaDesc:  article.MetaDesc,
+		Content:   article.Content,
+		Keywords:  keywords,
+	}
+}
+
+// auditRecord 将审核结果转换为数据库记录
+func auditRecord(result seo.PageAudit, reportID *uint) models.SEOAudit {
+	issues := make([]models.SEOAuditIssue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		issues = append(issues, models.SEOAuditIssue{
+			Check:    issue.Check,
+			Severity: issue.Severity,
+			Message:  issue.Message,
+		})
+	}
+
+	return models.SEOAudit{
+		ReportID:         reportID,
+		ArticleID:        result.ArticleID,
+		Score:            result.Score,
+		TitleWidth:       result.TitleWidth,
+		DescWidth:        result.DescWidth,
+		ContentLength:    result.ContentLength,
+		H2Count:          result.H2Count,
+		MissingKeywords:  result.MissingKeywords,
+		InternalLinks:    result.InternalLinks,
+		InboundLinks:     result.InboundLinks,
+		Images:           result.Images,
+		ImagesWithoutAlt: result.ImagesWithoutAlt,
+		Issues:           issues,
+	}
+}
+
+// AuditArticle 审核单篇文章并保存结果，重复标题和入站链接与已发布文章对比
+func (s *AuditService) AuditArticle(articleID uint) (*models.SEOAudit, error) {
+	var article models.Article
+	if err := s.db.Preload("Keywords").First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	pages, err := s.loadPages()
+	if err != nil {
+		return nil, err
+	}
+
+	// 未发布的文章也参与对比
+	if article.Status != "published" {
+		pages = append(pages, auditPage(&article))
+	}
+
+	site := s.seoService.AuditSite(pages)
+	for _, result := range site.Pages {
+		if result.ArticleID != article.ID {
+			continue
+		}
+
+		record := auditRecord(result, nil)
+		if err := s.db.Create(&record).Error; err != nil {
+			return nil, fmt.Errorf("保存审核结果失败: %w", err)
+		}
+		return &record, nil
+	}
+
+	return nil, fmt.Errorf("审核文章失败: 文章%d不在审核结果中", article.ID)
+}
+
+// RunSiteAudit 审核所有已发布文章并保存报告
+func (s *AuditService) RunSiteAudit() (*SEOAuditReportDetail, error) {
+	pages, err := s.loadPages()
+	if err != nil {
+		return nil, err
+	}
+
+	site := s.seoService.AuditSite(pages)
+	report := models.SEOAuditReport{
+		Pages:        len(site.Pages),
+		AverageScore: site.AverageScore,
+		IssueCounts:  site.IssueCounts,
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Create(&report).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("保存审核报告失败: %w", err)
+	}
+
+	audits := make([]models.SEOAudit, 0, len(site.Pages))
+	for _, result := range site.Pages {
+		audits = append(audits, auditRecord(result, &report.ID))
+	}
+	if len(audits) > 0 {
+		if err := tx.CreateInBatches(&audits, 100).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存审核结果失败: %w", err)
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return &SEOAuditReportDetail{Report: &report, Audits: audits}, nil
+}
+
+// GetArticleAudits 获取文章的历史审核结果
+func (s *AuditService) GetArticleAudits(articleID uint, page, pageSize int) ([]models.SEOAudit, int64, error) {
+	var audits []models.SEOAudit
+	var total int64
+
+	query := s.db.Model(&models.SEOAudit{}).Where("article_id = ?", articleID)
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计审核结果数量失败: %w", err)
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(pageSize).
+		Find(&audits).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询审核结果失败: %w", err)
+	}
+
+	return audits, total, nil
+}
+
+// GetReports 获取全站审核报告列表
+func (s *AuditService) GetReports(page, pageSize int) ([]models.SEOAuditReport, int64, error) {
+	var reports []models.SEOAuditReport
+	var total int64
+
+	query := s.db.Model(&models.SEOAuditReport{})
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计审核报告数量失败: %w", err)
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(pageSize).
+		Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询审核报告失败: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// GetReport 获取全站审核报告，得分低的文章排在前面
+func (s *AuditService) GetReport(id uint) (*SEOAuditReportDetail, error) {
+	var report models.SEOAuditReport
+	if err := s.db.First(&report, id).Error; err != nil {
+		return nil, fmt.Errorf("查询审核报告失败: %w", err)
+	}
+
+	var audits []models.SEOAudit
+	if err := s.db.Where("report_id = ?", report.ID).Order("score ASC, article_id ASC").Find(&audits).Error; err != nil {
+		return nil, fmt.Errorf("查询审核结果失败: %w", err)
+	}
+
+	return &SEOAuditReportDetail{Report: &report, Audits: audits}, nil
+}
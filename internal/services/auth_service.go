@@ -28,9 +28,10 @@ func NewAuthService(db *gorm.DB, cfg *config.Config) *AuthService {
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=50"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Username    string `json:"username" binding:"required,min=3,max=50"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,min=6"`
+	DisplayName string `json:"display_name" binding:"max=50"` // 文章的公开署名
 }
 
 // LoginRequest 登录请求
@@ -69,10 +70,11 @@ func (s *AuthService) Register(req RegisterRequest) (*models.User, error) {
 
 	// 创建新用户
 	user := models.User{
-		Username: req.Username,
-		Email:    req.Email,
-		Role:     "user", // 默认角色
-		Active:   true,
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Role:        "user", // 默认角色
+		Active:      true,
 	}
 
 	// 设置密码
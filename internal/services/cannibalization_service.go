@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidCannibalResolution 关键词蚕食处理参数无效
+var ErrInvalidCannibalResolution = errors.New("无效的关键词蚕食处理")
+
+// CannibalResolution 关键词蚕食的处理结果
+type CannibalResolution struct {
+	Action   string                  `json:"action"`
+	Loser    *models.Article         `json:"loser"`
+	Winner   *models.Article         `json:"winner"`
+	Redirect *models.ArticleRedirect `json:"redirect,omitempty"`
+}
+
+// GetCannibalizationReport 找出争夺相同关键词的已发布文章，规范URL已指向其他文章的不再参与检查
+func (s *AuditService) GetCannibalizationReport() ([]seo.CannibalGroup, error) {
+	var articles []models.Article
+	if err := s.db.Preload("Keywords").
+		Where("status = ? AND canonical_url = ''", "published").
+		Order("id").
+		Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("查询已发布文章失败: %w", err)
+	}
+
+	pages := make([]seo.CannibalPage, 0, len(articles))
+	for _, article := range articles {
+		page := seo.CannibalPage{
+			ArticleID:   article.ID,
+			Slug:        article.Slug,
+			Title:       article.Title,
+			Content:     article.Content,
+			ViewCount:   article.ViewCount,
+			PublishedAt: article.CreatedAt,
+		}
+		if article.PublishedAt != nil {
+			page.PublishedAt = *article.PublishedAt
+		}
+		for _, keyword := range article.Keywords {
+			page.Keywords = append(page.Keywords, seo.CannibalKeyword{
+				ID:        keyword.ID,
+				Word:      keyword.Word,
+				ClusterID: keyword.ClusterID,
+			})
+		}
+		pages = append(pages, page)
+	}
+
+	return seo.FindCannibalization(pages), nil
+}
+
+// ResolveCannibalization 一键处理关键词蚕食：canonical将被替换文章的规范URL指向保留文章；
+// redirect（内容合并后同样使用）将被替换文章的地址301跳转到保留文章，关键词转给保留文章后归档被替换文章
+func (s *AuditService) ResolveCannibalization(loserID, winnerID uint, action string) (*CannibalResolution, error) {
+	if loserID == winnerID {
+		return nil, fmt.Errorf("%w: 保留文章和被替换文章不能相同", ErrInvalidCannibalResolution)
+	}
+	if action != seo.CannibalActionCanonical && action != seo.CannibalActionRedirect {
+		return nil, fmt.Errorf("%w: 不支持的处理方式%s", ErrInvalidCannibalResolution, action)
+	}
+
+	var loser, winner models.Article
+	if err := s.db.First(&loser, loserID).Error; err != nil {
+		return nil, fmt.Errorf("查询被替换文章失败: %w", err)
+	}
+	if err := s.db.First(&winner, winnerID).Error; err != nil {
+		return nil, fmt.Errorf("查询保留文章失败: %w", err)
+	}
+	if winner.Status != "published" {
+		return nil, fmt.Errorf("%w: 保留文章尚未发布", ErrInvalidCannibalResolution)
+	}
+	if winner.CanonicalURL != "" {
+		return nil, fmt.Errorf("%w: 保留文章的规范URL指向其他文章", ErrInvalidCannibalResolution)
+	}
+
+	result := &CannibalResolution{Action: action, Loser: &loser, Winner: &winner}
+	winnerURL := s.seoService.GenerateCanonicalURL(winner.Slug)
+	loserURL := s.seoService.GenerateCanonicalURL(loser.Slug)
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	// 规范URL指向被替换文章的文章改为指向保留文章
+	if err := tx.Model(&models.Article{}).
+		Where("canonical_url = ? AND id <> ?", loserURL, winner.ID).
+		Update("canonical_url", winnerURL).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新规范URL失败: %w", err)
+	}
+
+	if action == seo.CannibalActionCanonical {
+		if err := tx.Model(&loser).Update("canonical_url", winnerURL).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("设置规范URL失败: %w", err)
+		}
+	} else {
+		redirect, err := redirectArticle(tx, &loser, &winner)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		result.Redirect = redirect
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// redirectArticle 被替换文章的地址301跳转到保留文章，已有指向被替换文章的跳转改为直接指向保留文章，
+// 关键词转给保留文章后归档被替换文章
+func redirectArticle(tx *gorm.DB, loser, winner *models.Article) (*models.ArticleRedirect, error) {
+	redirect := &models.ArticleRedirect{
+		FromSlug:   loser.Slug,
+		ToSlug:     winner.Slug,
+		StatusCode: 301,
+		ArticleID:  &loser.ID,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"to_slug", "status_code", "article_id", "updated_at"}),
+	}).Create(redirect).Error; err != nil {
+		return nil, fmt.Errorf("创建跳转失败: %w", err)
+	}
+
+	// 避免跳转链和跳转循环
+	if err := tx.Model(&models.ArticleRedirect{}).
+		Where("to_slug = ?", loser.Slug).
+		Updates(map[string]interface{}{"to_slug": winner.Slug, "updated_at": time.Now()}).Error; err != nil {
+		return nil, fmt.Errorf("更新跳转失败: %w", err)
+	}
+	if err := tx.Where("from_slug = ?", winner.Slug).Delete(&models.ArticleRedirect{}).Error; err != nil {
+		return nil, fmt.Errorf("删除跳转失败: %w", err)
+	}
+
+	// 被替换文章的关键词由保留文章覆盖
+	if err := tx.Exec("INSERT INTO article_keywords (article_id, keyword_id) "+
+		"SELECT ?, keyword_id FROM article_keywords WHERE article_id = ? ON CONFLICT DO NOTHING", winner.ID, loser.ID).Error; err != nil {
+		return nil, fmt.Errorf("转移文章关键词失败: %w", err)
+	}
+
+	loser.Status = "archived"
+	loser.CanonicalURL = ""
+	if err := tx.Model(loser).Updates(map[string]interface{}{"status": loser.Status, "canonical_url": loser.CanonicalURL}).Error; err != nil {
+		return nil, fmt.Errorf("归档文章失败: %w", err)
+	}
+
+	return redirect, nil
+}
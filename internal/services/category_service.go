@@ -2,11 +2,29 @@ package services
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/NietzscheX/seo-generate/internal/models"
 	"gorm.io/gorm"
 )
 
+// defaultCategoryTerms 默认分类的词典，用于自动建议关键词分类
+var defaultCategoryTerms = map[string][]string{
+	"阴阳五行": {"阴阳", "五行", "相生", "相克"},
+	"脏腑经络": {"脏腑", "经络", "经脉", "穴位", "肝", "脾", "肾", "心经", "肺经"},
+	"气血津液": {"气血", "气虚", "血虚", "津液", "补气", "补血"},
+	"病因病机": {"病因", "病机", "湿气", "寒气", "上火", "体质"},
+	"饮食养生": {"食疗", "食谱", "药膳", "汤", "粥", "茶", "吃什么", "做法"},
+	"运动养生": {"运动", "锻炼", "散步", "跑步", "拉伸"},
+	"起居养生": {"睡眠", "失眠", "熬夜", "作息", "泡脚"},
+	"情志养生": {"情绪", "焦虑", "抑郁", "压力", "心情"},
+	"四季养生": {"春季", "夏季", "秋季", "冬季", "春天", "夏天", "秋天", "冬天", "节气", "三伏"},
+	"冥想打坐": {"冥想", "打坐", "静坐", "禅修", "正念"},
+	"气功导引": {"气功", "导引", "八段锦", "五禽戏", "易筋经", "六字诀", "站桩"},
+	"太极拳法": {"太极", "太极拳"},
+	"心性修炼": {"心性", "修心", "修身养性", "静心"},
+}
+
 // CategoryService 分类服务
 type CategoryService struct {
 	db *gorm.DB
@@ -42,6 +60,15 @@ func (s *CategoryService) GetCategoryByID(id uint) (*models.Category, error) {
 	return &category, nil
 }
 
+// GetCategoryByName 根据名称获取分类
+func (s *CategoryService) GetCategoryByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := s.db.Where("name = ?", name).First(&category).Error; err != nil {
+		return nil, fmt.Errorf("查询分类失败: %w", err)
+	}
+	return &category, nil
+}
+
 // GetCategoryWithChildren 获取分类及其子分类
 func (s *CategoryService) GetCategoryWithChildren(id uint) (*models.Category, error) {
 	var category models.Category
@@ -51,6 +78,27 @@ func (s *CategoryService) GetCategoryWithChildren(id uint) (*models.Category, er
 	return &category, nil
 }
 
+// GetCategoryPath 获取从根分类到指定分类的路径
+func (s *CategoryService) GetCategoryPath(id uint) ([]models.Category, error) {
+	var path []models.Category
+	visited := make(map[uint]bool)
+
+	current := &id
+	for current != nil && !visited[*current] {
+		visited[*current] = true
+
+		var category models.Category
+		if err := s.db.First(&category, *current).Error; err != nil {
+			return nil, fmt.Errorf("查询分类失败: %w", err)
+		}
+
+		path = append([]models.Category{category}, path...)
+		current = category.ParentID
+	}
+
+	return path, nil
+}
+
 // GetAllCategories 获取所有分类
 func (s *CategoryService) GetAllCategories() ([]models.Category, error) {
 	var categories []models.Category
@@ -142,6 +190,32 @@ func (s *CategoryService) UpdateCategory(id uint, name string, parentID *uint) (
 	return &category, nil
 }
 
+// SetCategoryTerms 设置分类词典，去掉空白和重复的词
+func (s *CategoryService) SetCategoryTerms(id uint, terms []string) (*models.Category, error) {
+	var category models.Category
+	if err := s.db.First(&category, id).Error; err != nil {
+		return nil, fmt.Errorf("查询分类失败: %w", err)
+	}
+
+	seen := make(map[string]bool, len(terms))
+	cleaned := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		cleaned = append(cleaned, term)
+	}
+
+	category.Terms = cleaned
+	if err := s.db.Model(&category).Select("terms").Updates(&category).Error; err != nil {
+		return nil, fmt.Errorf("更新分类词典失败: %w", err)
+	}
+
+	return &category, nil
+}
+
 // DeleteCategory 删除分类
 func (s *CategoryService) DeleteCategory(id uint) error {
 	// 检查是否有子分类
@@ -169,6 +243,12 @@ func (s *CategoryService) DeleteCategory(id uint) error {
 		return fmt.Errorf("删除分类与文章的关联失败: %w", err)
 	}
 
+	// 删除关键词的分类建议
+	if err := tx.Where("category_id = ?", id).Delete(&models.KeywordCategorySuggestion{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除分类建议失败: %w", err)
+	}
+
 	// 删除分类
 	if err := tx.Delete(&models.Category{}, id).Error; err != nil {
 		tx.Rollback()
@@ -248,6 +328,7 @@ func (s *CategoryService) InitDefaultCategories() error {
 			childCat := models.Category{
 				Name:     childName,
 				ParentID: &parentCat.ID,
+				Terms:    defaultCategoryTerms[childName],
 			}
 
 			if err := tx.Create(&childCat).Error; err != nil {
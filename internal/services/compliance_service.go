@@ -0,0 +1,349 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/compliance"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidComplianceRule 合规规则无效
+var ErrInvalidComplianceRule = errors.New("无效的合规规则")
+
+// ErrComplianceRuleExists 已有相同词条的合规规则，词条在未删除的规则中唯一
+var ErrComplianceRuleExists = errors.New("规则已存在")
+
+// ComplianceService 内容合规服务
+type ComplianceService struct {
+	db     *gorm.DB
+	mu     sync.RWMutex
+	engine *compliance.Engine
+}
+
+// NewComplianceService 创建内容合规服务
+func NewComplianceService(db *gorm.DB) *ComplianceService {
+	return &ComplianceService{
+		db: db,
+	}
+}
+
+// InitDefaultRules 初始化默认合规规则
+func (s *ComplianceService) InitDefaultRules() error {
+	// 检查是否已有规则
+	var count int64
+	if err := s.db.Model(&models.ComplianceRule{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("检查合规规则数量失败: %w", err)
+	}
+
+	if count > 0 {
+		return nil // 已有规则，不需要初始化
+	}
+
+	defaults := compliance.DefaultRules()
+	rules := make([]models.ComplianceRule, 0, len(defaults))
+	for _, rule := range defaults {
+		rules = append(rules, models.ComplianceRule{
+			Pattern:     rule.Pattern,
+			Category:    rule.Category,
+			Severity:    string(rule.Severity),
+			Replacement: rule.Replacement,
+			Enabled:     true,
+		})
+	}
+
+	if err := s.db.Create(&rules).Error; err != nil {
+		return fmt.Errorf("创建默认合规规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// getEngine 获取规则引擎，规则变更后重新构建
+func (s *ComplianceService) getEngine() (*compliance.Engine, error) {
+	s.mu.RLock()
+	engine := s.engine
+	s.mu.RUnlock()
+	if engine != nil {
+		return engine, nil
+	}
+
+	var rules []models.ComplianceRule
+	if err := s.db.Where("enabled = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询合规规则失败: %w", err)
+	}
+
+	engineRules := make([]compliance.Rule, 0, len(rules))
+	for _, rule := range rules {
+		engineRules = append(engineRules, compliance.Rule{
+			ID:          rule.ID,
+			Pattern:     rule.Pattern,
+			Category:    rule.Category,
+			Severity:    compliance.Severity(rule.Severity),
+			Replacement: rule.Replacement,
+		})
+	}
+
+	engine = compliance.NewEngine(engineRules)
+
+	s.mu.Lock()
+	s.engine = engine
+	s.mu.Unlock()
+
+	return engine, nil
+}
+
+// invalidate 清除已构建的规则引擎
+func (s *ComplianceService) invalidate() {
+	s.mu.Lock()
+	s.engine = nil
+	s.mu.Unlock()
+}
+
+// Apply 检查内容并执行自动改写
+func (s *ComplianceService) Apply(content string) (string, []compliance.Finding, error) {
+	engine, err := s.getEngine()
+	if err != nil {
+		return content, nil, err
+	}
+
+	content, findings := engine.Apply(content)
+	return content, findings, nil
+}
+
+// Check 检查内容，不做改写
+func (s *ComplianceService) Check(content string) ([]compliance.Finding, error) {
+	engine, err := s.getEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	return engine.Check(content), nil
+}
+
+// SaveFindings 保存文章指定字段的检查结果，替换该字段的全部旧结果。rewritten表示内容已按改写规则改写，
+// 此时改写类问题无需人工复核；人工标记为已处理的问题在词条仍存在时保持已处理
+func (s *ComplianceService) SaveFindings(tx *gorm.DB, articleID uint, field string, findings []compliance.Finding, rewritten bool) error {
+	var resolved []models.ComplianceFinding
+	if err := tx.Where("article_id = ? AND field = ? AND resolved_by IS NOT NULL", articleID, field).
+		Find(&resolved).Error; err != nil {
+		return fmt.Errorf("查询已处理的合规检查结果失败: %w", err)
+	}
+	resolvedBy := make(map[string]*uint, len(resolved))
+	for _, finding := range resolved {
+		resolvedBy[fmt.Sprintf("%d:%s", finding.RuleID, finding.Term)] = finding.ResolvedBy
+	}
+
+	if err := tx.Where("article_id = ? AND field = ?", articleID, field).
+		Delete(&models.ComplianceFinding{}).Error; err != nil {
+		return fmt.Errorf("清除旧的合规检查结果失败: %w", err)
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	records := make([]models.ComplianceFinding, 0, len(findings))
+	for _, finding := range findings {
+		record := models.ComplianceFinding{
+			ArticleID:   articleID,
+			RuleID:      finding.RuleID,
+			Field:       field,
+			Term:        finding.Term,
+			Category:    finding.Category,
+			Severity:    string(finding.Severity),
+			Offset:      finding.Offset,
+			Context:     finding.Context,
+			Replacement: finding.Replacement,
+			// 自动改写的内容已处理，无需人工复核
+			Resolved: rewritten && finding.Severity == compliance.SeverityRewrite,
+		}
+		if userID, ok := resolvedBy[fmt.Sprintf("%d:%s", finding.RuleID, finding.Term)]; ok {
+			record.Resolved = true
+			record.ResolvedBy = userID
+		}
+		records = append(records, record)
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return fmt.Errorf("保存合规检查结果失败: %w", err)
+	}
+
+	return nil
+}
+
+// ReviewArticle 重新检查文章并保存结果，只检查不改写，改写类问题需要人工修改
+func (s *ComplianceService) ReviewArticle(articleID uint) ([]models.ComplianceFinding, error) {
+	var article models.Article
+	if err := s.db.Preload("FAQs").First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	faqs := make([]string, 0, len(article.FAQs)*2)
+	for _, faq := range article.FAQs {
+		faqs = append(faqs, faq.Question, faq.Answer)
+	}
+
+	fields := map[string]string{
+		"title":      article.Title,
+		"summary":    article.Summary,
+		"content":    article.Content,
+		"meta_title": article.MetaTitle,
+		"meta_desc":  article.MetaDesc,
+		"faq":        strings.Join(faqs, "\n"),
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	for field, text := range fields {
+		findings, err := s.Check(text)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := s.SaveFindings(tx, articleID, field, findings, false); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetArticleFindings(articleID)
+}
+
+// GetArticleFindings 获取文章的合规检查结果
+func (s *ComplianceService) GetArticleFindings(articleID uint) ([]models.ComplianceFinding, error) {
+	var findings []models.ComplianceFinding
+	if err := s.db.Where("article_id = ?", articleID).
+		Order("resolved ASC, severity ASC, id ASC").
+		Find(&findings).Error; err != nil {
+		return nil, fmt.Errorf("查询合规检查结果失败: %w", err)
+	}
+	return findings, nil
+}
+
+// ResolveFinding 将检查结果标记为已处理
+func (s *ComplianceService) ResolveFinding(id uint, userID uint) (*models.ComplianceFinding, error) {
+	var finding models.ComplianceFinding
+	if err := s.db.First(&finding, id).Error; err != nil {
+		return nil, fmt.Errorf("查询合规检查结果失败: %w", err)
+	}
+
+	finding.Resolved = true
+	finding.ResolvedBy = &userID
+
+	if err := s.db.Save(&finding).Error; err != nil {
+		return nil, fmt.Errorf("更新合规检查结果失败: %w", err)
+	}
+
+	return &finding, nil
+}
+
+// GetRules 获取合规规则列表
+func (s *ComplianceService) GetRules(category string) ([]models.ComplianceRule, error) {
+	var rules []models.ComplianceRule
+
+	query := s.db.Model(&models.ComplianceRule{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	if err := query.Order("category, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询合规规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule 创建合规规则
+func (s *ComplianceService) CreateRule(rule models.ComplianceRule) (*models.ComplianceRule, error) {
+	if err := validateComplianceRule(&rule); err != nil {
+		return nil, err
+	}
+	if err := s.checkRulePattern(rule.Pattern, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("创建合规规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return &rule, nil
+}
+
+// UpdateRule 更新合规规则
+func (s *ComplianceService) UpdateRule(id uint, update models.ComplianceRule) (*models.ComplianceRule, error) {
+	var rule models.ComplianceRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		return nil, fmt.Errorf("查询合规规则失败: %w", err)
+	}
+
+	if err := validateComplianceRule(&update); err != nil {
+		return nil, err
+	}
+	if err := s.checkRulePattern(update.Pattern, rule.ID); err != nil {
+		return nil, err
+	}
+
+	rule.Pattern = update.Pattern
+	rule.Category = update.Category
+	rule.Severity = update.Severity
+	rule.Replacement = update.Replacement
+	rule.Enabled = update.Enabled
+
+	if err := s.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("更新合规规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return &rule, nil
+}
+
+// checkRulePattern 检查词条是否已被其他未删除的规则使用，excludeID为正在更新的规则
+func (s *ComplianceService) checkRulePattern(pattern string, excludeID uint) error {
+	var count int64
+	if err := s.db.Model(&models.ComplianceRule{}).
+		Where("pattern = ? AND id <> ?", pattern, excludeID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("查询合规规则失败: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrComplianceRuleExists, pattern)
+	}
+	return nil
+}
+
+// DeleteRule 删除合规规则
+func (s *ComplianceService) DeleteRule(id uint) error {
+	if err := s.db.Delete(&models.ComplianceRule{}, id).Error; err != nil {
+		return fmt.Errorf("删除合规规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// validateComplianceRule 校验合规规则
+func validateComplianceRule(rule *models.ComplianceRule) error {
+	if rule.Pattern == "" {
+		return fmt.Errorf("%w: 规则词条不能为空", ErrInvalidComplianceRule)
+	}
+
+	// 改写规则允许替换为空，即直接删除该词
+	switch compliance.Severity(rule.Severity) {
+	case compliance.SeverityBlock, compliance.SeverityFlag, compliance.SeverityRewrite:
+		return nil
+	default:
+		return fmt.Errorf("%w: 无效的规则级别%s", ErrInvalidComplianceRule, rule.Severity)
+	}
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/ai"
+)
+
+// 分段类型
+const (
+	SectionKindOutline    = "outline"
+	SectionKindIntro      = "intro"
+	SectionKindSection    = "section"
+	SectionKindConclusion = "conclusion"
+)
+
+// 分段数量限制
+const (
+	minPipelineSections     = 3
+	defaultPipelineSections = 6
+)
+
+// articleOutline 文章大纲
+type articleOutline struct {
+	Title    string           `json:"title"`
+	Sections []outlineSection `json:"sections"`
+}
+
+// outlineSection 大纲中的一节
+type outlineSection struct {
+	Heading string   `json:"heading"`
+	Points  []string `json:"points"`
+}
+
+// generatePipeline 按大纲、分段、开头结尾的顺序生成文章，已完成的步骤直接复用
+func (s *ContentService) generatePipeline(ctx context.Context, task *models.GenerationTask, keyword models.Keyword) (string, error) {
+	// 加载已完成的步骤
+	var saved []models.GenerationSection
+	if err := s.db.Where("task_id = ?", task.ID).Find(&saved).Error; err != nil {
+		return "", fmt.Errorf("查询分段结果失败: %w", err)
+	}
+	existing := make(map[string]*models.GenerationSection, len(saved))
+	for i := range saved {
+		existing[sectionKey(saved[i].Kind, saved[i].Position)] = &saved[i]
+	}
+
+	data, err := s.buildData(task, keyword)
+	if err != nil {
+		return "", err
+	}
+
+	// 生成大纲
+	outline, err := s.pipelineOutline(ctx, task, data, existing)
+	if err != nil {
+		return "", err
+	}
+
+	headings := make([]string, len(outline.Sections))
+	for i, section := range outline.Sections {
+		headings[i] = fmt.Sprintf("%d. %s", i+1, section.Heading)
+	}
+	data.Title = outline.Title
+	data.Outline = strings.Join(headings, "\n")
+
+	// 每节的长度目标
+	count := len(outline.Sections)
+	sectionData := data
+	sectionData.MinLength = max(s.config.Content.ArticleMinLength/(count+1), 200)
+	sectionData.MaxLength = max(s.config.Content.ArticleMaxLength/(count+1), sectionData.MinLength+100)
+
+	// 逐节生成正文
+	bodies := make([]string, 0, count)
+	for i, section := range outline.Sections {
+		sectionData.Heading = section.Heading
+		sectionData.Points = section.Points
+		sectionData.Previous = ""
+		if i > 0 {
+			sectionData.Previous = lastRunes(bodies[i-1], 200)
+		}
+
+		body, err := s.pipelineStep(ctx, task, existing, PromptKindSection, SectionKindSection, i+1, section.Heading, sectionData)
+		if err != nil {
+			return "", fmt.Errorf("生成第%d节「%s」失败: %w", i+1, section.Heading, err)
+		}
+		bodies = append(bodies, normalizeSection(body, section.Heading))
+	}
+
+	// 生成引言和总结
+	intro, err := s.pipelineStep(ctx, task, existing, PromptKindIntro, SectionKindIntro, 0, "", data)
+	if err != nil {
+		return "", fmt.Errorf("生成引言失败: %w", err)
+	}
+
+	conclusion, err := s.pipelineStep(ctx, task, existing, PromptKindConclusion, SectionKindConclusion, count+1, "总结与建议", data)
+	if err != nil {
+		return "", fmt.Errorf("生成总结失败: %w", err)
+	}
+
+	// 组装全文
+	parts := []string{"# " + outline.Title, stripHeadings(intro)}
+	parts = append(parts, bodies...)
+	parts = append(parts, normalizeSection(conclusion, "总结与建议"))
+
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// pipelineOutline 生成或复用文章大纲
+func (s *ContentService) pipelineOutline(ctx context.Context, task *models.GenerationTask, data PromptData, existing map[string]*models.GenerationSection) (*articleOutline, error) {
+	if section, ok := existing[sectionKey(SectionKindOutline, 0)]; ok && section.Status == "completed" {
+		var outline articleOutline
+		if err := json.Unmarshal([]byte(section.Content), &outline); err == nil {
+			return &outline, nil
+		}
+	}
+
+	data.MinSections = minPipelineSections
+	data.MaxSections = s.config.Content.MaxSections
+	if data.MaxSections <= 0 {
+		data.MaxSections = defaultPipelineSections
+	}
+
+	rendered, err := s.renderPrompt(task, PromptKindOutline, data)
+	if err != nil {
+		return nil, err
+	}
+	s.db.Model(task).Updates(map[string]interface{}{
+		"prompt":              rendered.Prompt,
+		"system_prompt":       rendered.SystemPrompt,
+		"template_version_id": rendered.TemplateVersionID,
+	})
+
+	// 大纲本身是JSON，使用模型的JSON输出模式
+	raw, modelUsed, err := s.generateJSON(ctx, rendered.SystemPrompt, rendered.Prompt)
+	if err == nil {
+		s.db.Model(task).Update("model_used", modelUsed)
+	}
+
+	// 解析并校验大纲
+	var outline articleOutline
+	if err == nil {
+		if jsonErr := json.Unmarshal([]byte(ai.ExtractJSON(raw)), &outline); jsonErr != nil {
+			err = fmt.Errorf("解析大纲JSON失败: %w", jsonErr)
+		} else if len(outline.Sections) == 0 {
+			err = fmt.Errorf("大纲没有包含任何章节")
+		}
+	}
+
+	if err != nil {
+		s.saveSection(task.ID, existing, SectionKindOutline, 0, "", raw, modelUsed, &rendered.TemplateVersionID, err)
+		return nil, fmt.Errorf("生成大纲失败: %w", err)
+	}
+
+	if strings.TrimSpace(outline.Title) == "" {
+		outline.Title = data.Keyword
+	}
+	if len(outline.Sections) > data.MaxSections {
+		outline.Sections = outline.Sections[:data.MaxSections]
+	}
+
+	outlineJSON, _ := json.Marshal(outline)
+	s.saveSection(task.ID, existing, SectionKindOutline, 0, outline.Title, string(outlineJSON), modelUsed, &rendered.TemplateVersionID, nil)
+
+	return &outline, nil
+}
+
+// pipelineStep 执行一个生成步骤，已完成的步骤直接返回保存的结果
+func (s *ContentService) pipelineStep(ctx context.Context, task *models.GenerationTask, existing map[string]*models.GenerationSection, promptKind, kind string, position int, heading string, data PromptData) (string, error) {
+	if section, ok := existing[sectionKey(kind, position)]; ok && section.Status == "completed" {
+		return section.Content, nil
+	}
+
+	rendered, err := s.renderPrompt(task, promptKind, data)
+	if err != nil {
+		return "", err
+	}
+
+	content, modelUsed, err := s.generateText(ctx, rendered.SystemPrompt, rendered.Prompt)
+	if err == nil && strings.TrimSpace(content) == "" {
+		err = fmt.Errorf("没有内容返回")
+	}
+
+	s.saveSection(task.ID, existing, kind, position, heading, content, modelUsed, &rendered.TemplateVersionID, err)
+	if err != nil {
+		return "", err
+	}
+
+	return content, nil
+}
+
+// saveSection 保存步骤结果，失败时记录错误以便之后继续
+func (s *ContentService) saveSection(taskID uint, existing map[string]*models.GenerationSection, kind string, position int, heading, content, modelUsed string, templateVersionID *uint, genErr error) {
+	key := sectionKey(kind, position)
+	section, ok := existing[key]
+	if !ok {
+		section = &models.GenerationSection{
+			TaskID:   taskID,
+			Kind:     kind,
+			Position: position,
+		}
+		existing[key] = section
+	}
+
+	section.Heading = heading
+	section.Content = content
+	section.ModelUsed = modelUsed
+	section.TemplateVersionID = templateVersionID
+	section.Status = "completed"
+	section.ErrorMessage = ""
+	if genErr != nil {
+		section.Status = "failed"
+		section.ErrorMessage = genErr.Error()
+	}
+
+	if err := s.db.Save(section).Error; err != nil {
+		log.Printf("保存分段结果失败: %v", err)
+	}
+}
+
+// sectionKey 分段结果的索引键
+func sectionKey(kind string, position int) string {
+	return fmt.Sprintf("%s:%d", kind, position)
+}
+
+// normalizeSection 确保段落以指定的二级标题开头，并去掉模型多写的一级标题
+func normalizeSection(body, heading string) string {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	for len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "# ") {
+		lines = lines[1:]
+	}
+	body = strings.TrimSpace(strings.Join(lines, "\n"))
+
+	if !strings.HasPrefix(body, "## ") {
+		body = "## " + heading + "\n\n" + body
+	}
+	return body
+}
+
+// stripHeadings 去掉引言中模型多写的标题行
+func stripHeadings(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// lastRunes 返回文本末尾的若干字符
+func lastRunes(text string, n int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return string(runes[len(runes)-n:])
+}
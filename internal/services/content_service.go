@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,90 +12,297 @@ import (
 	"github.com/NietzscheX/seo-generate/config"
 	"github.com/NietzscheX/seo-generate/internal/models"
 	"github.com/NietzscheX/seo-generate/pkg/ai"
+	"github.com/NietzscheX/seo-generate/pkg/compliance"
 	"gorm.io/gorm"
 )
 
 // ContentService 内容生成服务
 type ContentService struct {
-	db             *gorm.DB
-	config         *config.Config
-	deepseekClient *ai.DeepSeekClient
-	ollamaClient   *ai.OllamaClient
+	db                *gorm.DB
+	config            *config.Config
+	deepseekClient    *ai.DeepSeekClient
+	ollamaClient      *ai.OllamaClient
+	complianceService *ComplianceService
+	promptService     *PromptService
+	experimentService *ExperimentService
+	faqService        *FAQService
 }
 
 // NewContentService 创建内容生成服务
-func NewContentService(db *gorm.DB, cfg *config.Config) *ContentService {
+func NewContentService(db *gorm.DB, cfg *config.Config, complianceService *ComplianceService, promptService *PromptService, experimentService *ExperimentService, faqService *FAQService) *ContentService {
 	return &ContentService{
-		db:             db,
-		config:         cfg,
-		deepseekClient: ai.NewDeepSeekClient(cfg),
-		ollamaClient:   ai.NewOllamaClient(cfg),
+		db:                db,
+		config:            cfg,
+		deepseekClient:    ai.NewDeepSeekClient(cfg),
+		ollamaClient:      ai.NewOllamaClient(cfg),
+		complianceService: complianceService,
+		promptService:     promptService,
+		experimentService: experimentService,
+		faqService:        faqService,
 	}
 }
 
-// PromptTemplate 提示模板
-const PromptTemplate = `
-请根据以下关键词，创作一篇关于养生/中医/修行的高质量文章：
+// 生成模式
+const (
+	GenerationModeSingle   = "single"   // 单次调用生成全文
+	GenerationModePipeline = "pipeline" // 大纲、分段、组装的多阶段生成
+)
+
+// ErrTaskNotResumable 任务已完成或正在执行，不能继续
+var ErrTaskNotResumable = errors.New("任务不能继续执行")
+
+// GenerateOptions 文章生成选项
+type GenerateOptions struct {
+	Mode      string // 为空时使用配置的默认模式
+	ClusterID *uint  // 以关键词聚类为目标生成，关键词为聚类的主关键词
+}
 
-主要关键词: %s
+// maxSecondaryKeywords 以聚类为目标生成时提示中最多的次要关键词数
+const maxSecondaryKeywords = 10
 
-文章要求:
-1. 标题需要包含主关键词，吸引人点击
-2. 内容长度在%d-%d字之间
-3. 分段清晰，每段不超过300字
-4. 使用二级标题(##)和三级标题(###)组织内容
-5. 内容需要专业、准确、有深度
-6. 适当引用中医经典或科学研究支持观点
-7. 结尾要有总结和实用建议
+// resolveMode 返回实际使用的生成模式
+func (s *ContentService) resolveMode(mode string) string {
+	if mode != "" {
+		return mode
+	}
+	if s.config.Content.PipelineMode {
+		return GenerationModePipeline
+	}
+	return GenerationModeSingle
+}
 
-文章格式:
-- 使用Markdown格式
-- 标题使用一级标题(#)
-- 正文分段使用空行隔开
-- 重要概念可以使用**加粗**标记
-- 可以适当使用列表展示步骤或要点
+// GenerationTimeout 返回生成整篇文章的超时时间，分段模式需要多次调用模型
+func (s *ContentService) GenerationTimeout(mode string) time.Duration {
+	timeout := time.Duration(s.config.AI.Timeout) * time.Second
+	if s.resolveMode(mode) != GenerationModePipeline {
+		if s.config.Content.StructuredOutput {
+			// 结构化输出校验失败时需要重试
+			return timeout * time.Duration(s.jsonMaxRetries()+1)
+		}
+		return timeout
+	}
 
-请确保内容原创、有价值，避免虚假或误导性信息。
-`
+	maxSections := s.config.Content.MaxSections
+	if maxSections <= 0 {
+		maxSections = defaultPipelineSections
+	}
+	// 大纲、各节、引言和总结
+	return timeout * time.Duration(maxSections+3)
+}
 
 // GenerateArticle 生成文章
 func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Keyword, categoryIDs []uint) (*models.Article, error) {
+	return s.GenerateArticleWithOptions(ctx, keyword, categoryIDs, GenerateOptions{})
+}
+
+// GenerateArticleWithOptions 按指定选项生成文章
+func (s *ContentService) GenerateArticleWithOptions(ctx context.Context, keyword models.Keyword, categoryIDs []uint, opts GenerateOptions) (*models.Article, error) {
+	mode := s.resolveMode(opts.Mode)
+	if mode != GenerationModeSingle && mode != GenerationModePipeline {
+		return nil, fmt.Errorf("无效的生成模式: %s", mode)
+	}
+
 	// 创建生成任务
 	task := models.GenerationTask{
-		KeywordID: keyword.ID,
-		Status:    "processing",
-		Prompt:    fmt.Sprintf(PromptTemplate, keyword.Word, s.config.Content.ArticleMinLength, s.config.Content.ArticleMaxLength),
+		KeywordID:   keyword.ID,
+		Status:      "processing",
+		Mode:        mode,
+		CategoryIDs: categoryIDs,
+		ClusterID:   opts.ClusterID,
+	}
+
+	// 参与运行中的提示模板实验
+	variant, err := s.experimentService.PickVariant(modePromptKinds(mode))
+	if err != nil {
+		return nil, err
+	}
+	if variant != nil {
+		task.ExperimentVariantID = &variant.ID
+	}
+
+	if mode == GenerationModeSingle {
+		data, err := s.buildData(&task, keyword)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered, err := s.renderPrompt(&task, PromptKindArticle, data)
+		if err != nil {
+			return nil, err
+		}
+		task.Prompt = rendered.Prompt
+		task.SystemPrompt = rendered.SystemPrompt
+		task.TemplateVersionID = &rendered.TemplateVersionID
+		task.StructuredOutput = s.config.Content.StructuredOutput
 	}
 
 	if err := s.db.Create(&task).Error; err != nil {
 		return nil, fmt.Errorf("创建生成任务失败: %w", err)
 	}
 
+	return s.runTask(ctx, &task, keyword)
+}
+
+// buildData 构建任务的模板变量，以聚类为目标时加入次要关键词
+func (s *ContentService) buildData(task *models.GenerationTask, keyword models.Keyword) (PromptData, error) {
+	data, err := s.promptService.BuildData(keyword, task.CategoryIDs)
+	if err != nil {
+		return data, err
+	}
+	if task.ClusterID != nil {
+		if err := s.promptService.AddClusterData(&data, *task.ClusterID, keyword.ID, maxSecondaryKeywords); err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+// modePromptKinds 返回生成模式使用的提示模板类型
+func modePromptKinds(mode string) []string {
+	if mode == GenerationModePipeline {
+		return []string{PromptKindOutline, PromptKindSection, PromptKindIntro, PromptKindConclusion}
+	}
+	return []string{PromptKindArticle}
+}
+
+// renderPrompt 渲染任务使用的提示，任务所在实验分组覆盖该类型时使用分组的模板版本
+func (s *ContentService) renderPrompt(task *models.GenerationTask, kind string, data PromptData) (*RenderedPrompt, error) {
+	if task.ExperimentVariantID != nil {
+		variant, err := s.experimentService.GetVariant(*task.ExperimentVariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		if variant.Experiment != nil && variant.Experiment.Kind == kind {
+			version, err := s.promptService.GetVersionByID(variant.TemplateVersionID)
+			if err != nil {
+				return nil, err
+			}
+			return s.promptService.Render(version, data)
+		}
+	}
+
+	return s.promptService.RenderKind(kind, task.CategoryIDs, data)
+}
+
+// ResumeArticle 继续执行失败的生成任务，分段模式下已完成的段落不会重新生成
+func (s *ContentService) ResumeArticle(ctx context.Context, taskID uint) (*models.Article, error) {
+	var task models.GenerationTask
+	if err := s.db.Preload("Keyword").First(&task, taskID).Error; err != nil {
+		return nil, fmt.Errorf("查询生成任务失败: %w", err)
+	}
+
+	if task.Status == "completed" {
+		return nil, fmt.Errorf("%w: 任务已完成，无需继续", ErrTaskNotResumable)
+	}
+	if task.Status == "processing" {
+		return nil, fmt.Errorf("%w: 任务正在执行，请等待完成或失败后再继续", ErrTaskNotResumable)
+	}
+
+	// 只有未在执行的任务可以改为执行中，避免同时继续同一个任务
+	result := s.db.Model(&task).
+		Where("status NOT IN ?", []string{"processing", "completed"}).
+		Update("status", "processing")
+	if result.Error != nil {
+		return nil, fmt.Errorf("更新任务状态失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: 任务正在执行，请等待完成或失败后再继续", ErrTaskNotResumable)
+	}
+
+	return s.runTask(ctx, &task, task.Keyword)
+}
+
+// GetGenerationTask 获取生成任务及其分段结果
+func (s *ContentService) GetGenerationTask(taskID uint) (*models.GenerationTask, error) {
+	var task models.GenerationTask
+	if err := s.db.Preload("Keyword").
+		Preload("TemplateVersion").
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&task, taskID).Error; err != nil {
+		return nil, fmt.Errorf("查询生成任务失败: %w", err)
+	}
+	return &task, nil
+}
+
+// runTask 执行生成任务并保存文章
+func (s *ContentService) runTask(ctx context.Context, task *models.GenerationTask, keyword models.Keyword) (*models.Article, error) {
+	var content string
+	var output *ai.ArticleOutput
+	var err error
+
+	switch {
+	case task.Mode == GenerationModePipeline:
+		content, err = s.generatePipeline(ctx, task, keyword)
+	case task.StructuredOutput:
+		output, err = s.generateStructured(ctx, task)
+		if err == nil {
+			content = output.Markdown()
+		}
+	default:
+		content, err = s.generateSingle(ctx, task)
+	}
+	if err != nil {
+		// 更新任务状态为失败
+		s.db.Model(task).Updates(map[string]interface{}{
+			"status":        "failed",
+			"error_message": err.Error(),
+		})
+		return nil, fmt.Errorf("生成内容失败: %w", err)
+	}
+
+	return s.saveArticle(task, keyword, content, output)
+}
+
+// generateSingle 单次调用生成全文
+func (s *ContentService) generateSingle(ctx context.Context, task *models.GenerationTask) (string, error) {
+	systemPrompt := task.SystemPrompt
+	if systemPrompt == "" {
+		systemPrompt = ai.DefaultSystemPrompt
+	}
+
 	// 尝试使用DeepSeek生成内容
-	content, err := s.generateWithDeepSeek(ctx, task.Prompt)
+	content, err := s.generateWithDeepSeek(ctx, systemPrompt, task.Prompt)
 	if err != nil {
 		// 如果DeepSeek失败，尝试使用Ollama
-		s.db.Model(&task).Updates(map[string]interface{}{
+		s.db.Model(task).Updates(map[string]interface{}{
 			"error_message": err.Error(),
 		})
 
-		content, err = s.generateWithOllama(ctx, task.Prompt)
+		content, err = s.generateWithOllama(ctx, systemPrompt, task.Prompt)
 		if err != nil {
-			// 更新任务状态为失败
-			s.db.Model(&task).Updates(map[string]interface{}{
-				"status":        "failed",
-				"error_message": err.Error(),
-			})
-			return nil, fmt.Errorf("生成内容失败: %w", err)
+			return "", err
 		}
 
 		// 更新使用的模型
-		s.db.Model(&task).Update("model_used", "ollama")
+		s.db.Model(task).Update("model_used", "ollama")
 	} else {
 		// 更新使用的模型
-		s.db.Model(&task).Update("model_used", "deepseek")
+		s.db.Model(task).Update("model_used", "deepseek")
+	}
+
+	return content, nil
+}
+
+// generateText 依次尝试DeepSeek和Ollama生成内容，返回内容和使用的模型
+func (s *ContentService) generateText(ctx context.Context, systemPrompt, prompt string) (string, string, error) {
+	content, err := s.generateWithDeepSeek(ctx, systemPrompt, prompt)
+	if err == nil {
+		return content, "deepseek", nil
 	}
 
+	content, ollamaErr := s.generateWithOllama(ctx, systemPrompt, prompt)
+	if ollamaErr != nil {
+		return "", "", fmt.Errorf("DeepSeek: %v; Ollama: %w", err, ollamaErr)
+	}
+
+	return content, "ollama", nil
+}
+
+// saveArticle 处理生成的内容并保存文章，output不为空时使用结构化输出中的摘要、SEO元信息和标签
+func (s *ContentService) saveArticle(task *models.GenerationTask, keyword models.Keyword, content string, output *ai.ArticleOutput) (*models.Article, error) {
 	// 打印原始内容
 	fmt.Println("=== 原始AI生成内容 ===")
 	fmt.Println(content)
@@ -119,6 +327,17 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 
 	// 生成摘要
 	summary := generateSummary(content)
+	metaTitle := title
+	var metaDesc string
+	var tags []string
+	if output != nil {
+		if output.Summary != "" {
+			summary = output.Summary
+		}
+		metaTitle = output.MetaTitle
+		metaDesc = output.MetaDescription
+		tags = output.Tags
+	}
 	fmt.Println("=== 生成的摘要 ===")
 	fmt.Println(summary)
 
@@ -149,6 +368,21 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 		return r
 	}, summary)
 
+	// 结构化输出的Meta标题和描述同样清理
+	metaTitle = strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 && r < 256 {
+			return -1
+		}
+		return r
+	}, metaTitle)
+
+	metaDesc = strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 && r < 256 {
+			return -1
+		}
+		return r
+	}, metaDesc)
+
 	// 打印清理后的内容
 	fmt.Println("=== 清理后的标题 ===")
 	fmt.Println(title)
@@ -157,14 +391,56 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 	fmt.Println("=== 清理后的摘要 ===")
 	fmt.Println(summary)
 
+	// 常见问题：优先使用结构化输出，否则从正文中提取
+	faqs, faqSource := ExtractFAQs(content), FAQSourceExtracted
+	if output != nil && len(output.FAQ) > 0 {
+		faqs, faqSource = output.FAQ, FAQSourceGenerated
+	}
+
+	// 合规检查，自动改写违规用语
+	findings := make(map[string][]compliance.Finding)
+	if metaDesc == "" {
+		metaDesc = summary[:min(len(summary), 160)]
+	}
+	for field, text := range map[string]*string{"title": &title, "content": &content, "summary": &summary, "meta_title": &metaTitle, "meta_desc": &metaDesc} {
+		rewritten, fieldFindings, err := s.complianceService.Apply(*text)
+		if err != nil {
+			s.db.Model(task).Updates(map[string]interface{}{
+				"status":        "failed",
+				"error_message": err.Error(),
+			})
+			return nil, fmt.Errorf("合规检查失败: %w", err)
+		}
+		*text = rewritten
+		findings[field] = fieldFindings
+	}
+
+	// 常见问题的问答同样改写
+	faqFindings := make([]compliance.Finding, 0)
+	for i := range faqs {
+		for _, text := range []*string{&faqs[i].Question, &faqs[i].Answer} {
+			rewritten, itemFindings, err := s.complianceService.Apply(*text)
+			if err != nil {
+				s.db.Model(task).Updates(map[string]interface{}{
+					"status":        "failed",
+					"error_message": err.Error(),
+				})
+				return nil, fmt.Errorf("合规检查失败: %w", err)
+			}
+			*text = rewritten
+			faqFindings = append(faqFindings, itemFindings...)
+		}
+	}
+
 	// 创建文章
 	article := &models.Article{
 		Title:     title,
 		Slug:      slug,
 		Content:   content,
 		Summary:   summary,
-		MetaTitle: title,
-		MetaDesc:  summary[:min(len(summary), 160)],
+		MetaTitle: metaTitle,
+		MetaDesc:  metaDesc,
+		Tags:      tags,
 		Status:    "draft",
 	}
 
@@ -184,9 +460,9 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 	}
 
 	// 关联分类
-	if len(categoryIDs) > 0 {
+	if len(task.CategoryIDs) > 0 {
 		var categories []models.Category
-		if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		if err := tx.Where("id IN ?", task.CategoryIDs).Find(&categories).Error; err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("查询分类失败: %w", err)
 		}
@@ -197,8 +473,37 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 		}
 	}
 
+	// 保存常见问题
+	if err := s.faqService.SaveFAQs(tx, article.ID, faqs, faqSource); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	findings["faq"] = faqFindings
+
+	// 保存合规检查结果
+	for field, fieldFindings := range findings {
+		if err := s.complianceService.SaveFindings(tx, article.ID, field, fieldFindings, true); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 记录实验分组
+	if task.ExperimentVariantID != nil {
+		findingCount := 0
+		for _, fieldFindings := range findings {
+			findingCount += len(fieldFindings)
+		}
+		score := scoreArticleQuality(title, content, keyword.Word, s.config.Content.ArticleMinLength, s.config.Content.ArticleMaxLength, findingCount)
+
+		if err := s.experimentService.RecordArticle(tx, article, *task.ExperimentVariantID, score); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// 更新任务状态
-	if err := tx.Model(&task).Updates(map[string]interface{}{
+	if err := tx.Model(task).Updates(map[string]interface{}{
 		"status":     "completed",
 		"article_id": article.ID,
 	}).Error; err != nil {
@@ -206,6 +511,12 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 		return nil, fmt.Errorf("更新任务状态失败: %w", err)
 	}
 
+	// 关键词标记为已覆盖
+	if err := markKeywordCovered(tx, keyword.ID, task.ClusterID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("提交事务失败: %w", err)
@@ -215,21 +526,21 @@ func (s *ContentService) GenerateArticle(ctx context.Context, keyword models.Key
 }
 
 // generateWithDeepSeek 使用DeepSeek生成内容
-func (s *ContentService) generateWithDeepSeek(ctx context.Context, prompt string) (string, error) {
+func (s *ContentService) generateWithDeepSeek(ctx context.Context, systemPrompt, prompt string) (string, error) {
 	// 创建超时上下文
 	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.AI.Timeout)*time.Second)
 	defer cancel()
 
-	return s.deepseekClient.GenerateContent(timeoutCtx, prompt)
+	return s.deepseekClient.GenerateContentWithSystem(timeoutCtx, systemPrompt, prompt)
 }
 
 // generateWithOllama 使用Ollama生成内容
-func (s *ContentService) generateWithOllama(ctx context.Context, prompt string) (string, error) {
+func (s *ContentService) generateWithOllama(ctx context.Context, systemPrompt, prompt string) (string, error) {
 	// 创建超时上下文
 	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.AI.Timeout)*time.Second)
 	defer cancel()
 
-	return s.ollamaClient.GenerateContent(timeoutCtx, prompt)
+	return s.ollamaClient.GenerateContentWithSystem(timeoutCtx, systemPrompt, prompt)
 }
 
 // parseArticle 解析文章标题和内容
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/ai"
+)
+
+// defaultJSONMaxRetries 结构化输出校验失败后的默认重试次数
+const defaultJSONMaxRetries = 2
+
+// jsonMaxRetries 返回结构化输出的重试次数
+func (s *ContentService) jsonMaxRetries() int {
+	if s.config.Content.JSONMaxRetries > 0 {
+		return s.config.Content.JSONMaxRetries
+	}
+	return defaultJSONMaxRetries
+}
+
+// generateStructured 要求模型返回JSON格式的文章，校验失败时带上错误信息让模型修正
+func (s *ContentService) generateStructured(ctx context.Context, task *models.GenerationTask) (*ai.ArticleOutput, error) {
+	systemPrompt := task.SystemPrompt
+	if systemPrompt == "" {
+		systemPrompt = ai.DefaultSystemPrompt
+	}
+
+	basePrompt := task.Prompt + "\n" + ai.ArticleOutputInstruction
+	prompt := basePrompt
+
+	var lastErr error
+	for attempt := 0; attempt <= s.jsonMaxRetries(); attempt++ {
+		raw, modelUsed, err := s.generateJSON(ctx, systemPrompt, prompt)
+		if err != nil {
+			return nil, err
+		}
+		s.db.Model(task).Update("model_used", modelUsed)
+
+		output, err := ai.ParseArticleOutput(raw)
+		if err == nil {
+			outputJSON, _ := json.Marshal(output)
+			task.Output = string(outputJSON)
+			s.db.Model(task).Update("output", task.Output)
+			return output, nil
+		}
+
+		lastErr = err
+		log.Printf("任务 %d 第%d次结构化输出校验失败: %v", task.ID, attempt+1, err)
+		prompt = ai.RepairPrompt(basePrompt, raw, err)
+	}
+
+	return nil, fmt.Errorf("结构化输出校验失败: %w", lastErr)
+}
+
+// generateJSON 使用JSON输出模式依次尝试DeepSeek和Ollama，返回内容和使用的模型
+func (s *ContentService) generateJSON(ctx context.Context, systemPrompt, prompt string) (string, string, error) {
+	deepseekCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.AI.Timeout)*time.Second)
+	content, err := s.deepseekClient.GenerateJSONWithSystem(deepseekCtx, systemPrompt, prompt)
+	cancel()
+	if err == nil {
+		return content, "deepseek", nil
+	}
+
+	ollamaCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.AI.Timeout)*time.Second)
+	defer cancel()
+	content, ollamaErr := s.ollamaClient.GenerateJSONWithSystem(ollamaCtx, systemPrompt, prompt)
+	if ollamaErr != nil {
+		return "", "", fmt.Errorf("DeepSeek: %v; Ollama: %w", err, ollamaErr)
+	}
+
+	return content, "ollama", nil
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"gorm.io/gorm"
+)
+
+// 实验状态
+const (
+	ExperimentStatusDraft   = "draft"
+	ExperimentStatusRunning = "running"
+	ExperimentStatusStopped = "stopped"
+)
+
+// ExperimentVariantRequest 实验分组请求
+type ExperimentVariantRequest struct {
+	Name              string `json:"name" binding:"required"`
+	TemplateVersionID uint   `json:"template_version_id" binding:"required"`
+	Weight            int    `json:"weight"`
+}
+
+// VariantReport 实验分组效果统计
+type VariantReport struct {
+	VariantID         uint    `json:"variant_id"`
+	Name              string  `json:"name"`
+	TemplateVersionID uint    `json:"template_version_id"`
+	Weight            int     `json:"weight"`
+	Articles          int64   `json:"articles"`
+	AvgQualityScore   float64 `json:"avg_quality_score"`
+	EditedArticles    int64   `json:"edited_articles"`
+	AvgEditDistance   float64 `json:"avg_edit_distance"`
+	AvgEditRatio      float64 `json:"avg_edit_ratio"`
+	TotalViews        int64   `json:"total_views"`
+	AvgViews          float64 `json:"avg_views"`
+}
+
+// ExperimentReport 实验效果报告
+type ExperimentReport struct {
+	Experiment *models.PromptExperiment `json:"experiment"`
+	Variants   []VariantReport          `json:"variants"`
+}
+
+// ExperimentService 提示模板实验服务
+type ExperimentService struct {
+	db *gorm.DB
+}
+
+// NewExperimentService 创建提示模板实验服务
+func NewExperimentService(db *gorm.DB) *ExperimentService {
+	return &ExperimentService{
+		db: db,
+	}
+}
+
+// CreateExperiment 创建实验，分组的模板版本必须属于实验的模板类型
+func (s *ExperimentService) CreateExperiment(name, kind, description string, variants []ExperimentVariantRequest) (*models.PromptExperiment, error) {
+	if !validPromptKind(kind) {
+		return nil, fmt.Errorf("无效的模板类型: %s", kind)
+	}
+	if len(variants) < 2 {
+		return nil, fmt.Errorf("实验至少需要两个分组")
+	}
+
+	experiment := models.PromptExperiment{
+		Name:        name,
+		Kind:        kind,
+		Description: description,
+		Status:      ExperimentStatusDraft,
+	}
+
+	for _, v := range variants {
+		var version models.PromptTemplateVersion
+		if err := s.db.Joins("JOIN prompt_templates ON prompt_templates.id = prompt_template_versions.template_id").
+			Where("prompt_template_versions.id = ? AND prompt_templates.kind = ?", v.TemplateVersionID, kind).
+			First(&version).Error; err != nil {
+			return nil, fmt.Errorf("分组%s的模板版本%d不存在或类型不是%s", v.Name, v.TemplateVersionID, kind)
+		}
+
+		weight := v.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
+		experiment.Variants = append(experiment.Variants, models.PromptExperimentVariant{
+			Name:              v.Name,
+			TemplateVersionID: v.TemplateVersionID,
+			Weight:            weight,
+		})
+	}
+
+	if err := s.db.Create(&experiment).Error; err != nil {
+		return nil, fmt.Errorf("创建实验失败: %w", err)
+	}
+
+	return &experiment, nil
+}
+
+// ListExperiments 获取实验列表
+func (s *ExperimentService) ListExperiments(status string) ([]models.PromptExperiment, error) {
+	var experiments []models.PromptExperiment
+
+	query := s.db.Preload("Variants")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("id DESC").Find(&experiments).Error; err != nil {
+		return nil, fmt.Errorf("查询实验失败: %w", err)
+	}
+	return experiments, nil
+}
+
+// GetExperiment 获取实验详情
+func (s *ExperimentService) GetExperiment(id uint) (*models.PromptExperiment, error) {
+	var experiment models.PromptExperiment
+	if err := s.db.Preload("Variants.TemplateVersion").First(&experiment, id).Error; err != nil {
+		return nil, fmt.Errorf("查询实验失败: %w", err)
+	}
+	return &experiment, nil
+}
+
+// StartExperiment 开始实验，同一模板类型同时只能运行一个实验
+func (s *ExperimentService) StartExperiment(id uint) (*models.PromptExperiment, error) {
+	experiment, err := s.GetExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if experiment.Status == ExperimentStatusRunning {
+		return experiment, nil
+	}
+	if experiment.Status == ExperimentStatusStopped {
+		return nil, fmt.Errorf("已结束的实验不能重新开始")
+	}
+
+	var running int64
+	if err := s.db.Model(&models.PromptExperiment{}).
+		Where("kind = ? AND status = ?", experiment.Kind, ExperimentStatusRunning).
+		Count(&running).Error; err != nil {
+		return nil, fmt.Errorf("检查运行中的实验失败: %w", err)
+	}
+	if running > 0 {
+		return nil, fmt.Errorf("模板类型%s已有运行中的实验", experiment.Kind)
+	}
+
+	now := time.Now()
+	experiment.Status = ExperimentStatusRunning
+	experiment.StartedAt = &now
+
+	if err := s.db.Model(experiment).Updates(map[string]interface{}{
+		"status":     experiment.Status,
+		"started_at": experiment.StartedAt,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("开始实验失败: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// StopExperiment 结束实验
+func (s *ExperimentService) StopExperiment(id uint) (*models.PromptExperiment, error) {
+	experiment, err := s.GetExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	experiment.Status = ExperimentStatusStopped
+	experiment.EndedAt = &now
+
+	if err := s.db.Model(experiment).Updates(map[string]interface{}{
+		"status":   experiment.Status,
+		"ended_at": experiment.EndedAt,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("结束实验失败: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// PickVariant 按权重为生成任务选择实验分组，没有运行中的实验时返回nil
+func (s *ExperimentService) PickVariant(kinds []string) (*models.PromptExperimentVariant, error) {
+	var experiment models.PromptExperiment
+	err := s.db.Preload("Variants").
+		Where("kind IN ? AND status = ?", kinds, ExperimentStatusRunning).
+		Order("started_at").
+		First(&experiment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询运行中的实验失败: %w", err)
+	}
+
+	variant := pickWeighted(experiment.Variants)
+	if variant == nil {
+		return nil, nil
+	}
+
+	experiment.Variants = nil
+	variant.Experiment = &experiment
+	return variant, nil
+}
+
+// GetVariant 获取实验分组及其所属实验
+func (s *ExperimentService) GetVariant(id uint) (*models.PromptExperimentVariant, error) {
+	var variant models.PromptExperimentVariant
+	if err := s.db.Preload("Experiment").First(&variant, id).Error; err != nil {
+		return nil, fmt.Errorf("查询实验分组失败: %w", err)
+	}
+	return &variant, nil
+}
+
+// RecordArticle 记录文章所属分组和生成时的内容
+func (s *ExperimentService) RecordArticle(tx *gorm.DB, article *models.Article, variantID uint, qualityScore float64) error {
+	var variant models.PromptExperimentVariant
+	if err := tx.First(&variant, variantID).Error; err != nil {
+		return fmt.Errorf("查询实验分组失败: %w", err)
+	}
+
+	assignment := models.ExperimentAssignment{
+		ArticleID:        article.ID,
+		ExperimentID:     variant.ExperimentID,
+		VariantID:        variant.ID,
+		GeneratedContent: article.Content,
+		QualityScore:     qualityScore,
+	}
+
+	if err := tx.Create(&assignment).Error; err != nil {
+		return fmt.Errorf("记录实验分组失败: %w", err)
+	}
+	return nil
+}
+
+// RecordEdit 人工编辑后更新文章相对生成内容的编辑距离
+func (s *ExperimentService) RecordEdit(articleID uint, content string) error {
+	var assignment models.ExperimentAssignment
+	err := s.db.Where("article_id = ?", articleID).First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // 文章不在实验中
+	}
+	if err != nil {
+		return fmt.Errorf("查询实验分组失败: %w", err)
+	}
+
+	distance := editDistance(assignment.GeneratedContent, content)
+	ratio := 0.0
+	if length := max(utf8.RuneCountInString(assignment.GeneratedContent), utf8.RuneCountInString(content)); length > 0 {
+		ratio = float64(distance) / float64(length)
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&assignment).Updates(map[string]interface{}{
+		"edit_distance": distance,
+		"edit_ratio":    ratio,
+		"edited_at":     &now,
+	}).Error; err != nil {
+		return fmt.Errorf("更新编辑距离失败: %w", err)
+	}
+
+	return nil
+}
+
+// Report 按分组统计实验效果
+func (s *ExperimentService) Report(id uint) (*ExperimentReport, error) {
+	experiment, err := s.GetExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExperimentReport{
+		Experiment: experiment,
+		Variants:   make([]VariantReport, 0, len(experiment.Variants)),
+	}
+
+	for _, variant := range experiment.Variants {
+		row := VariantReport{
+			VariantID:         variant.ID,
+			Name:              variant.Name,
+			TemplateVersionID: variant.TemplateVersionID,
+			Weight:            variant.Weight,
+		}
+
+		if err := s.db.Table("experiment_assignments").
+			Select(`COUNT(*) AS articles,
+				COALESCE(AVG(experiment_assignments.quality_score), 0) AS avg_quality_score,
+				COUNT(experiment_assignments.edited_at) AS edited_articles,
+				COALESCE(AVG(CASE WHEN experiment_assignments.edited_at IS NOT NULL THEN experiment_assignments.edit_distance END), 0) AS avg_edit_distance,
+				COALESCE(AVG(CASE WHEN experiment_assignments.edited_at IS NOT NULL THEN experiment_assignments.edit_ratio END), 0) AS avg_edit_ratio,
+				COALESCE(SUM(articles.view_count), 0) AS total_views,
+				COALESCE(AVG(articles.view_count), 0) AS avg_views`).
+			Joins("JOIN articles ON articles.id = experiment_assignments.article_id AND articles.deleted_at IS NULL").
+			Where("experiment_assignments.variant_id = ?", variant.ID).
+			Scan(&row).Error; err != nil {
+			return nil, fmt.Errorf("统计实验分组失败: %w", err)
+		}
+
+		report.Variants = append(report.Variants, row)
+	}
+
+	return report, nil
+}
+
+// pickWeighted 按权重随机选择分组
+func pickWeighted(variants []models.PromptExperimentVariant) *models.PromptExperimentVariant {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	n := rand.Intn(total)
+	for i := range variants {
+		n -= variants[i].Weight
+		if n < 0 {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// editDistance 计算两段文本按字符的编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// scoreArticleQuality 根据长度、结构和关键词覆盖对生成的文章打分（0-100）
+func scoreArticleQuality(title, content, keyword string, minLength, maxLength, findings int) float64 {
+	score := 0.0
+	length := utf8.RuneCountInString(content)
+
+	// 长度符合要求（30分）
+	switch {
+	case length >= minLength && length <= maxLength:
+		score += 30
+	case length >= minLength/2:
+		score += 15
+	}
+
+	// 结构：二级标题数量（25分）
+	h2 := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "## ") {
+			h2++
+		}
+	}
+	score += float64(min(h2, 5)) * 5
+
+	// 关键词覆盖（30分）
+	if keyword != "" {
+		if strings.Contains(title, keyword) {
+			score += 15
+		}
+		if count := strings.Count(content, keyword); count >= 2 {
+			score += 15
+		} else if count == 1 {
+			score += 8
+		}
+	}
+
+	// 合规问题扣分（15分）
+	score += float64(max(15-findings*3, 0))
+
+	return score
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/ai"
+	"gorm.io/gorm"
+)
+
+// FAQ来源
+const (
+	FAQSourceGenerated = "generated" // 结构化输出中生成
+	FAQSourceExtracted = "extracted" // 从正文中提取
+	FAQSourceManual    = "manual"    // 编辑手动维护
+)
+
+// FAQ数量和长度限制
+const (
+	maxArticleFAQs     = 8
+	maxFAQAnswerLength = 300
+)
+
+// questionPattern 判断标题是否是问句
+var questionPattern = regexp.MustCompile(`[?？]$|什么|怎么|如何|为什么|为何|哪些|能不能|可以.*吗|吗$|好不好|有没有`)
+
+// qaPrefixPattern 匹配"问：""答："形式的问答
+var qaPrefixPattern = regexp.MustCompile(`^(?:\*\*)?(问|答|Q|A)\s*[：:]\s*(?:\*\*)?`)
+
+// FAQService 文章常见问题服务
+type FAQService struct {
+	db *gorm.DB
+}
+
+// NewFAQService 创建文章常见问题服务
+func NewFAQService(db *gorm.DB) *FAQService {
+	return &FAQService{
+		db: db,
+	}
+}
+
+// GetArticleFAQs 获取文章的常见问题
+func (s *FAQService) GetArticleFAQs(articleID uint) ([]models.ArticleFAQ, error) {
+	var faqs []models.ArticleFAQ
+	if err := s.db.Where("article_id = ?", articleID).Order("position ASC, id ASC").Find(&faqs).Error; err != nil {
+		return nil, fmt.Errorf("查询常见问题失败: %w", err)
+	}
+	return faqs, nil
+}
+
+// SaveFAQs 在事务中替换文章的常见问题
+func (s *FAQService) SaveFAQs(tx *gorm.DB, articleID uint, items []ai.FAQItem, source string) error {
+	if err := tx.Where("article_id = ?", articleID).Delete(&models.ArticleFAQ{}).Error; err != nil {
+		return fmt.Errorf("删除常见问题失败: %w", err)
+	}
+
+	faqs := make([]models.ArticleFAQ, 0, len(items))
+	for _, item := range items {
+		question := strings.TrimSpace(item.Question)
+		answer := strings.TrimSpace(item.Answer)
+		if question == "" || answer == "" {
+			continue
+		}
+
+		faqs = append(faqs, models.ArticleFAQ{
+			ArticleID: articleID,
+			Position:  len(faqs) + 1,
+			Question:  question,
+			Answer:    answer,
+			Source:    source,
+		})
+	}
+	if len(faqs) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&faqs).Error; err != nil {
+		return fmt.Errorf("保存常见问题失败: %w", err)
+	}
+	return nil
+}
+
+// ReplaceArticleFAQs 使用编辑提交的列表替换文章的常见问题
+func (s *FAQService) ReplaceArticleFAQs(articleID uint, items []ai.FAQItem) ([]models.ArticleFAQ, error) {
+	var article models.Article
+	if err := s.db.First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := s.SaveFAQs(tx, articleID, items, FAQSourceManual); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetArticleFAQs(articleID)
+}
+
+// ExtractArticleFAQs 从文章正文中重新提取常见问题，替换现有的问题
+func (s *FAQService) ExtractArticleFAQs(articleID uint) ([]models.ArticleFAQ, error) {
+	var article models.Article
+	if err := s.db.First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	items := ExtractFAQs(article.Content)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("正文中没有找到问答形式的内容")
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := s.SaveFAQs(tx, articleID, items, FAQSourceExtracted); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetArticleFAQs(articleID)
+}
+
+// ExtractFAQs 从Markdown正文中提取问答：问句形式的小标题及其下的第一段，以及"问：/答："形式的段落
+func ExtractFAQs(content string) []ai.FAQItem {
+	var items []ai.FAQItem
+	seen := make(map[string]bool)
+
+	add := func(question string, answer []string) {
+		question = strings.TrimSpace(question)
+		text := truncateAnswer(strings.Join(answer, ""))
+		if question == "" || text == "" || seen[question] || len(items) >= maxArticleFAQs {
+			return
+		}
+		seen[question] = true
+		items = append(items, ai.FAQItem{Question: question, Answer: text})
+	}
+
+	var question string
+	var answer []string
+	var fromQA bool
+
+	flush := func() {
+		if question != "" {
+			add(question, answer)
+		}
+		question, answer, fromQA = "", nil, false
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "#") {
+			flush()
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if strings.HasPrefix(line, "##") && questionPattern.MatchString(heading) {
+				question = stripMarkdown(heading)
+			}
+			continue
+		}
+
+		if m := qaPrefixPattern.FindStringSubmatch(line); m != nil {
+			text := stripMarkdown(line[len(m[0]):])
+			switch m[1] {
+			case "问", "Q":
+				flush()
+				question, fromQA = text, true
+			case "答", "A":
+				if fromQA && len(answer) == 0 {
+					answer = append(answer, text)
+				}
+			}
+			continue
+		}
+
+		if question == "" {
+			continue
+		}
+
+		// 小标题下只取第一段作为回答
+		if line == "" {
+			if len(answer) > 0 && !fromQA {
+				flush()
+			}
+			continue
+		}
+		if !fromQA {
+			answer = append(answer, stripMarkdown(line))
+		}
+	}
+	flush()
+
+	return items
+}
+
+// stripMarkdown 去掉行内的Markdown标记
+func stripMarkdown(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimLeft(text, "-*+> ")
+	text = strings.ReplaceAll(text, "**", "")
+	text = strings.ReplaceAll(text, "__", "")
+	text = strings.ReplaceAll(text, "`", "")
+	return strings.TrimSpace(text)
+}
+
+// truncateAnswer 将回答截断到长度限制内，尽量在句末截断
+func truncateAnswer(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= maxFAQAnswerLength {
+		return string(runes)
+	}
+
+	cut := string(runes[:maxFAQAnswerLength])
+	if i := strings.LastIndexAny(cut, "。！？；"); i > 0 {
+		_, size := utf8.DecodeRuneInString(cut[i:])
+		return cut[:i+size]
+	}
+	return cut + "…"
+}
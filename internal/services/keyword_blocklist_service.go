@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// KeywordActionBlocked 导入时命中屏蔽规则的行
+const KeywordActionBlocked = "blocked"
+
+// keywordBlockReasonManual 手动屏蔽的关键词的屏蔽原因
+const keywordBlockReasonManual = "手动屏蔽"
+
+// keywordBlockBatch 对已有关键词应用屏蔽规则时每批处理的关键词数
+const keywordBlockBatch = 1000
+
+// KeywordBlocklistResult 对已有关键词应用屏蔽规则的结果
+type KeywordBlocklistResult struct {
+	Checked  int                     `json:"checked"`
+	Blocked  int                     `json:"blocked"`
+	Keywords []models.BlockedKeyword `json:"keywords"`
+}
+
+// InitDefaultBlockRules 初始化默认屏蔽规则
+func (s *KeywordService) InitDefaultBlockRules() error {
+	// 检查是否已有规则
+	var count int64
+	if err := s.db.Model(&models.KeywordBlockRule{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("检查屏蔽规则数量失败: %w", err)
+	}
+
+	if count > 0 {
+		return nil // 已有规则，不需要初始化
+	}
+
+	defaults := seo.DefaultKeywordBlockRules()
+	rules := make([]models.KeywordBlockRule, 0, len(defaults))
+	for _, rule := range defaults {
+		rules = append(rules, models.KeywordBlockRule{
+			Pattern:   rule.Pattern,
+			MatchType: rule.MatchType,
+			Category:  rule.Category,
+			Enabled:   true,
+		})
+	}
+
+	if err := s.db.Create(&rules).Error; err != nil {
+		return fmt.Errorf("创建默认屏蔽规则失败: %w", err)
+	}
+
+	s.invalidateBlocklist()
+	return nil
+}
+
+// getBlocklist 获取编译后的屏蔽规则，规则变更后重新构建
+func (s *KeywordService) getBlocklist() (*seo.KeywordBlocklist, error) {
+	s.blocklistMu.RLock()
+	blocklist := s.blocklist
+	s.blocklistMu.RUnlock()
+	if blocklist != nil {
+		return blocklist, nil
+	}
+
+	var rules []models.KeywordBlockRule
+	if err := s.db.Where("enabled = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询屏蔽规则失败: %w", err)
+	}
+
+	blockRules := make([]seo.KeywordBlockRule, 0, len(rules))
+	for _, rule := range rules {
+		blockRules = append(blockRules, blockRule(rule))
+	}
+	blocklist = seo.NewKeywordBlocklist(blockRules)
+
+	s.blocklistMu.Lock()
+	s.blocklist = blocklist
+	s.blocklistMu.Unlock()
+
+	return blocklist, nil
+}
+
+// invalidateBlocklist 清除已编译的屏蔽规则
+func (s *KeywordService) invalidateBlocklist() {
+	s.blocklistMu.Lock()
+	s.blocklist = nil
+	s.blocklistMu.Unlock()
+}
+
+// blockRule 转换为匹配使用的屏蔽规则
+func blockRule(rule models.KeywordBlockRule) seo.KeywordBlockRule {
+	return seo.KeywordBlockRule{
+		ID:        rule.ID,
+		Pattern:   rule.Pattern,
+		MatchType: rule.MatchType,
+		Category:  rule.Category,
+	}
+}
+
+// GetBlockRules 获取屏蔽规则列表
+func (s *KeywordService) GetBlockRules(category string) ([]models.KeywordBlockRule, error) {
+	var rules []models.KeywordBlockRule
+
+	query := s.db.Model(&models.KeywordBlockRule{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	if err := query.Order("category, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询屏蔽规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateBlockRule 创建屏蔽规则
+func (s *KeywordService) CreateBlockRule(rule models.KeywordBlockRule) (*models.KeywordBlockRule, error) {
+	if err := seo.ValidateBlockRule(blockRule(rule)); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("创建屏蔽规则失败: %w", err)
+	}
+
+	s.invalidateBlocklist()
+	return &rule, nil
+}
+
+// UpdateBlockRule 更新屏蔽规则，已屏蔽的关键词不会因为规则修改而恢复
+func (s *KeywordService) UpdateBlockRule(id uint, update models.KeywordBlockRule) (*models.KeywordBlockRule, error) {
+	var rule models.KeywordBlockRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		return nil, fmt.Errorf("查询屏蔽规则失败: %w", err)
+	}
+
+	if err := seo.ValidateBlockRule(blockRule(update)); err != nil {
+		return nil, err
+	}
+
+	rule.Pattern = update.Pattern
+	rule.MatchType = update.MatchType
+	rule.Category = update.Category
+	rule.Enabled = update.Enabled
+
+	if err := s.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("更新屏蔽规则失败: %w", err)
+	}
+
+	s.invalidateBlocklist()
+	return &rule, nil
+}
+
+// DeleteBlockRule 删除屏蔽规则
+func (s *KeywordService) DeleteBlockRule(id uint) error {
+	if err := s.db.Delete(&models.KeywordBlockRule{}, id).Error; err != nil {
+		return fmt.Errorf("删除屏蔽规则失败: %w", err)
+	}
+
+	s.invalidateBlocklist()
+	return nil
+}
+
+// filterBlocked 按屏蔽规则拆分关键词，返回未命中的关键词、命中的关键词和命中记录
+func (s *KeywordService) filterBlocked(keywords []models.Keyword) ([]models.Keyword, []models.Keyword, []models.BlockedKeyword, error) {
+	blocklist, err := s.getBlocklist()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	allowed := make([]models.Keyword, 0, len(keywords))
+	var blocked []models.Keyword
+	var report []models.BlockedKeyword
+	for _, keyword := range keywords {
+		rule, ok := blocklist.Match(keyword.Word)
+		if !ok {
+			allowed = append(allowed, keyword)
+			continue
+		}
+		blocked = append(blocked, keyword)
+		report = append(report, models.BlockedKeyword{Word: keyword.Word, RuleID: rule.ID, Reason: rule.Reason()})
+	}
+	return allowed, blocked, report, nil
+}
+
+// blockKeywords 在事务中写入命中屏蔽规则的关键词并标记为已屏蔽，记录屏蔽原因，以免再次获取时重复处理。
+// 已审核或已有文章的关键词经过人工确认，保持原状态
+func blockKeywords(tx *gorm.DB, keywords []models.Keyword, report []models.BlockedKeyword, source string) error {
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	if _, err := upsertKeywords(tx, keywords, source); err != nil {
+		return err
+	}
+
+	hits := make(map[uint]int)
+	for i := range keywords {
+		if keywords[i].Status != KeywordStatusNew {
+			continue
+		}
+		if err := tx.Model(&keywords[i]).Updates(map[string]interface{}{
+			"status":       KeywordStatusBlocked,
+			"block_reason": report[i].Reason,
+		}).Error; err != nil {
+			return fmt.Errorf("屏蔽关键词失败: %w", err)
+		}
+		hits[report[i].RuleID]++
+	}
+
+	return addBlockRuleHits(tx, hits)
+}
+
+// addBlockRuleHits 累加屏蔽规则的命中数
+func addBlockRuleHits(tx *gorm.DB, hits map[uint]int) error {
+	for ruleID, count := range hits {
+		if err := tx.Model(&models.KeywordBlockRule{}).Where("id = ?", ruleID).
+			UpdateColumn("hits", gorm.Expr("hits + ?", count)).Error; err != nil {
+			return fmt.Errorf("更新屏蔽规则命中数失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyBlocklist 对已有的新关键词应用屏蔽规则，用于新增规则后清理之前获取的关键词。
+// 与获取时一致，已审核、已加入队列或已有文章的关键词经过人工确认，保持原状态
+func (s *KeywordService) ApplyBlocklist() (*KeywordBlocklistResult, error) {
+	blocklist, err := s.getBlocklist()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KeywordBlocklistResult{Keywords: []models.BlockedKeyword{}}
+	hits := make(map[uint]int)
+	var lastID uint
+	for {
+		var keywords []models.Keyword
+		if err := s.db.Select("id", "word").
+			Where("status = ? AND id > ?", KeywordStatusNew, lastID).
+			Order("id").
+			Limit(keywordBlockBatch).
+			Find(&keywords).Error; err != nil {
+			return nil, fmt.Errorf("查询关键词失败: %w", err)
+		}
+		if len(keywords) == 0 {
+			break
+		}
+		lastID = keywords[len(keywords)-1].ID
+		result.Checked += len(keywords)
+
+		for _, keyword := range keywords {
+			rule, ok := blocklist.Match(keyword.Word)
+			if !ok {
+				continue
+			}
+			if err := s.db.Model(&keyword).Updates(map[string]interface{}{
+				"status":       KeywordStatusBlocked,
+				"block_reason": rule.Reason(),
+			}).Error; err != nil {
+				return nil, fmt.Errorf("屏蔽关键词失败: %w", err)
+			}
+			hits[rule.ID]++
+			result.Keywords = append(result.Keywords, models.BlockedKeyword{Word: keyword.Word, RuleID: rule.ID, Reason: rule.Reason()})
+		}
+	}
+	result.Blocked = len(result.Keywords)
+
+	if err := addBlockRuleHits(s.db, hits); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
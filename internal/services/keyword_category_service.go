@@ -0,0 +1,604 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/cluster"
+	"github.com/NietzscheX/seo-generate/pkg/segment"
+	"gorm.io/gorm"
+)
+
+// 分类建议方式
+const (
+	CategoryMethodDictionary = "dictionary" // 关键词包含分类词典中的词
+	CategoryMethodSimilarity = "similarity" // 与分类下已有关键词相似
+	CategoryMethodLLM        = "llm"        // 大模型分类
+)
+
+// 分类建议状态
+const (
+	SuggestionStatusPending  = "pending"
+	SuggestionStatusAccepted = "accepted"
+	SuggestionStatusRejected = "rejected" // 拒绝后不会再次建议
+)
+
+const (
+	// MaxCategorySuggestKeywords 单次生成分类建议最多处理的关键词数
+	MaxCategorySuggestKeywords = 5000
+	// maxKeywordSuggestions 每个关键词最多保留的分类建议数
+	maxKeywordSuggestions = 3
+	// defaultSuggestionMinConfidence 置信度低于该值的建议不保存
+	defaultSuggestionMinConfidence = 0.3
+	// defaultCategoryLLMThreshold 词典和相似度的最高置信度低于该值时交给大模型
+	defaultCategoryLLMThreshold = 0.6
+	// categorySimilarityMin 与已归类关键词的分词重合度低于该值时不作为依据
+	categorySimilarityMin = 0.3
+	// categorySuggestTimeout 大模型分类的超时时间
+	categorySuggestTimeout = 10 * time.Minute
+)
+
+// ErrInvalidCategoryAssignment 分类建议或批量分配参数无效
+var ErrInvalidCategoryAssignment = errors.New("无效的关键词分类")
+
+// KeywordCategoryOptions 分类建议选项
+type KeywordCategoryOptions struct {
+	KeywordIDs    []uint  // 为空时处理所有尚未分类的关键词
+	Uncategorized bool    // 指定KeywordIDs时只处理其中尚未分类的关键词
+	UseLLM        bool    // 词典和相似度把握不大的关键词交给大模型分类
+	MinConfidence float64 // 为0时使用默认值
+	AutoAccept    float64 // 大于0时，置信度达到该值的最佳建议直接分配
+}
+
+// KeywordCategoryResult 分类建议结果
+type KeywordCategoryResult struct {
+	Keywords      int    `json:"keywords"`      // 处理的关键词数
+	Suggested     int    `json:"suggested"`     // 有建议的关键词数
+	Suggestions   int    `json:"suggestions"`   // 保存的建议数
+	AutoAccepted  int    `json:"auto_accepted"` // 直接分配的关键词数
+	LLMClassified int    `json:"llm_classified"`
+	LLMError      string `json:"llm_error,omitempty"` // 大模型分类失败时保留词典和相似度的建议
+}
+
+// KeywordCategoryReview 审核分类建议
+type KeywordCategoryReview struct {
+	IDs           []uint   // 要审核的建议
+	MinConfidence *float64 // IDs为空时审核所有置信度不低于该值的待审核建议
+	CategoryID    *uint    // IDs为空时只审核该分类的建议
+	Accept        bool     // true为接受并分配，false为拒绝
+}
+
+// KeywordCategoryReviewResult 审核结果
+type KeywordCategoryReviewResult struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+}
+
+// categoryScore 关键词在某个分类上的得分
+type categoryScore struct {
+	categoryID uint
+	confidence float64 // 各方式综合后的置信度
+	method     string  // 置信度最高的方式
+	best       float64
+	reasons    []string
+}
+
+// add 合并一种方式的置信度，多种方式都指向同一分类时置信度更高
+func (cs *categoryScore) add(method string, confidence float64, reason string) {
+	cs.confidence = 1 - (1-cs.confidence)*(1-confidence)
+	if confidence > cs.best {
+		cs.best = confidence
+		cs.method = method
+	}
+	cs.reasons = append(cs.reasons, reason)
+}
+
+// assignedKeyword 已归类的关键词，用于相似度比较
+type assignedKeyword struct {
+	KeywordID  uint
+	CategoryID uint
+	Word       string
+	tokens     []string
+}
+
+// categoryPair 关键词和分类
+type categoryPair struct {
+	keywordID  uint
+	categoryID uint
+}
+
+// SuggestCategories 为关键词生成分类建议：按分类词典、与分类下已有关键词的相似度和可选的大模型分类打分，
+// 每个关键词保留置信度最高的几个分类；之前的待审核建议会被替换，已拒绝的分类不会再次建议
+func (s *KeywordService) SuggestCategories(opts KeywordCategoryOptions) (*KeywordCategoryResult, error) {
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = defaultSuggestionMinConfidence
+	}
+
+	uncategorized := "NOT EXISTS (SELECT 1 FROM category_keywords WHERE category_keywords.keyword_id = keywords.id)"
+	query := s.db.Where("status <> ?", KeywordStatusBlocked)
+	if len(opts.KeywordIDs) > 0 {
+		query = query.Where("id IN ?", opts.KeywordIDs)
+		if opts.Uncategorized {
+			query = query.Where(uncategorized)
+		}
+	} else {
+		query = query.Where(uncategorized)
+	}
+
+	var keywords []models.Keyword
+	if err := query.Order("search_volume DESC, id ASC").Limit(MaxCategorySuggestKeywords).Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	result := &KeywordCategoryResult{Keywords: len(keywords)}
+	if len(keywords) == 0 {
+		return result, nil
+	}
+
+	var categories []models.Category
+	if err := s.db.Order("id").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("查询分类失败: %w", err)
+	}
+	if len(categories) == 0 {
+		return result, nil
+	}
+
+	assigned, err := s.assignedKeywords()
+	if err != nil {
+		return nil, err
+	}
+
+	keywordIDs := make([]uint, len(keywords))
+	for i, keyword := range keywords {
+		keywordIDs[i] = keyword.ID
+	}
+	excluded, err := s.excludedPairs(keywordIDs, assigned)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分词到已归类关键词的倒排索引，只与有共同分词的关键词比较
+	index := make(map[string][]int)
+	for i := range assigned {
+		seen := make(map[string]bool, len(assigned[i].tokens))
+		for _, token := range assigned[i].tokens {
+			if !seen[token] {
+				seen[token] = true
+				index[token] = append(index[token], i)
+			}
+		}
+	}
+
+	scores := make([]map[uint]*categoryScore, len(keywords))
+	for i, keyword := range keywords {
+		scores[i] = make(map[uint]*categoryScore)
+		scoreByDictionary(keyword.Word, categories, scores[i])
+		scoreBySimilarity(keyword, assigned, index, scores[i])
+	}
+
+	if opts.UseLLM {
+		result.LLMClassified, result.LLMError = s.scoreByLLM(keywords, categories, scores)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	// 替换之前的建议，保留已拒绝的记录
+	if err := tx.Where("keyword_id IN ? AND status <> ?", keywordIDs, SuggestionStatusRejected).
+		Delete(&models.KeywordCategorySuggestion{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("删除旧的分类建议失败: %w", err)
+	}
+
+	for i, keyword := range keywords {
+		ranked := rankCategoryScores(scores[i], opts.MinConfidence)
+
+		var suggestions []models.KeywordCategorySuggestion
+		for _, score := range ranked {
+			if excluded[categoryPair{keyword.ID, score.categoryID}] {
+				continue
+			}
+			suggestions = append(suggestions, models.KeywordCategorySuggestion{
+				KeywordID:  keyword.ID,
+				CategoryID: score.categoryID,
+				Method:     score.method,
+				Confidence: score.confidence,
+				Reason:     strings.Join(score.reasons, "；"),
+				Status:     SuggestionStatusPending,
+			})
+			if len(suggestions) >= maxKeywordSuggestions {
+				break
+			}
+		}
+		if len(suggestions) == 0 {
+			continue
+		}
+
+		if opts.AutoAccept > 0 && suggestions[0].Confidence >= opts.AutoAccept {
+			if err := assignKeywordCategory(tx, keyword.ID, suggestions[0].CategoryID); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			suggestions[0].Status = SuggestionStatusAccepted
+			result.AutoAccepted++
+		}
+
+		if err := tx.Create(&suggestions).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存分类建议失败: %w", err)
+		}
+		result.Suggested++
+		result.Suggestions += len(suggestions)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// suggestNewKeywordCategories 为新保存的关键词生成分类建议，只使用词典和相似度，失败时只记录日志
+func (s *KeywordService) suggestNewKeywordCategories(keywordIDs []uint) {
+	if len(keywordIDs) == 0 {
+		return
+	}
+	if _, err := s.SuggestCategories(KeywordCategoryOptions{KeywordIDs: keywordIDs, Uncategorized: true}); err != nil {
+		log.Printf("生成关键词分类建议失败: %v", err)
+	}
+}
+
+// assignedKeywords 查询所有已归类的关键词及其分词
+func (s *KeywordService) assignedKeywords() ([]assignedKeyword, error) {
+	var assigned []assignedKeyword
+	if err := s.db.Table("category_keywords").
+		Select("category_keywords.keyword_id, category_keywords.category_id, keywords.word").
+		Joins("JOIN keywords ON keywords.id = category_keywords.keyword_id AND keywords.deleted_at IS NULL").
+		Scan(&assigned).Error; err != nil {
+		return nil, fmt.Errorf("查询已归类关键词失败: %w", err)
+	}
+
+	for i := range assigned {
+		assigned[i].tokens = segment.Segment(assigned[i].Word)
+	}
+	return assigned, nil
+}
+
+// excludedPairs 不再建议的关键词和分类：已经关联的和已拒绝的
+func (s *KeywordService) excludedPairs(keywordIDs []uint, assigned []assignedKeyword) (map[categoryPair]bool, error) {
+	excluded := make(map[categoryPair]bool)
+	for _, a := range assigned {
+		excluded[categoryPair{a.KeywordID, a.CategoryID}] = true
+	}
+
+	var rejected []models.KeywordCategorySuggestion
+	if err := s.db.Select("keyword_id", "category_id").
+		Where("keyword_id IN ? AND status = ?", keywordIDs, SuggestionStatusRejected).
+		Find(&rejected).Error; err != nil {
+		return nil, fmt.Errorf("查询已拒绝的分类建议失败: %w", err)
+	}
+	for _, r := range rejected {
+		excluded[categoryPair{r.KeywordID, r.CategoryID}] = true
+	}
+	return excluded, nil
+}
+
+// scoreByDictionary 按分类词典打分，分类名称本身也作为词典中的词；命中的词越多置信度越高
+func scoreByDictionary(word string, categories []models.Category, scores map[uint]*categoryScore) {
+	text := strings.ToLower(word)
+	for _, category := range categories {
+		terms := append([]string{strings.ToLower(category.Name)}, category.Terms...)
+
+		var matched []string
+		seen := make(map[string]bool, len(terms))
+		for _, term := range terms {
+			if term != "" && !seen[term] && strings.Contains(text, term) {
+				matched = append(matched, term)
+			}
+			seen[term] = true
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		confidence := math.Min(0.6+0.15*float64(len(matched)-1), 0.95)
+		scoreFor(scores, category.ID).add(CategoryMethodDictionary, confidence, "分类词典: "+strings.Join(matched, "、"))
+	}
+}
+
+// scoreBySimilarity 按与分类下已有关键词的分词重合度打分，取每个分类中最相似的关键词
+func scoreBySimilarity(keyword models.Keyword, assigned []assignedKeyword, index map[string][]int, scores map[uint]*categoryScore) {
+	tokens := segment.Segment(keyword.Word)
+	if len(tokens) == 0 {
+		return
+	}
+
+	compared := make(map[int]bool)
+	best := make(map[uint]int)
+	bestScore := make(map[uint]float64)
+	for _, token := range tokens {
+		for _, i := range index[token] {
+			if compared[i] || assigned[i].KeywordID == keyword.ID {
+				continue
+			}
+			compared[i] = true
+
+			score := cluster.Dice(tokens, assigned[i].tokens)
+			if score >= categorySimilarityMin && score > bestScore[assigned[i].CategoryID] {
+				best[assigned[i].CategoryID] = i
+				bestScore[assigned[i].CategoryID] = score
+			}
+		}
+	}
+
+	for categoryID, i := range best {
+		score := bestScore[categoryID]
+		reason := fmt.Sprintf("相似关键词: %s (%.2f)", assigned[i].Word, score)
+		scoreFor(scores, categoryID).add(CategoryMethodSimilarity, 0.9*score, reason)
+	}
+}
+
+// scoreByLLM 词典和相似度把握不大的关键词交给大模型分类，返回大模型分类的关键词数和错误信息
+func (s *KeywordService) scoreByLLM(keywords []models.Keyword, categories []models.Category, scores []map[uint]*categoryScore) (int, string) {
+	if !s.categoryClassifier.Enabled() {
+		return 0, "未配置DeepSeek或Ollama"
+	}
+
+	var words []string
+	for i, keyword := range keywords {
+		top := 0.0
+		for _, score := range scores[i] {
+			top = math.Max(top, score.confidence)
+		}
+		if top < defaultCategoryLLMThreshold {
+			words = append(words, keyword.Word)
+		}
+	}
+	if len(words) == 0 {
+		return 0, ""
+	}
+
+	names := make([]string, len(categories))
+	byName := make(map[string]uint, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+		byName[category.Name] = category.ID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), categorySuggestTimeout)
+	defer cancel()
+	choices, err := s.categoryClassifier.Classify(ctx, words, names)
+
+	classified := 0
+	for i, keyword := range keywords {
+		choice, ok := choices[keyword.Word]
+		if !ok || choice.Confidence <= 0 {
+			continue
+		}
+		// 模型给出的置信度偏高，最高按0.9计算
+		scoreFor(scores[i], byName[choice.Category]).add(CategoryMethodLLM, math.Min(choice.Confidence, 0.9), "大模型分类")
+		classified++
+	}
+
+	if err != nil {
+		return classified, err.Error()
+	}
+	return classified, ""
+}
+
+// scoreFor 获取分类的得分，不存在时创建
+func scoreFor(scores map[uint]*categoryScore, categoryID uint) *categoryScore {
+	score, ok := scores[categoryID]
+	if !ok {
+		score = &categoryScore{categoryID: categoryID}
+		scores[categoryID] = score
+	}
+	return score
+}
+
+// rankCategoryScores 按置信度从高到低排列不低于minConfidence的分类
+func rankCategoryScores(scores map[uint]*categoryScore, minConfidence float64) []*categoryScore {
+	ranked := make([]*categoryScore, 0, len(scores))
+	for _, score := range scores {
+		if score.confidence >= minConfidence {
+			ranked = append(ranked, score)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].confidence != ranked[j].confidence {
+			return ranked[i].confidence > ranked[j].confidence
+		}
+		return ranked[i].categoryID < ranked[j].categoryID
+	})
+	return ranked
+}
+
+// GetCategorySuggestions 获取分类建议，按置信度从高到低排列
+func (s *KeywordService) GetCategorySuggestions(page, pageSize int, status string, categoryID *uint, minConfidence float64) ([]models.KeywordCategorySuggestion, int64, error) {
+	var suggestions []models.KeywordCategorySuggestion
+	var total int64
+
+	query := s.db.Model(&models.KeywordCategorySuggestion{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	if minConfidence > 0 {
+		query = query.Where("confidence >= ?", minConfidence)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计分类建议数量失败: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Keyword").Preload("Category").
+		Order("confidence DESC, id ASC").
+		Offset(offset).Limit(pageSize).
+		Find(&suggestions).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询分类建议失败: %w", err)
+	}
+
+	return suggestions, total, nil
+}
+
+// ReviewCategorySuggestions 批量接受或拒绝待审核的分类建议，接受时将关键词关联到分类
+func (s *KeywordService) ReviewCategorySuggestions(review KeywordCategoryReview) (*KeywordCategoryReviewResult, error) {
+	query := s.db.Where("status = ?", SuggestionStatusPending)
+	if len(review.IDs) > 0 {
+		query = query.Where("id IN ?", review.IDs)
+	} else {
+		if review.MinConfidence == nil {
+			return nil, fmt.Errorf("%w: 需要指定建议ID或最低置信度", ErrInvalidCategoryAssignment)
+		}
+		query = query.Where("confidence >= ?", *review.MinConfidence)
+		if review.CategoryID != nil {
+			query = query.Where("category_id = ?", *review.CategoryID)
+		}
+	}
+
+	var suggestions []models.KeywordCategorySuggestion
+	if err := query.Find(&suggestions).Error; err != nil {
+		return nil, fmt.Errorf("查询分类建议失败: %w", err)
+	}
+
+	result := &KeywordCategoryReviewResult{}
+	if len(suggestions) == 0 {
+		return result, nil
+	}
+
+	status := SuggestionStatusRejected
+	if review.Accept {
+		status = SuggestionStatusAccepted
+	}
+
+	ids := make([]uint, len(suggestions))
+	for i, suggestion := range suggestions {
+		ids[i] = suggestion.ID
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if review.Accept {
+		for _, suggestion := range suggestions {
+			if err := assignKeywordCategory(tx, suggestion.KeywordID, suggestion.CategoryID); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Model(&models.KeywordCategorySuggestion{}).Where("id IN ?", ids).
+		Update("status", status).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新分类建议失败: %w", err)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	if review.Accept {
+		result.Accepted = len(suggestions)
+	} else {
+		result.Rejected = len(suggestions)
+	}
+	return result, nil
+}
+
+// AssignKeywordsToCategories 批量将关键词关联到分类，已关联的保持不变，对应的待审核建议标记为已接受
+func (s *KeywordService) AssignKeywordsToCategories(keywordIDs, categoryIDs []uint) error {
+	keywordIDs = uniqueIDs(keywordIDs)
+	categoryIDs = uniqueIDs(categoryIDs)
+	if len(keywordIDs) == 0 || len(categoryIDs) == 0 {
+		return fmt.Errorf("%w: 关键词和分类不能为空", ErrInvalidCategoryAssignment)
+	}
+	if err := s.checkKeywordsAndCategories(keywordIDs, categoryIDs); err != nil {
+		return err
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	for _, keywordID := range keywordIDs {
+		for _, categoryID := range categoryIDs {
+			if err := assignKeywordCategory(tx, keywordID, categoryID); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	if err := tx.Model(&models.KeywordCategorySuggestion{}).
+		Where("keyword_id IN ? AND category_id IN ? AND status = ?", keywordIDs, categoryIDs, SuggestionStatusPending).
+		Update("status", SuggestionStatusAccepted).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("更新分类建议失败: %w", err)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// UnassignKeywordsFromCategories 批量解除关键词与分类的关联，categoryIDs为空时移出所有分类，返回解除的关联数
+func (s *KeywordService) UnassignKeywordsFromCategories(keywordIDs, categoryIDs []uint) (int64, error) {
+	keywordIDs = uniqueIDs(keywordIDs)
+	if len(keywordIDs) == 0 {
+		return 0, fmt.Errorf("%w: 关键词不能为空", ErrInvalidCategoryAssignment)
+	}
+
+	query := s.db.Table("category_keywords").Where("keyword_id IN ?", keywordIDs)
+	if len(categoryIDs) > 0 {
+		query = query.Where("category_id IN ?", categoryIDs)
+	}
+
+	result := query.Delete(nil)
+	if result.Error != nil {
+		return 0, fmt.Errorf("解除关键词和分类的关联失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// checkKeywordsAndCategories 检查关键词和分类都存在
+func (s *KeywordService) checkKeywordsAndCategories(keywordIDs, categoryIDs []uint) error {
+	var count int64
+	if err := s.db.Model(&models.Keyword{}).Where("id IN ?", keywordIDs).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询关键词失败: %w", err)
+	}
+	if int(count) != len(keywordIDs) {
+		return fmt.Errorf("%w: 部分关键词不存在", ErrInvalidCategoryAssignment)
+	}
+
+	if err := s.db.Model(&models.Category{}).Where("id IN ?", categoryIDs).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询分类失败: %w", err)
+	}
+	if int(count) != len(categoryIDs) {
+		return fmt.Errorf("%w: 部分分类不存在", ErrInvalidCategoryAssignment)
+	}
+	return nil
+}
+
+// assignKeywordCategory 在事务中关联关键词和分类，已关联时不做修改
+func assignKeywordCategory(tx *gorm.DB, keywordID, categoryID uint) error {
+	if err := tx.Exec("INSERT INTO category_keywords (category_id, keyword_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		categoryID, keywordID).Error; err != nil {
+		return fmt.Errorf("关联关键词和分类失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,537 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/cluster"
+	"github.com/NietzscheX/seo-generate/pkg/segment"
+	"gorm.io/gorm"
+)
+
+// 聚类方式
+const (
+	ClusterMethodLexical   = "lexical"   // 分词重合度
+	ClusterMethodEmbedding = "embedding" // 分词重合度和向量相似度
+	ClusterMethodManual    = "manual"    // 手动创建
+)
+
+const (
+	// MaxClusterKeywords 单次聚类最多处理的关键词数，按搜索量从高到低选取
+	MaxClusterKeywords = 5000
+	// clusterTimeout 聚类（含计算向量）的超时时间
+	clusterTimeout = 10 * time.Minute
+)
+
+// clusterSeasonTerms 季节词，不同季节的养生关键词即使字面相近也不归为同一聚类
+var clusterSeasonTerms = map[string]string{
+	"春季": "春", "春天": "春", "夏季": "夏", "夏天": "夏",
+	"秋季": "秋", "秋天": "秋", "冬季": "冬", "冬天": "冬",
+}
+
+// ErrInvalidCluster 聚类编辑参数无效
+var ErrInvalidCluster = errors.New("无效的关键词聚类")
+
+// KeywordClusterOptions 聚类选项
+type KeywordClusterOptions struct {
+	CategoryID         *uint   // 只对该分类下的关键词聚类
+	LexicalThreshold   float64 // 为0时使用默认值
+	EmbeddingThreshold float64 // 为0时使用默认值
+}
+
+// KeywordClusterResult 聚类结果
+type KeywordClusterResult struct {
+	Method      string `json:"method"`
+	Keywords    int    `json:"keywords"`    // 参与聚类的关键词数
+	Clusters    int    `json:"clusters"`    // 新建的聚类数
+	Clustered   int    `json:"clustered"`   // 归入聚类的关键词数
+	Unclustered int    `json:"unclustered"` // 没有相似关键词、未归入聚类的关键词数
+}
+
+// RebuildClusters 重新聚类：未锁定聚类中的关键词和未归类的关键词重新分组，至少两个关键词才组成聚类，
+// 搜索量最高的关键词为主关键词；手动编辑过（已锁定）的聚类保持不变
+func (s *KeywordService) RebuildClusters(opts KeywordClusterOptions) (*KeywordClusterResult, error) {
+	unlocked := s.db.Model(&models.KeywordCluster{}).Select("id").Where("locked = ?", false)
+	query := s.db.Where("status <> ?", KeywordStatusBlocked).
+		Where("cluster_id IS NULL OR cluster_id IN (?)", unlocked)
+	if opts.CategoryID != nil {
+		query = query.Where("id IN (?)", s.db.Table("category_keywords").
+			Select("keyword_id").Where("category_id = ?", *opts.CategoryID))
+	}
+
+	var keywords []models.Keyword
+	if err := query.Order("search_volume DESC, id ASC").Limit(MaxClusterKeywords).Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	result := &KeywordClusterResult{Method: ClusterMethodLexical, Keywords: len(keywords)}
+	if len(keywords) == 0 {
+		return result, nil
+	}
+
+	items := make([]cluster.Item, len(keywords))
+	for i, keyword := range keywords {
+		items[i] = cluster.Item{
+			ID:     keyword.ID,
+			Weight: keyword.SearchVolume,
+			Tokens: segment.Segment(keyword.Word),
+		}
+	}
+
+	// 配置了向量模型时同时按向量相似度聚类，向量接口不可用时只按分词重合度聚类
+	if s.embeddingClient.Enabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), clusterTimeout)
+		vectors, err := s.keywordVectors(ctx, keywords)
+		cancel()
+		if err != nil {
+			log.Printf("计算关键词向量失败，只按分词重合度聚类: %v", err)
+		} else {
+			for i := range items {
+				items[i].Vector = vectors[i]
+			}
+			result.Method = ClusterMethodEmbedding
+		}
+	}
+
+	groups := cluster.Build(items, cluster.Options{
+		LexicalThreshold:   opts.LexicalThreshold,
+		EmbeddingThreshold: opts.EmbeddingThreshold,
+		Exclusive:          clusterSeasonTerms,
+	})
+
+	ids := make([]uint, len(keywords))
+	for i, keyword := range keywords {
+		ids[i] = keyword.ID
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Model(&models.Keyword{}).Where("id IN ?", ids).Update("cluster_id", nil).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("清除关键词聚类失败: %w", err)
+	}
+
+	for _, group := range groups {
+		if len(group.Members) < 2 {
+			result.Unclustered++
+			continue
+		}
+
+		primary := keywords[group.Members[0]]
+		keywordCluster := models.KeywordCluster{
+			Name:             primary.Word,
+			PrimaryKeywordID: &primary.ID,
+			Method:           result.Method,
+		}
+		if err := tx.Create(&keywordCluster).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("创建关键词聚类失败: %w", err)
+		}
+
+		memberIDs := make([]uint, len(group.Members))
+		for i, member := range group.Members {
+			memberIDs[i] = keywords[member].ID
+		}
+		if err := tx.Model(&models.Keyword{}).Where("id IN ?", memberIDs).Update("cluster_id", keywordCluster.ID).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("更新关键词聚类失败: %w", err)
+		}
+
+		result.Clusters++
+		result.Clustered += len(memberIDs)
+	}
+
+	if err := tidyClusters(tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// keywordVectors 获取关键词的向量，优先使用缓存，缓存中没有或模型不同时重新计算并保存
+func (s *KeywordService) keywordVectors(ctx context.Context, keywords []models.Keyword) ([][]float64, error) {
+	model := s.embeddingClient.Model()
+	ids := make([]uint, len(keywords))
+	for i, keyword := range keywords {
+		ids[i] = keyword.ID
+	}
+
+	var cached []models.KeywordEmbedding
+	if err := s.db.Where("keyword_id IN ? AND model = ?", ids, model).Find(&cached).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词向量失败: %w", err)
+	}
+	byID := make(map[uint][]float64, len(cached))
+	for _, embedding := range cached {
+		byID[embedding.KeywordID] = embedding.Vector
+	}
+
+	var missing []int
+	var texts []string
+	for i, keyword := range keywords {
+		if _, ok := byID[keyword.ID]; !ok {
+			missing = append(missing, i)
+			texts = append(texts, keyword.Word)
+		}
+	}
+
+	if len(missing) > 0 {
+		vectors, err := s.embeddingClient.Embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+
+		embeddings := make([]models.KeywordEmbedding, len(missing))
+		for j, i := range missing {
+			byID[keywords[i].ID] = vectors[j]
+			embeddings[j] = models.KeywordEmbedding{KeywordID: keywords[i].ID, Model: model, Vector: vectors[j]}
+		}
+		if err := s.db.Save(&embeddings).Error; err != nil {
+			return nil, fmt.Errorf("保存关键词向量失败: %w", err)
+		}
+	}
+
+	result := make([][]float64, len(keywords))
+	for i, keyword := range keywords {
+		result[i] = byID[keyword.ID]
+	}
+	return result, nil
+}
+
+// tidyClusters 整理聚类：删除没有关键词的聚类和只剩一个关键词的未锁定聚类，主关键词不在聚类中时改为搜索量最高的关键词
+func tidyClusters(tx *gorm.DB) error {
+	// 已删除的关键词不再属于任何聚类
+	if err := tx.Unscoped().Model(&models.Keyword{}).
+		Where("deleted_at IS NOT NULL AND cluster_id IS NOT NULL").
+		Update("cluster_id", nil).Error; err != nil {
+		return fmt.Errorf("清除已删除关键词的聚类失败: %w", err)
+	}
+
+	members := tx.Model(&models.Keyword{}).Select("COUNT(*)").Where("keywords.cluster_id = keyword_clusters.id")
+	var stale []uint
+	if err := tx.Model(&models.KeywordCluster{}).
+		Where("(?) = 0 OR (locked = ? AND (?) < 2)", members, false, members).
+		Pluck("id", &stale).Error; err != nil {
+		return fmt.Errorf("查询待删除的聚类失败: %w", err)
+	}
+	if len(stale) > 0 {
+		if err := tx.Model(&models.Keyword{}).Where("cluster_id IN ?", stale).Update("cluster_id", nil).Error; err != nil {
+			return fmt.Errorf("清除关键词聚类失败: %w", err)
+		}
+		if err := tx.Delete(&models.KeywordCluster{}, stale).Error; err != nil {
+			return fmt.Errorf("删除关键词聚类失败: %w", err)
+		}
+	}
+
+	if err := tx.Exec(`UPDATE keyword_clusters SET primary_keyword_id = (
+			SELECT id FROM keywords
+			WHERE keywords.cluster_id = keyword_clusters.id AND keywords.deleted_at IS NULL
+			ORDER BY search_volume DESC, id ASC LIMIT 1
+		)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM keywords
+			WHERE keywords.id = keyword_clusters.primary_keyword_id AND keywords.cluster_id = keyword_clusters.id
+				AND keywords.deleted_at IS NULL
+		)`).Error; err != nil {
+		return fmt.Errorf("更新聚类主关键词失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetClusters 分页获取关键词聚类，按关键词数从多到少排列，q不为空时按名称筛选
+func (s *KeywordService) GetClusters(page, pageSize int, q string) ([]models.KeywordCluster, int64, error) {
+	var clusters []models.KeywordCluster
+	var total int64
+
+	query := s.db.Model(&models.KeywordCluster{})
+	if q != "" {
+		query = query.Where("name LIKE ?", "%"+q+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计关键词聚类数量失败: %w", err)
+	}
+
+	members := s.db.Model(&models.Keyword{}).Select("COUNT(*)").Where("keywords.cluster_id = keyword_clusters.id")
+	offset := (page - 1) * pageSize
+	if err := query.Select("keyword_clusters.*, (?) AS keyword_count", members).
+		Preload("PrimaryKeyword").
+		Order("keyword_count DESC, keyword_clusters.id ASC").
+		Offset(offset).Limit(pageSize).
+		Find(&clusters).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询关键词聚类失败: %w", err)
+	}
+
+	return clusters, total, nil
+}
+
+// GetCluster 获取关键词聚类及其关键词，主关键词在前，其余按搜索量从高到低排列
+func (s *KeywordService) GetCluster(id uint) (*models.KeywordCluster, error) {
+	var keywordCluster models.KeywordCluster
+	if err := s.db.Preload("PrimaryKeyword").
+		Preload("Keywords", func(db *gorm.DB) *gorm.DB {
+			return db.Order("search_volume DESC, id ASC")
+		}).
+		First(&keywordCluster, id).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词聚类失败: %w", err)
+	}
+
+	for i, keyword := range keywordCluster.Keywords {
+		if keywordCluster.PrimaryKeywordID != nil && keyword.ID == *keywordCluster.PrimaryKeywordID {
+			copy(keywordCluster.Keywords[1:i+1], keywordCluster.Keywords[:i])
+			keywordCluster.Keywords[0] = keyword
+			break
+		}
+	}
+	keywordCluster.KeywordCount = len(keywordCluster.Keywords)
+
+	return &keywordCluster, nil
+}
+
+// ClusterKeywords 获取聚类的主关键词和次要关键词，次要关键词按搜索量从高到低排列，最多limit个
+func (s *KeywordService) ClusterKeywords(id uint, limit int) (*models.Keyword, []string, error) {
+	keywordCluster, err := s.GetCluster(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keywordCluster.Keywords) == 0 || keywordCluster.PrimaryKeywordID == nil {
+		return nil, nil, fmt.Errorf("%w: 聚类没有关键词", ErrInvalidCluster)
+	}
+
+	primary := keywordCluster.Keywords[0]
+	secondary := make([]string, 0, len(keywordCluster.Keywords)-1)
+	for _, keyword := range keywordCluster.Keywords[1:] {
+		if len(secondary) >= limit {
+			break
+		}
+		secondary = append(secondary, keyword.Word)
+	}
+	return &primary, secondary, nil
+}
+
+// CreateCluster 手动创建聚类，关键词从原来的聚类中移出；未指定主关键词时使用搜索量最高的关键词
+func (s *KeywordService) CreateCluster(name string, keywordIDs []uint, primaryKeywordID *uint) (*models.KeywordCluster, error) {
+	if len(keywordIDs) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一个关键词", ErrInvalidCluster)
+	}
+	if primaryKeywordID != nil && !containsID(keywordIDs, *primaryKeywordID) {
+		return nil, fmt.Errorf("%w: 主关键词必须属于聚类", ErrInvalidCluster)
+	}
+
+	var keywords []models.Keyword
+	if err := s.db.Where("id IN ?", keywordIDs).Order("search_volume DESC, id ASC").Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+	if len(keywords) != len(uniqueIDs(keywordIDs)) {
+		return nil, fmt.Errorf("%w: 关键词不存在", ErrInvalidCluster)
+	}
+	if primaryKeywordID == nil {
+		primaryKeywordID = &keywords[0].ID
+	}
+	if name == "" {
+		for _, keyword := range keywords {
+			if keyword.ID == *primaryKeywordID {
+				name = keyword.Word
+			}
+		}
+	}
+
+	keywordCluster := models.KeywordCluster{
+		Name:             name,
+		PrimaryKeywordID: primaryKeywordID,
+		Method:           ClusterMethodManual,
+		Locked:           true,
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Create(&keywordCluster).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建关键词聚类失败: %w", err)
+	}
+	if err := s.moveKeywords(tx, keywordCluster.ID, keywordIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetCluster(keywordCluster.ID)
+}
+
+// UpdateCluster 修改聚类名称、主关键词或锁定状态，修改名称或主关键词后聚类自动锁定
+func (s *KeywordService) UpdateCluster(id uint, name *string, primaryKeywordID *uint, locked *bool) (*models.KeywordCluster, error) {
+	var keywordCluster models.KeywordCluster
+	if err := s.db.First(&keywordCluster, id).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词聚类失败: %w", err)
+	}
+
+	updates := map[string]interface{}{}
+	if name != nil {
+		if *name == "" {
+			return nil, fmt.Errorf("%w: 名称不能为空", ErrInvalidCluster)
+		}
+		updates["name"] = *name
+		updates["locked"] = true
+	}
+	if primaryKeywordID != nil {
+		var count int64
+		if err := s.db.Model(&models.Keyword{}).Where("id = ? AND cluster_id = ?", *primaryKeywordID, id).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("查询关键词失败: %w", err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: 主关键词必须属于聚类", ErrInvalidCluster)
+		}
+		updates["primary_keyword_id"] = *primaryKeywordID
+		updates["locked"] = true
+	}
+	if locked != nil {
+		updates["locked"] = *locked
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&keywordCluster).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("更新关键词聚类失败: %w", err)
+		}
+	}
+
+	return s.GetCluster(id)
+}
+
+// AddClusterKeywords 将关键词加入聚类（从原来的聚类中移出），聚类自动锁定
+func (s *KeywordService) AddClusterKeywords(id uint, keywordIDs []uint) (*models.KeywordCluster, error) {
+	if len(keywordIDs) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一个关键词", ErrInvalidCluster)
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Keyword{}).Where("id IN ?", keywordIDs).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+	if int(count) != len(uniqueIDs(keywordIDs)) {
+		return nil, fmt.Errorf("%w: 关键词不存在", ErrInvalidCluster)
+	}
+
+	var keywordCluster models.KeywordCluster
+	if err := s.db.First(&keywordCluster, id).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词聚类失败: %w", err)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Model(&keywordCluster).Update("locked", true).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新关键词聚类失败: %w", err)
+	}
+	if err := s.moveKeywords(tx, id, keywordIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetCluster(id)
+}
+
+// RemoveClusterKeyword 将关键词移出聚类，聚类自动锁定；移出主关键词时改为搜索量最高的关键词
+func (s *KeywordService) RemoveClusterKeyword(id, keywordID uint) error {
+	var keywordCluster models.KeywordCluster
+	if err := s.db.First(&keywordCluster, id).Error; err != nil {
+		return fmt.Errorf("查询关键词聚类失败: %w", err)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Model(&models.Keyword{}).Where("id = ? AND cluster_id = ?", keywordID, id).Update("cluster_id", nil).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("移出关键词失败: %w", err)
+	}
+	if err := tx.Model(&keywordCluster).Update("locked", true).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("更新关键词聚类失败: %w", err)
+	}
+	if err := tidyClusters(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCluster 删除聚类，其中的关键词变为未归类
+func (s *KeywordService) DeleteCluster(id uint) error {
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Model(&models.Keyword{}).Where("cluster_id = ?", id).Update("cluster_id", nil).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("清除关键词聚类失败: %w", err)
+	}
+	if err := tx.Delete(&models.KeywordCluster{}, id).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除关键词聚类失败: %w", err)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
+}
+
+// moveKeywords 将关键词移入聚类并整理受影响的聚类
+func (s *KeywordService) moveKeywords(tx *gorm.DB, clusterID uint, keywordIDs []uint) error {
+	if err := tx.Model(&models.Keyword{}).Where("id IN ?", keywordIDs).Update("cluster_id", clusterID).Error; err != nil {
+		return fmt.Errorf("更新关键词聚类失败: %w", err)
+	}
+	return tidyClusters(tx)
+}
+
+// containsID ID列表中是否包含指定ID
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueIDs 去重后的ID列表
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
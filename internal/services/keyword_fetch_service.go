@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+)
+
+const (
+	// defaultKeywordFetchLimit 未指定数量时获取的关键词数
+	defaultKeywordFetchLimit = 100
+	// maxKeywordFetchLimit 单次最多获取的关键词数
+	maxKeywordFetchLimit = 1000
+	// keywordFetchTimeout 单次获取的超时时间，5118分页和下拉词扩展之间都有等待
+	keywordFetchTimeout = 5 * time.Minute
+)
+
+// 关键词获取状态
+const (
+	KeywordFetchStatusSuccess = "success"
+	KeywordFetchStatusFailed  = "failed"
+)
+
+var (
+	// ErrUnknownKeywordSource 关键词来源不存在
+	ErrUnknownKeywordSource = errors.New("未知的关键词来源")
+	// ErrKeywordSourceDisabled 关键词来源未配置
+	ErrKeywordSourceDisabled = errors.New("关键词来源未配置")
+	// ErrKeywordQuotaExhausted 关键词来源当日配额已用完
+	ErrKeywordQuotaExhausted = errors.New("关键词来源当日配额已用完")
+)
+
+// KeywordFetchRequest 关键词获取请求
+type KeywordFetchRequest struct {
+	Source string // 为空时使用5118
+	Seed   string
+	Limit  int
+	HTML   string
+	UserID *uint
+}
+
+// KeywordFetchResponse 关键词获取结果
+type KeywordFetchResponse struct {
+	Fetch    models.KeywordFetch `json:"fetch"`
+	Keywords []models.Keyword    `json:"keywords"`
+}
+
+// KeywordSourceQuota 关键词来源当日配额使用情况
+type KeywordSourceQuota struct {
+	Source     string `json:"source"`
+	Enabled    bool   `json:"enabled"`
+	DailyQuota int    `json:"daily_quota"` // 0表示不限制
+	UsedToday  int64  `json:"used_today"`
+	Exhausted  bool   `json:"exhausted"`
+}
+
+// source 按名称查找关键词来源
+func (s *KeywordService) source(name string) (seo.KeywordSource, error) {
+	if name == "" {
+		name = seo.KeywordSource5118
+	}
+	for _, source := range s.sources {
+		if source.Name() == name {
+			return source, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKeywordSource, name)
+}
+
+// quotaGroup 共用配额的来源使用同一个名称统计，5118的各个接口共用5118的配额
+func quotaGroup(source string) string {
+	if source == seo.KeywordSource5118LongTail {
+		return seo.KeywordSource5118
+	}
+	return source
+}
+
+// dailyQuota 配置的每日请求次数配额，0表示不限制
+func (s *KeywordService) dailyQuota(source string) int {
+	switch quotaGroup(source) {
+	case seo.KeywordSource5118:
+		return s.config.KeywordSources.API5118DailyQuota
+	case seo.KeywordSourceBaiduSuggest:
+		return s.config.KeywordSources.BaiduSuggestDailyQuota
+	case seo.KeywordSourceLLM:
+		return s.config.KeywordSources.LLMDailyQuota
+	}
+	return 0
+}
+
+// usedToday 统计来源当日请求外部接口的次数：5118按API调用日志统计（包括指标查询），其他来源按获取记录统计
+func (s *KeywordService) usedToday(source string) (int64, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var used int64
+	if quotaGroup(source) == seo.KeywordSource5118 {
+		if err := s.db.Model(&models.APILog{}).
+			Where("api_name = ? AND created_at >= ?", seo.KeywordSource5118, today).
+			Count(&used).Error; err != nil {
+			return 0, fmt.Errorf("统计5118调用次数失败: %w", err)
+		}
+		return used, nil
+	}
+
+	if err := s.db.Model(&models.KeywordFetch{}).
+		Where("source = ? AND created_at >= ?", source, today).
+		Select("COALESCE(SUM(api_calls), 0)").
+		Scan(&used).Error; err != nil {
+		return 0, fmt.Errorf("统计请求次数失败: %w", err)
+	}
+	return used, nil
+}
+
+// quota 统计关键词来源当日的配额使用情况
+func (s *KeywordService) quota(source seo.KeywordSource) (*KeywordSourceQuota, error) {
+	used, err := s.usedToday(source.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &KeywordSourceQuota{
+		Source:     source.Name(),
+		Enabled:    source.Enabled(),
+		DailyQuota: s.dailyQuota(source.Name()),
+		UsedToday:  used,
+	}
+	quota.Exhausted = quota.DailyQuota > 0 && quota.UsedToday >= int64(quota.DailyQuota)
+	return quota, nil
+}
+
+// GetSourceQuotas 获取各关键词来源当日的配额使用情况
+func (s *KeywordService) GetSourceQuotas() ([]KeywordSourceQuota, error) {
+	quotas := make([]KeywordSourceQuota, 0, len(s.sources))
+	for _, source := range s.sources {
+		quota, err := s.quota(source)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, *quota)
+	}
+	return quotas, nil
+}
+
+// FetchKeywords 从指定来源获取关键词，清洗后保存并记录每个关键词的来源
+func (s *KeywordService) FetchKeywords(req KeywordFetchRequest) (*KeywordFetchResponse, error) {
+	source, err := s.source(req.Source)
+	if err != nil {
+		return nil, err
+	}
+	if !source.Enabled() {
+		return nil, fmt.Errorf("%w: %s", ErrKeywordSourceDisabled, source.Name())
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultKeywordFetchLimit
+	}
+	if req.Limit > maxKeywordFetchLimit {
+		req.Limit = maxKeywordFetchLimit
+	}
+
+	// 配额按剩余请求次数传给来源，来源达到次数后停止
+	quota, err := s.quota(source)
+	if err != nil {
+		return nil, err
+	}
+	if quota.Exhausted {
+		return nil, fmt.Errorf("%w: %s", ErrKeywordQuotaExhausted, source.Name())
+	}
+	maxRequests := 0
+	if quota.DailyQuota > 0 {
+		maxRequests = quota.DailyQuota - int(quota.UsedToday)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keywordFetchTimeout)
+	defer cancel()
+
+	result, fetchErr := source.Fetch(ctx, seo.KeywordQuery{
+		Seed:        req.Seed,
+		Limit:       req.Limit,
+		HTML:        req.HTML,
+		MaxRequests: maxRequests,
+	})
+	if result == nil {
+		result = &seo.KeywordFetchResult{}
+	}
+
+	fetch := models.KeywordFetch{
+		Source:    source.Name(),
+		Seed:      req.Seed,
+		Requested: req.Limit,
+		APICalls:  result.Requests,
+		Returned:  len(result.Keywords),
+		Status:    KeywordFetchStatusSuccess,
+		UserID:    req.UserID,
+	}
+
+	if errors.Is(fetchErr, seo.ErrInvalidKeywordQuery) {
+		return nil, fetchErr
+	}
+
+	// 获取失败时也记录请求次数，用于统计配额
+	if fetchErr != nil {
+		fetch.Status = KeywordFetchStatusFailed
+		fetch.Error = fetchErr.Error()
+		if err := s.db.Create(&fetch).Error; err != nil {
+			return nil, fmt.Errorf("保存获取记录失败: %w", err)
+		}
+		return nil, fmt.Errorf("从%s获取关键词失败: %w", source.Name(), fetchErr)
+	}
+
+	// 记录关键词在来源结果中第一次出现的位置
+	ranks := make(map[string]int, len(result.Keywords))
+	for i, keyword := range result.Keywords {
+		if _, ok := ranks[keyword.Word]; !ok {
+			ranks[keyword.Word] = i + 1
+		}
+	}
+
+	// 清洗关键词，保持来源返回的顺序
+	keywords := seo.CleanKeywords(result.Keywords)
+	sort.Slice(keywords, func(i, j int) bool {
+		return ranks[keywords[i].Word] < ranks[keywords[j].Word]
+	})
+	fetch.Invalid = len(result.Keywords) - len(keywords)
+
+	// 命中屏蔽规则的关键词标记为已屏蔽，不计入保存的关键词
+	keywords, blocked, blockedReport, err := s.filterBlocked(keywords)
+	if err != nil {
+		return nil, err
+	}
+	fetch.Blocked = len(blockedReport)
+	fetch.BlockedKeywords = blockedReport
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Create(&fetch).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("保存获取记录失败: %w", err)
+	}
+
+	attributions := make([]models.KeywordAttribution, len(keywords))
+	for i, keyword := range keywords {
+		attributions[i] = models.KeywordAttribution{
+			FetchID:      fetch.ID,
+			Source:       source.Name(),
+			Seed:         req.Seed,
+			Rank:         ranks[keyword.Word],
+			SearchVolume: keyword.SearchVolume,
+		}
+	}
+
+	if err := blockKeywords(tx, blocked, blockedReport, source.Name()); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	actions, err := upsertKeywords(tx, keywords, source.Name())
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var created []uint
+	for i := range attributions {
+		attributions[i].KeywordID = keywords[i].ID
+		if actions[i] == KeywordActionCreate {
+			fetch.Created++
+			created = append(created, keywords[i].ID)
+		}
+	}
+	if len(attributions) > 0 {
+		if err := tx.Create(&attributions).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存关键词来源失败: %w", err)
+		}
+	}
+
+	// 来源同时返回指标时保存指标快照
+	if metrics := metricsForKeywords(keywords, result.Metrics, fetch.CreatedAt); len(metrics) > 0 {
+		if err := tx.Create(&metrics).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存关键词指标失败: %w", err)
+		}
+	}
+
+	fetch.Saved = len(keywords)
+	if err := tx.Model(&fetch).Updates(map[string]interface{}{
+		"saved":   fetch.Saved,
+		"created": fetch.Created,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("更新获取记录失败: %w", err)
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	s.suggestNewKeywordCategories(created)
+
+	return &KeywordFetchResponse{Fetch: fetch, Keywords: keywords}, nil
+}
+
+// GetFetches 获取关键词获取记录
+func (s *KeywordService) GetFetches(page, pageSize int, source, status string) ([]models.KeywordFetch, int64, error) {
+	var fetches []models.KeywordFetch
+	var total int64
+
+	query := s.db.Model(&models.KeywordFetch{})
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计获取记录数量失败: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&fetches).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询获取记录失败: %w", err)
+	}
+
+	return fetches, total, nil
+}
+
+// GetKeywordAttributions 获取关键词的来源记录，最早发现的在前
+func (s *KeywordService) GetKeywordAttributions(keywordID uint) ([]models.KeywordAttribution, error) {
+	if err := s.db.First(&models.Keyword{}, keywordID).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	var attributions []models.KeywordAttribution
+	if err := s.db.Where("keyword_id = ?", keywordID).Order("created_at ASC, id ASC").Find(&attributions).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词来源失败: %w", err)
+	}
+	return attributions, nil
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/NietzscheX/seo-generate/pkg/spreadsheet"
+	"gorm.io/gorm"
+)
+
+// 导入表格中可映射的字段
+const (
+	KeywordFieldWord         = "word"
+	KeywordFieldSearchVolume = "search_volume"
+	KeywordFieldCategory     = "category"
+)
+
+// KeywordActionSkip 导入时跳过的行
+const KeywordActionSkip = "skip"
+
+// keywordImportPreviewRows 导入结果中预览的行数
+const keywordImportPreviewRows = 100
+
+// keywordColumnAliases 未指定列映射时按表头自动识别的列名
+var keywordColumnAliases = map[string][]string{
+	KeywordFieldWord:         {"关键词", "关键字", "keyword", "word"},
+	KeywordFieldSearchVolume: {"搜索量", "日均搜索量", "搜索指数", "整体日均值", "指数", "search_volume", "volume"},
+	KeywordFieldCategory:     {"分类", "category", "categories"},
+}
+
+// keywordExportHeader 导出表格的表头，与导入时自动识别的列名一致
+var keywordExportHeader = []string{"关键词", "搜索量", "分类", "来源", "状态", "创建时间"}
+
+// KeywordImportOptions 关键词导入选项
+type KeywordImportOptions struct {
+	Mapping          map[string]string // 字段到列的映射，列可以是表头名称或列名（如"B"）
+	Source           string            // 新建关键词的来源，默认为import
+	CategoryID       *uint             // 所有关键词都分配到的分类
+	CreateCategories bool              // 分类列中的分类不存在时自动创建
+	DryRun           bool              // 只预览结果，不写入数据库
+}
+
+// KeywordImportRow 导入表格中的一行
+type KeywordImportRow struct {
+	Row          int      `json:"row"`
+	Word         string   `json:"word"`
+	SearchVolume int      `json:"search_volume"`
+	Categories   []string `json:"categories,omitempty"`
+	Action       string   `json:"action"` // create, update, unchanged, skip, blocked
+	Error        string   `json:"error,omitempty"`
+}
+
+// KeywordImportResult 关键词导入结果
+type KeywordImportResult struct {
+	DryRun            bool               `json:"dry_run"`
+	Columns           map[string]string  `json:"columns"`
+	Total             int                `json:"total"`
+	Created           int                `json:"created"`
+	Updated           int                `json:"updated"`
+	Unchanged         int                `json:"unchanged"`
+	Skipped           int                `json:"skipped"`
+	Blocked           int                `json:"blocked"` // 命中屏蔽规则，已标记为屏蔽的关键词数
+	CreatedCategories []string           `json:"created_categories,omitempty"`
+	Preview           []KeywordImportRow `json:"preview"`
+	Errors            []KeywordImportRow `json:"errors"`
+}
+
+// KeywordExportFilter 关键词导出条件
+type KeywordExportFilter struct {
+	CategoryID *uint
+	Source     string
+	Status     string
+	MinVolume  *int
+	MaxVolume  *int
+	Query      string
+}
+
+// ImportKeywords 从CSV或XLSX表格导入关键词，第一行为表头
+func (s *KeywordService) ImportKeywords(filename string, data []byte, opts KeywordImportOptions) (*KeywordImportResult, error) {
+	format, err := spreadsheet.FormatOf(filename)
+	if err != nil {
+		return nil, err
+	}
+	table, err := spreadsheet.Read(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("读取表格失败: %w", err)
+	}
+	if len(table) == 0 {
+		return nil, fmt.Errorf("表格为空")
+	}
+
+	columns, err := keywordColumns(table[0], opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Source == "" {
+		opts.Source = KeywordSourceImport
+	}
+
+	result := &KeywordImportResult{
+		DryRun:  opts.DryRun,
+		Columns: make(map[string]string, len(columns)),
+		Preview: []KeywordImportRow{},
+		Errors:  []KeywordImportRow{},
+	}
+	for field, index := range columns {
+		result.Columns[field] = columnLabel(table[0], index)
+	}
+
+	rows := parseKeywordRows(table, columns)
+	result.Total = len(rows)
+
+	// 开始事务，预览时同样写入后回滚，保证预览结果与实际导入一致
+	tx := s.db.Begin()
+
+	if err := s.importKeywordRows(tx, rows, opts, result); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if opts.DryRun {
+		tx.Rollback()
+	} else if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	var created []string
+
+	for _, row := range rows {
+		switch row.Action {
+		case KeywordActionCreate:
+			result.Created++
+			created = append(created, row.Word)
+		case KeywordActionUpdate:
+			result.Updated++
+		case KeywordActionUnchanged:
+			result.Unchanged++
+		case KeywordActionBlocked:
+			result.Blocked++
+			result.Errors = append(result.Errors, *row)
+		default:
+			result.Skipped++
+			result.Errors = append(result.Errors, *row)
+		}
+		if len(result.Preview) < keywordImportPreviewRows {
+			result.Preview = append(result.Preview, *row)
+		}
+	}
+
+	// 为新建的、表格中没有指定分类的关键词生成分类建议
+	if !opts.DryRun && len(created) > 0 {
+		var createdIDs []uint
+		if err := s.db.Model(&models.Keyword{}).Where("word IN ?", created).Pluck("id", &createdIDs).Error; err != nil {
+			return nil, fmt.Errorf("查询新建关键词失败: %w", err)
+		}
+		s.suggestNewKeywordCategories(createdIDs)
+	}
+
+	return result, nil
+}
+
+// importKeywordRows 在事务中解析分类并写入关键词
+func (s *KeywordService) importKeywordRows(tx *gorm.DB, rows []*KeywordImportRow, opts KeywordImportOptions, result *KeywordImportResult) error {
+	var fixed *models.Category
+	if opts.CategoryID != nil {
+		fixed = &models.Category{}
+		if err := tx.First(fixed, *opts.CategoryID).Error; err != nil {
+			return fmt.Errorf("查询分类失败: %w", err)
+		}
+	}
+
+	// 按名称查找分类列中的分类
+	categories := make(map[string]*models.Category)
+	for _, row := range rows {
+		for _, name := range row.Categories {
+			if _, ok := categories[name]; ok {
+				continue
+			}
+
+			var category models.Category
+			err := tx.Where("name = ?", name).First(&category).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) && opts.CreateCategories {
+				category = models.Category{Name: name}
+				if err := tx.Create(&category).Error; err != nil {
+					return fmt.Errorf("创建分类失败: %w", err)
+				}
+				result.CreatedCategories = append(result.CreatedCategories, name)
+			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				categories[name] = nil
+				continue
+			} else if err != nil {
+				return fmt.Errorf("查询分类失败: %w", err)
+			}
+			categories[name] = &category
+		}
+	}
+
+	blocklist, err := s.getBlocklist()
+	if err != nil {
+		return err
+	}
+
+	var valid []*KeywordImportRow
+	var keywords []models.Keyword
+	var blocked []models.Keyword
+	var blockedReport []models.BlockedKeyword
+	for _, row := range rows {
+		if row.Action == KeywordActionSkip {
+			continue
+		}
+		for _, name := range row.Categories {
+			if categories[name] == nil {
+				row.Action = KeywordActionSkip
+				row.Error = fmt.Sprintf("分类不存在: %s", name)
+				break
+			}
+		}
+		if row.Action == KeywordActionSkip {
+			continue
+		}
+		if rule, ok := blocklist.Match(row.Word); ok {
+			row.Action = KeywordActionBlocked
+			row.Error = "已屏蔽: " + rule.Reason()
+			blocked = append(blocked, models.Keyword{Word: row.Word, SearchVolume: row.SearchVolume})
+			blockedReport = append(blockedReport, models.BlockedKeyword{Word: row.Word, RuleID: rule.ID, Reason: rule.Reason()})
+			continue
+		}
+		valid = append(valid, row)
+		keywords = append(keywords, models.Keyword{Word: row.Word, SearchVolume: row.SearchVolume})
+	}
+
+	if err := blockKeywords(tx, blocked, blockedReport, opts.Source); err != nil {
+		return err
+	}
+
+	actions, err := upsertKeywords(tx, keywords, opts.Source)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range valid {
+		row.Action = actions[i]
+
+		var assign []models.Category
+		if fixed != nil {
+			assign = append(assign, *fixed)
+		}
+		for _, name := range row.Categories {
+			assign = append(assign, *categories[name])
+		}
+		if len(assign) == 0 {
+			continue
+		}
+		if err := tx.Model(&keywords[i]).Association("Categories").Append(assign); err != nil {
+			return fmt.Errorf("关联关键词和分类失败: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// keywordColumns 根据映射或表头确定各字段所在的列，关键词列必须存在
+func keywordColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	columns := make(map[string]int)
+
+	for field, column := range mapping {
+		if _, ok := keywordColumnAliases[field]; !ok {
+			return nil, fmt.Errorf("未知的字段: %s", field)
+		}
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+
+		index := headerIndex(header, column)
+		if index < 0 {
+			var ok bool
+			if index, ok = spreadsheet.ColumnIndex(column); !ok {
+				return nil, fmt.Errorf("表格中没有列: %s", column)
+			}
+		}
+		columns[field] = index
+	}
+
+	// 未映射的字段按表头自动识别
+	for field, aliases := range keywordColumnAliases {
+		if _, ok := mapping[field]; ok {
+			continue
+		}
+		for _, alias := range aliases {
+			if index := headerIndex(header, alias); index >= 0 {
+				columns[field] = index
+				break
+			}
+		}
+	}
+
+	if _, ok := columns[KeywordFieldWord]; !ok {
+		return nil, fmt.Errorf("未找到关键词列，请指定列映射")
+	}
+	return columns, nil
+}
+
+// headerIndex 查找表头中的列，不区分大小写，找不到时返回-1
+func headerIndex(header []string, name string) int {
+	for i, cell := range header {
+		if strings.EqualFold(strings.TrimSpace(cell), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnLabel 列的表头名称，表头为空时使用列号
+func columnLabel(header []string, index int) string {
+	if index < len(header) && strings.TrimSpace(header[index]) != "" {
+		return strings.TrimSpace(header[index])
+	}
+	return fmt.Sprintf("第%d列", index+1)
+}
+
+// parseKeywordRows 解析表头以外的行，无效行和表格内的重复关键词标记为跳过
+func parseKeywordRows(table [][]string, columns map[string]int) []*KeywordImportRow {
+	cell := func(record []string, field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	rows := make([]*KeywordImportRow, 0, len(table)-1)
+	seen := make(map[string]*KeywordImportRow)
+	for i, record := range table[1:] {
+		word := seo.NormalizeSpaces(cell(record, KeywordFieldWord))
+		volume := cell(record, KeywordFieldSearchVolume)
+		category := cell(record, KeywordFieldCategory)
+		if word == "" && volume == "" && category == "" {
+			continue // 跳过空行
+		}
+
+		row := &KeywordImportRow{
+			Row:        i + 2, // 表格中的行号，表头为第1行
+			Word:       word,
+			Categories: splitCategories(category),
+		}
+		rows = append(rows, row)
+
+		searchVolume, err := parseSearchVolume(volume)
+		if err != nil {
+			row.Action = KeywordActionSkip
+			row.Error = fmt.Sprintf("无效的搜索量: %s", volume)
+			continue
+		}
+		row.SearchVolume = searchVolume
+
+		if !seo.ValidKeyword(word) {
+			row.Action = KeywordActionSkip
+			row.Error = "关键词为空或长度不合适"
+			continue
+		}
+
+		// 表格内重复的关键词合并到第一次出现的行：保留较高的搜索量，合并分类
+		if first, ok := seen[word]; ok {
+			first.SearchVolume = max(first.SearchVolume, row.SearchVolume)
+			first.Categories = mergeCategories(first.Categories, row.Categories)
+			row.Action = KeywordActionSkip
+			row.Error = fmt.Sprintf("与第%d行重复，已合并", first.Row)
+			continue
+		}
+		seen[word] = row
+	}
+
+	return rows
+}
+
+// splitCategories 拆分分类列，多个分类可用逗号、分号、顿号或竖线分隔
+func splitCategories(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(",，;；、|", r)
+	})
+	return mergeCategories(nil, fields)
+}
+
+// mergeCategories 合并分类名称并去重
+func mergeCategories(names, more []string) []string {
+	for _, name := range more {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range names {
+			if existing == name {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseSearchVolume 解析搜索量，支持千分位和"万"为单位，空值为0
+func parseSearchVolume(value string) (int, error) {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, ",", ""), " ", "")
+	if value == "" || value == "-" {
+		return 0, nil
+	}
+
+	multiplier := 1.0
+	if strings.HasSuffix(value, "万") {
+		multiplier = 10000
+		value = strings.TrimSuffix(value, "万")
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) || n*multiplier > math.MaxInt32 {
+		return 0, fmt.Errorf("无效的搜索量: %s", value)
+	}
+	return int(math.Round(n * multiplier)), nil
+}
+
+// ExportKeywords 按条件导出关键词，返回包含表头的表格行
+func (s *KeywordService) ExportKeywords(filter KeywordExportFilter) ([][]string, error) {
+	query := s.db.Model(&models.Keyword{}).Preload("Categories")
+
+	if filter.CategoryID != nil {
+		query = query.Where("id IN (?)", s.db.Table("category_keywords").
+			Select("keyword_id").Where("category_id = ?", *filter.CategoryID))
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MinVolume != nil {
+		query = query.Where("search_volume >= ?", *filter.MinVolume)
+	}
+	if filter.MaxVolume != nil {
+		query = query.Where("search_volume <= ?", *filter.MaxVolume)
+	}
+	if filter.Query != "" {
+		query = query.Where("word LIKE ?", "%"+filter.Query+"%")
+	}
+
+	var keywords []models.Keyword
+	if err := query.Order("search_volume DESC, id ASC").Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	rows := make([][]string, 0, len(keywords)+1)
+	rows = append(rows, keywordExportHeader)
+	for _, keyword := range keywords {
+		names := make([]string, 0, len(keyword.Categories))
+		for _, category := range keyword.Categories {
+			names = append(names, category.Name)
+		}
+		rows = append(rows, []string{
+			keyword.Word,
+			strconv.Itoa(keyword.SearchVolume),
+			strings.Join(names, ","),
+			keyword.Source,
+			keyword.Status,
+			keyword.CreatedAt.Format(time.DateTime),
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+)
+
+// 意图来源
+const (
+	IntentSourceRule   = "rule"   // 规则分类
+	IntentSourceLLM    = "llm"    // 大模型分类
+	IntentSourceManual = "manual" // 人工指定，自动分类不会覆盖
+)
+
+const (
+	// MaxIntentKeywords 单次分类最多处理的关键词数
+	MaxIntentKeywords = 5000
+	// defaultIntentLLMThreshold 规则分类置信度低于该值时交给大模型
+	defaultIntentLLMThreshold = 0.8
+	// intentTimeout 大模型分类的超时时间
+	intentTimeout = 10 * time.Minute
+)
+
+// ErrInvalidIntent 关键词意图无效
+var ErrInvalidIntent = errors.New("无效的关键词意图")
+
+// KeywordIntentOptions 意图分类选项
+type KeywordIntentOptions struct {
+	KeywordIDs []uint  // 只分类指定的关键词，为空时分类所有尚未分类的关键词
+	Reclassify bool    // KeywordIDs为空时重新分类所有关键词，人工指定的除外
+	UseLLM     bool    // 规则置信度较低的关键词交给大模型分类
+	Threshold  float64 // 规则置信度低于该值时交给大模型，为0时使用默认值
+}
+
+// KeywordIntentResult 意图分类结果
+type KeywordIntentResult struct {
+	Processed      int            `json:"processed"`
+	Changed        int            `json:"changed"`
+	LLMClassified  int            `json:"llm_classified"`
+	LLMError       string         `json:"llm_error,omitempty"` // 大模型分类失败时保留规则分类结果
+	ByIntent       map[string]int `json:"by_intent"`
+	ByDomainIntent map[string]int `json:"by_domain_intent"`
+}
+
+// applyIntent 设置关键词的意图
+func applyIntent(keyword *models.Keyword, result seo.IntentResult, source string) {
+	keyword.Intent = result.Intent
+	keyword.DomainIntent = result.DomainIntent
+	keyword.IntentSource = source
+}
+
+// ClassifyIntents 按规则判断关键词意图，可选使用大模型复核规则置信度较低的关键词；人工指定的意图不会被覆盖
+func (s *KeywordService) ClassifyIntents(opts KeywordIntentOptions) (*KeywordIntentResult, error) {
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaultIntentLLMThreshold
+	}
+
+	query := s.db.Where("intent_source <> ?", IntentSourceManual)
+	if len(opts.KeywordIDs) > 0 {
+		query = query.Where("id IN ?", opts.KeywordIDs)
+	} else if !opts.Reclassify {
+		query = query.Where("intent = ''")
+	}
+
+	var keywords []models.Keyword
+	if err := query.Order("search_volume DESC, id ASC").Limit(MaxIntentKeywords).Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	result := &KeywordIntentResult{
+		Processed:      len(keywords),
+		ByIntent:       make(map[string]int),
+		ByDomainIntent: make(map[string]int),
+	}
+
+	classified := make([]models.Keyword, len(keywords))
+	var uncertain []string
+	for i, keyword := range keywords {
+		classified[i] = keyword
+		intent := seo.ClassifyIntent(keyword.Word)
+		applyIntent(&classified[i], intent, IntentSourceRule)
+		if intent.Confidence < opts.Threshold {
+			uncertain = append(uncertain, keyword.Word)
+		}
+	}
+
+	if opts.UseLLM && len(uncertain) > 0 {
+		if !s.intentClassifier.Enabled() {
+			result.LLMError = "未配置DeepSeek或Ollama"
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), intentTimeout)
+			llmResults, err := s.intentClassifier.Classify(ctx, uncertain)
+			cancel()
+			if err != nil {
+				result.LLMError = err.Error()
+			}
+			for i := range classified {
+				if intent, ok := llmResults[classified[i].Word]; ok {
+					applyIntent(&classified[i], intent, IntentSourceLLM)
+					result.LLMClassified++
+				}
+			}
+		}
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	for i, keyword := range classified {
+		result.ByIntent[keyword.Intent]++
+		if keyword.DomainIntent != "" {
+			result.ByDomainIntent[keyword.DomainIntent]++
+		}
+
+		original := keywords[i]
+		if original.Intent == keyword.Intent && original.DomainIntent == keyword.DomainIntent && original.IntentSource == keyword.IntentSource {
+			continue
+		}
+		if err := tx.Model(&models.Keyword{}).Where("id = ?", keyword.ID).Updates(map[string]interface{}{
+			"intent":        keyword.Intent,
+			"domain_intent": keyword.DomainIntent,
+			"intent_source": keyword.IntentSource,
+		}).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("更新关键词意图失败: %w", err)
+		}
+		result.Changed++
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetKeywordIntent 人工指定关键词意图，之后的自动分类不会覆盖；intent为空时恢复为规则分类
+func (s *KeywordService) SetKeywordIntent(keywordID uint, intent, domainIntent string) (*models.Keyword, error) {
+	var keyword models.Keyword
+	if err := s.db.First(&keyword, keywordID).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	if intent == "" {
+		applyIntent(&keyword, seo.ClassifyIntent(keyword.Word), IntentSourceRule)
+	} else {
+		if !seo.ValidIntent(intent) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidIntent, intent)
+		}
+		if !seo.ValidDomainIntent(domainIntent) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidIntent, domainIntent)
+		}
+		applyIntent(&keyword, seo.IntentResult{Intent: intent, DomainIntent: domainIntent}, IntentSourceManual)
+	}
+
+	if err := s.db.Model(&keyword).Updates(map[string]interface{}{
+		"intent":        keyword.Intent,
+		"domain_intent": keyword.DomainIntent,
+		"intent_source": keyword.IntentSource,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("更新关键词意图失败: %w", err)
+	}
+
+	return &keyword, nil
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// 关键词生命周期状态
+const (
+	KeywordStatusNew        = "new"         // 新获取，待审核
+	KeywordStatusApproved   = "approved"    // 已审核，可以选题
+	KeywordStatusQueued     = "queued"      // 已加入生成队列
+	KeywordStatusCovered    = "covered"     // 已有文章
+	KeywordStatusRefreshDue = "refresh_due" // 文章较旧，需要重新生成
+	KeywordStatusBlocked    = "blocked"     // 已屏蔽，不参与选题、聚类和分类建议
+)
+
+const (
+	// MaxNextKeywords 选题队列单次最多返回的关键词数
+	MaxNextKeywords = 100
+	// defaultKeywordRefreshDays 文章超过该天数未更新时关键词进入待更新状态
+	defaultKeywordRefreshDays = 180
+	// defaultKeywordQueueCheckInterval 默认的生命周期检查间隔
+	defaultKeywordQueueCheckInterval = 24 * time.Hour
+	// defaultKeywordRefreshPriority 待更新关键词的选题得分默认按50%计算
+	defaultKeywordRefreshPriority = 50
+	// keywordQueuedTimeout 加入队列超过该时间仍未完成的关键词恢复为可选题，避免任务丢失后一直处于队列中
+	keywordQueuedTimeout = 7 * 24 * time.Hour
+	// keywordScoreBatch 重新计算选题得分时每批处理的关键词数
+	keywordScoreBatch = 1000
+)
+
+// keywordHasArticle 关键词有已发布文章的条件，草稿和已归档的文章不算覆盖
+const keywordHasArticle = "EXISTS (SELECT 1 FROM article_keywords JOIN articles ON articles.id = article_keywords.article_id " +
+	"WHERE article_keywords.keyword_id = keywords.id AND articles.status = 'published')"
+
+// keywordHasDraft 关键词有待发布草稿的条件，生成后尚未发布的关键词保持已覆盖，避免重复生成
+const keywordHasDraft = "EXISTS (SELECT 1 FROM article_keywords JOIN articles ON articles.id = article_keywords.article_id " +
+	"WHERE article_keywords.keyword_id = keywords.id AND articles.status = 'draft')"
+
+// ErrInvalidKeywordStatus 关键词状态无效或不允许手动修改
+var ErrInvalidKeywordStatus = errors.New("无效的关键词状态")
+
+// keywordTransitions 允许手动修改的状态，加入队列和覆盖由生成任务自动修改
+var keywordTransitions = map[string][]string{
+	KeywordStatusNew:        {KeywordStatusApproved, KeywordStatusBlocked},
+	KeywordStatusApproved:   {KeywordStatusNew, KeywordStatusBlocked},
+	KeywordStatusQueued:     {KeywordStatusApproved, KeywordStatusBlocked},
+	KeywordStatusCovered:    {KeywordStatusRefreshDue, KeywordStatusBlocked},
+	KeywordStatusRefreshDue: {KeywordStatusCovered, KeywordStatusBlocked},
+	KeywordStatusBlocked:    {KeywordStatusNew, KeywordStatusApproved},
+}
+
+// KeywordStatusSkip 未能修改状态的关键词
+type KeywordStatusSkip struct {
+	KeywordID uint   `json:"keyword_id"`
+	Word      string `json:"word"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+}
+
+// KeywordStatusResult 批量修改状态的结果
+type KeywordStatusResult struct {
+	Updated int                 `json:"updated"`
+	Skipped []KeywordStatusSkip `json:"skipped"`
+}
+
+// NextKeyword 选题队列中的关键词
+type NextKeyword struct {
+	models.Keyword
+	Score           float64 `json:"score"`                      // 按覆盖情况折算后的选题得分
+	IsClusterLead   bool    `json:"is_cluster_lead"`            // 是聚类的主关键词，生成时以整个聚类为目标
+	ClusterKeywords int     `json:"cluster_keywords,omitempty"` // 聚类中的关键词数
+}
+
+// KeywordLifecycleResult 生命周期检查结果
+type KeywordLifecycleResult struct {
+	Covered    int64 `json:"covered"`     // 已有文章但状态未更新的关键词
+	Uncovered  int64 `json:"uncovered"`   // 文章已删除或归档、恢复为已审核的关键词
+	RefreshDue int64 `json:"refresh_due"` // 文章较旧、进入待更新状态的关键词
+	Released   int64 `json:"released"`    // 在队列中超时、恢复为可选题的关键词
+	Scored     int   `json:"scored"`      // 选题得分有变化的关键词
+}
+
+// SetKeywordStatus 批量手动修改关键词状态，不允许的状态变化会被跳过
+func (s *KeywordService) SetKeywordStatus(keywordIDs []uint, status string) (*KeywordStatusResult, error) {
+	if _, ok := keywordTransitions[status]; !ok || status == KeywordStatusQueued {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeywordStatus, status)
+	}
+
+	var keywords []models.Keyword
+	if err := s.db.Where("id IN ?", keywordIDs).Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	result := &KeywordStatusResult{Skipped: []KeywordStatusSkip{}}
+	var ids []uint
+	for _, keyword := range keywords {
+		if keyword.Status == status {
+			continue
+		}
+		allowed := false
+		for _, next := range keywordTransitions[keyword.Status] {
+			if next == status {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			result.Skipped = append(result.Skipped, KeywordStatusSkip{
+				KeywordID: keyword.ID,
+				Word:      keyword.Word,
+				Status:    keyword.Status,
+				Reason:    fmt.Sprintf("不能从%s改为%s", keyword.Status, status),
+			})
+			continue
+		}
+		ids = append(ids, keyword.ID)
+	}
+
+	if len(ids) > 0 {
+		blockReason := ""
+		if status == KeywordStatusBlocked {
+			blockReason = keywordBlockReasonManual
+		}
+		if err := s.db.Model(&models.Keyword{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       status,
+			"block_reason": blockReason,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("更新关键词状态失败: %w", err)
+		}
+	}
+	result.Updated = len(ids)
+
+	return result, nil
+}
+
+// GetNextKeywords 选题队列：按选题得分列出最值得写的关键词。已审核的关键词按原得分，待更新的按配置的百分比折算；
+// 聚类中的次要关键词由主关键词的文章覆盖，不单独选题
+func (s *KeywordService) GetNextKeywords(limit int, categoryID *uint) ([]NextKeyword, error) {
+	if limit <= 0 || limit > MaxNextKeywords {
+		limit = MaxNextKeywords
+	}
+
+	statuses := []string{KeywordStatusApproved, KeywordStatusRefreshDue}
+	if s.config.KeywordQueue.IncludeNew {
+		statuses = append(statuses, KeywordStatusNew)
+	}
+	refreshPriority := s.config.KeywordQueue.RefreshPriority
+	if refreshPriority <= 0 {
+		refreshPriority = defaultKeywordRefreshPriority
+	}
+
+	query := s.db.Model(&models.Keyword{}).
+		Select("keywords.*, CASE WHEN keywords.status = ? THEN keywords.priority * ? / 100.0 ELSE keywords.priority END AS score, "+
+			"keyword_clusters.id IS NOT NULL AS is_cluster_lead, "+
+			"(SELECT COUNT(*) FROM keywords members WHERE members.cluster_id = keyword_clusters.id AND members.deleted_at IS NULL) AS cluster_keywords",
+			KeywordStatusRefreshDue, refreshPriority).
+		Joins("LEFT JOIN keyword_clusters ON keyword_clusters.id = keywords.cluster_id AND keyword_clusters.primary_keyword_id = keywords.id").
+		Where("keywords.status IN ?", statuses).
+		Where("keywords.cluster_id IS NULL OR keyword_clusters.id IS NOT NULL")
+	if categoryID != nil {
+		query = query.Where("keywords.id IN (?)", s.db.Table("category_keywords").
+			Select("keyword_id").Where("category_id = ?", *categoryID))
+	}
+
+	var next []NextKeyword
+	if err := query.Order("score DESC, keywords.search_volume DESC, keywords.id ASC").
+		Limit(limit).
+		Scan(&next).Error; err != nil {
+		return nil, fmt.Errorf("查询选题队列失败: %w", err)
+	}
+
+	return next, nil
+}
+
+// markKeywordsQueued 关键词加入生成队列，已屏蔽、已在队列中和已覆盖的关键词保持不变
+func markKeywordsQueued(db *gorm.DB, keywordIDs []uint) error {
+	if len(keywordIDs) == 0 {
+		return nil
+	}
+	if err := db.Model(&models.Keyword{}).
+		Where("id IN ? AND status IN ?", keywordIDs, []string{KeywordStatusNew, KeywordStatusApproved, KeywordStatusRefreshDue}).
+		Update("status", KeywordStatusQueued).Error; err != nil {
+		return fmt.Errorf("更新关键词状态失败: %w", err)
+	}
+	return nil
+}
+
+// markKeywordCovered 文章生成后将关键词标记为已覆盖；以聚类为目标生成时，聚类中尚未覆盖的其他关键词也由这篇文章覆盖
+func markKeywordCovered(tx *gorm.DB, keywordID uint, clusterID *uint) error {
+	if err := tx.Model(&models.Keyword{}).
+		Where("id = ? AND status <> ?", keywordID, KeywordStatusBlocked).
+		Update("status", KeywordStatusCovered).Error; err != nil {
+		return fmt.Errorf("更新关键词状态失败: %w", err)
+	}
+
+	if clusterID != nil {
+		if err := tx.Model(&models.Keyword{}).
+			Where("cluster_id = ? AND status IN ?", *clusterID, []string{KeywordStatusNew, KeywordStatusApproved, KeywordStatusRefreshDue}).
+			Update("status", KeywordStatusCovered).Error; err != nil {
+			return fmt.Errorf("更新聚类关键词状态失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// releaseQueuedKeywords 生成失败时关键词离开队列：已有已发布文章的恢复为待更新，否则恢复为已审核
+func releaseQueuedKeywords(db *gorm.DB, keywordIDs []uint) (int64, error) {
+	if len(keywordIDs) == 0 {
+		return 0, nil
+	}
+	result := db.Model(&models.Keyword{}).
+		Where("id IN ? AND status = ?", keywordIDs, KeywordStatusQueued).
+		Update("status", gorm.Expr("CASE WHEN "+keywordHasArticle+" THEN ? ELSE ? END",
+			KeywordStatusRefreshDue, KeywordStatusApproved))
+	if result.Error != nil {
+		return 0, fmt.Errorf("更新关键词状态失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ProcessKeywordLifecycle 定时检查关键词生命周期并重新计算选题得分
+func (s *KeywordService) ProcessKeywordLifecycle(ctx context.Context) {
+	interval := time.Duration(s.config.KeywordQueue.CheckInterval) * time.Hour
+	if interval <= 0 {
+		interval = defaultKeywordQueueCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.RefreshLifecycle(); err != nil {
+			log.Printf("检查关键词生命周期失败: %v", err)
+		} else if result.Covered > 0 || result.Uncovered > 0 || result.RefreshDue > 0 || result.Released > 0 {
+			log.Printf("关键词生命周期: %d个已覆盖，%d个失去文章，%d个待更新，%d个离开队列",
+				result.Covered, result.Uncovered, result.RefreshDue, result.Released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshLifecycle 检查关键词生命周期：已有已发布文章的关键词标记为已覆盖，文章被删除或归档的恢复为已审核，
+// 文章较旧的标记为待更新，在队列中超时的恢复为可选题，并按最新指标重新计算选题得分
+func (s *KeywordService) RefreshLifecycle() (*KeywordLifecycleResult, error) {
+	result := &KeywordLifecycleResult{}
+
+	covered := s.db.Model(&models.Keyword{}).
+		Where("status IN ?", []string{KeywordStatusNew, KeywordStatusApproved}).
+		Where(keywordHasArticle).
+		Update("status", KeywordStatusCovered)
+	if covered.Error != nil {
+		return nil, fmt.Errorf("更新已覆盖关键词失败: %w", covered.Error)
+	}
+	result.Covered = covered.RowsAffected
+
+	uncovered := s.db.Model(&models.Keyword{}).
+		Where("status IN ?", []string{KeywordStatusCovered, KeywordStatusRefreshDue}).
+		Where("NOT "+keywordHasArticle).
+		Where("NOT "+keywordHasDraft).
+		Update("status", KeywordStatusApproved)
+	if uncovered.Error != nil {
+		return nil, fmt.Errorf("更新失去文章的关键词失败: %w", uncovered.Error)
+	}
+	result.Uncovered = uncovered.RowsAffected
+
+	refreshDays := s.config.KeywordQueue.RefreshDays
+	if refreshDays <= 0 {
+		refreshDays = defaultKeywordRefreshDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -refreshDays)
+	refreshDue := s.db.Model(&models.Keyword{}).
+		Where("status = ?", KeywordStatusCovered).
+		Where(keywordHasArticle).
+		Where("NOT EXISTS (SELECT 1 FROM article_keywords JOIN articles ON articles.id = article_keywords.article_id "+
+			"WHERE article_keywords.keyword_id = keywords.id AND articles.status = 'published' AND articles.updated_at >= ?)", cutoff).
+		Update("status", KeywordStatusRefreshDue)
+	if refreshDue.Error != nil {
+		return nil, fmt.Errorf("更新待更新关键词失败: %w", refreshDue.Error)
+	}
+	result.RefreshDue = refreshDue.RowsAffected
+
+	var stale []uint
+	if err := s.db.Model(&models.Keyword{}).
+		Where("status = ? AND updated_at < ?", KeywordStatusQueued, time.Now().Add(-keywordQueuedTimeout)).
+		Pluck("id", &stale).Error; err != nil {
+		return nil, fmt.Errorf("查询队列中的关键词失败: %w", err)
+	}
+	released, err := releaseQueuedKeywords(s.db, stale)
+	if err != nil {
+		return nil, err
+	}
+	result.Released = released
+
+	scored, err := s.recalculatePriority()
+	if err != nil {
+		return nil, err
+	}
+	result.Scored = scored
+
+	return result, nil
+}
+
+// recalculatePriority 按最新指标的搜索量和优化难度重新计算未屏蔽关键词的选题得分，没有指标的按关键词的搜索量计算
+func (s *KeywordService) recalculatePriority() (int, error) {
+	type keywordScore struct {
+		ID           uint
+		SearchVolume int
+		Difficulty   int
+		Priority     float64
+	}
+
+	changed := 0
+	var lastID uint
+	for {
+		var rows []keywordScore
+		if err := s.db.Model(&models.Keyword{}).
+			Select("keywords.id, COALESCE(latest.search_volume, keywords.search_volume) AS search_volume, "+
+				"COALESCE(latest.difficulty, 0) AS difficulty, keywords.priority").
+			Joins("LEFT JOIN (?) AS latest ON latest.keyword_id = keywords.id", latestMetrics(s.db)).
+			Where("keywords.status <> ? AND keywords.id > ?", KeywordStatusBlocked, lastID).
+			Order("keywords.id").
+			Limit(keywordScoreBatch).
+			Scan(&rows).Error; err != nil {
+			return changed, fmt.Errorf("查询关键词指标失败: %w", err)
+		}
+		if len(rows) == 0 {
+			return changed, nil
+		}
+
+		for _, row := range rows {
+			score := seo.PriorityScore(row.SearchVolume, row.Difficulty)
+			if score == row.Priority {
+				continue
+			}
+			if err := s.db.Model(&models.Keyword{}).Where("id = ?", row.ID).
+				UpdateColumn("priority", score).Error; err != nil {
+				return changed, fmt.Errorf("更新关键词选题得分失败: %w", err)
+			}
+			changed++
+		}
+		lastID = rows[len(rows)-1].ID
+	}
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxMetricsRefresh 单次最多刷新指标的关键词数
+	MaxMetricsRefresh = 200
+	// relatedQuestionsLimit 每个关键词获取的相关问题数
+	relatedQuestionsLimit = 20
+	// defaultMetricsRefreshInterval 默认的定时刷新间隔
+	defaultMetricsRefreshInterval = 6 * time.Hour
+	// defaultMetricsStaleDays 默认的指标过期天数
+	defaultMetricsStaleDays = 30
+	// defaultMetricsRefreshBatch 默认每次定时刷新的关键词数
+	defaultMetricsRefreshBatch = MaxMetricsRefresh
+	// defaultMetricsHistoryMonths 默认返回的指标历史月数
+	defaultMetricsHistoryMonths = 24
+)
+
+// KeywordMetricsOptions 指标刷新选项，趋势和相关问题需要为每个关键词单独调用一次接口
+type KeywordMetricsOptions struct {
+	Trend     bool
+	Questions bool
+}
+
+// KeywordMetricHistory 关键词的指标历史和季节性
+type KeywordMetricHistory struct {
+	Keyword     models.Keyword         `json:"keyword"`
+	Metrics     []models.KeywordMetric `json:"metrics"`      // 按获取时间从早到晚排列
+	Trend       []models.TrendPoint    `json:"trend"`        // 月度搜索量，用于计算季节性
+	TrendSource string                 `json:"trend_source"` // trend：5118指数趋势；history：按月汇总的指标快照
+	Seasonality seo.Seasonality        `json:"seasonality"`
+}
+
+// KeywordOpportunity 关键词及其最新指标
+type KeywordOpportunity struct {
+	Keyword models.Keyword       `json:"keyword"`
+	Metric  models.KeywordMetric `json:"metric"`
+}
+
+// newKeywordMetric 由5118返回的指标创建指标快照并计算机会得分
+func newKeywordMetric(keywordID uint, metrics seo.Keyword5118Metrics, fetchedAt time.Time) models.KeywordMetric {
+	return models.KeywordMetric{
+		KeywordID:     keywordID,
+		Source:        seo.KeywordSource5118,
+		SearchVolume:  metrics.SearchVolume,
+		PCVolume:      metrics.PCVolume,
+		MobileVolume:  metrics.MobileVolume,
+		Competition:   metrics.Competition,
+		Difficulty:    metrics.Difficulty,
+		BidCompanies:  metrics.BidCompanies,
+		CPC:           metrics.CPC,
+		LongTailCount: metrics.LongTailSize,
+		Opportunity:   seo.OpportunityScore(metrics.SearchVolume, metrics.MobileVolume, metrics.Competition),
+		FetchedAt:     fetchedAt,
+	}
+}
+
+// metricsForKeywords 为已保存的关键词匹配来源返回的指标
+func metricsForKeywords(keywords []models.Keyword, metrics []seo.Keyword5118Metrics, fetchedAt time.Time) []models.KeywordMetric {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	byWord := make(map[string]seo.Keyword5118Metrics, len(metrics))
+	for _, m := range metrics {
+		if _, ok := byWord[m.Keyword]; !ok {
+			byWord[m.Keyword] = m
+		}
+	}
+
+	snapshots := make([]models.KeywordMetric, 0, len(keywords))
+	for _, keyword := range keywords {
+		if m, ok := byWord[keyword.Word]; ok {
+			snapshots = append(snapshots, newKeywordMetric(keyword.ID, m, fetchedAt))
+		}
+	}
+	return snapshots
+}
+
+// metricsCalls 刷新指标需要调用5118接口的次数
+func metricsCalls(count int, opts KeywordMetricsOptions) int {
+	calls := (count + seo.MaxMetricsBatch - 1) / seo.MaxMetricsBatch
+	if opts.Trend {
+		calls += count
+	}
+	if opts.Questions {
+		calls += count
+	}
+	return calls
+}
+
+// RefreshMetrics 从5118获取关键词的PC和移动端搜索量、竞价竞争度、优化难度和点击单价，可选获取指数趋势和相关问题，
+// 每个关键词新增一条指标快照，并以最新搜索量更新关键词
+func (s *KeywordService) RefreshMetrics(keywordIDs []uint, opts KeywordMetricsOptions) ([]models.KeywordMetric, error) {
+	if len(keywordIDs) == 0 {
+		return []models.KeywordMetric{}, nil
+	}
+	if len(keywordIDs) > MaxMetricsRefresh {
+		return nil, fmt.Errorf("单次最多刷新%d个关键词", MaxMetricsRefresh)
+	}
+	if !s.api5118Client.Enabled() {
+		return nil, fmt.Errorf("%w: %s", ErrKeywordSourceDisabled, seo.KeywordSource5118)
+	}
+
+	var keywords []models.Keyword
+	if err := s.db.Where("id IN ?", keywordIDs).Order("id").Find(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+	if len(keywords) == 0 {
+		return []models.KeywordMetric{}, nil
+	}
+
+	// 剩余配额不足时不发起请求
+	quota, err := s.quota(s.api5118Client)
+	if err != nil {
+		return nil, err
+	}
+	calls := metricsCalls(len(keywords), opts)
+	if quota.DailyQuota > 0 && quota.UsedToday+int64(calls) > int64(quota.DailyQuota) {
+		return nil, fmt.Errorf("%w: 需要%d次调用，剩余%d次", ErrKeywordQuotaExhausted, calls, int64(quota.DailyQuota)-quota.UsedToday)
+	}
+
+	now := time.Now()
+	byWord := make(map[string]int, len(keywords))
+	for i, keyword := range keywords {
+		byWord[keyword.Word] = i
+	}
+	snapshots := make([]models.KeywordMetric, len(keywords))
+	returned := make([]bool, len(keywords))
+
+	// 批量查询基础指标
+	for start := 0; start < len(keywords); start += seo.MaxMetricsBatch {
+		end := min(start+seo.MaxMetricsBatch, len(keywords))
+		words := make([]string, 0, end-start)
+		for _, keyword := range keywords[start:end] {
+			words = append(words, keyword.Word)
+		}
+
+		items, err := s.api5118Client.KeywordMetrics(words)
+		if err != nil {
+			return nil, fmt.Errorf("获取关键词指标失败: %w", err)
+		}
+		for _, item := range items {
+			if i, ok := byWord[item.Keyword]; ok {
+				snapshots[i] = newKeywordMetric(keywords[i].ID, item, now)
+				returned[i] = true
+			}
+		}
+	}
+
+	// 5118没有返回的关键词不记录快照，避免把搜索量记为0
+	saved := make([]models.KeywordMetric, 0, len(keywords))
+	for i, keyword := range keywords {
+		if !returned[i] {
+			continue
+		}
+		if opts.Trend {
+			trend, err := s.api5118Client.KeywordTrend(keyword.Word)
+			if err != nil {
+				return nil, fmt.Errorf("获取关键词%s的指数趋势失败: %w", keyword.Word, err)
+			}
+			snapshots[i].Trend = trend
+		}
+		if opts.Questions {
+			questions, err := s.api5118Client.RelatedQuestions(keyword.Word, relatedQuestionsLimit)
+			if err != nil {
+				return nil, fmt.Errorf("获取关键词%s的相关问题失败: %w", keyword.Word, err)
+			}
+			snapshots[i].Questions = questions
+		}
+		saved = append(saved, snapshots[i])
+	}
+	if len(saved) == 0 {
+		return saved, nil
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Create(&saved).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("保存关键词指标失败: %w", err)
+	}
+
+	// 关键词的搜索量以最新指标为准，可以下降，同时重新计算选题得分
+	for _, metric := range saved {
+		if err := tx.Model(&models.Keyword{}).Where("id = ?", metric.KeywordID).Updates(map[string]interface{}{
+			"search_volume": metric.SearchVolume,
+			"priority":      seo.PriorityScore(metric.SearchVolume, metric.Difficulty),
+		}).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("更新关键词搜索量失败: %w", err)
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetLatestMetric 获取关键词最新的指标快照
+func (s *KeywordService) GetLatestMetric(keywordID uint) (*models.KeywordMetric, error) {
+	var metric models.KeywordMetric
+	if err := s.db.Where("keyword_id = ?", keywordID).
+		Order("fetched_at DESC, id DESC").
+		First(&metric).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词指标失败: %w", err)
+	}
+	return &metric, nil
+}
+
+// latestMetrics 每个关键词最新的指标快照
+func latestMetrics(db *gorm.DB) *gorm.DB {
+	return db.Table("keyword_metrics").
+		Select("DISTINCT ON (keyword_id) *").
+		Order("keyword_id, fetched_at DESC, id DESC")
+}
+
+// GetOpportunities 按最新指标的机会得分从高到低列出关键词，categoryID不为空时只列出该分类的关键词
+func (s *KeywordService) GetOpportunities(page, pageSize int, categoryID *uint) ([]KeywordOpportunity, int64, error) {
+	var total int64
+
+	query := s.db.Table("(?) AS latest", latestMetrics(s.db)).
+		Joins("JOIN keywords ON keywords.id = latest.keyword_id AND keywords.deleted_at IS NULL")
+	if categoryID != nil {
+		query = query.Where("latest.keyword_id IN (?)", s.db.Table("category_keywords").
+			Select("keyword_id").Where("category_id = ?", *categoryID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计关键词数量失败: %w", err)
+	}
+
+	var metrics []models.KeywordMetric
+	offset := (page - 1) * pageSize
+	if err := query.Select("latest.*").
+		Order("latest.opportunity DESC, latest.search_volume DESC, latest.keyword_id ASC").
+		Offset(offset).Limit(pageSize).
+		Find(&metrics).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询关键词指标失败: %w", err)
+	}
+	if len(metrics) == 0 {
+		return []KeywordOpportunity{}, total, nil
+	}
+
+	ids := make([]uint, len(metrics))
+	for i, metric := range metrics {
+		ids[i] = metric.KeywordID
+	}
+	var keywords []models.Keyword
+	if err := s.db.Where("id IN ?", ids).Find(&keywords).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询关键词失败: %w", err)
+	}
+	byID := make(map[uint]models.Keyword, len(keywords))
+	for _, keyword := range keywords {
+		byID[keyword.ID] = keyword
+	}
+
+	opportunities := make([]KeywordOpportunity, 0, len(metrics))
+	for _, metric := range metrics {
+		keyword, ok := byID[metric.KeywordID]
+		if !ok {
+			continue
+		}
+		opportunities = append(opportunities, KeywordOpportunity{Keyword: keyword, Metric: metric})
+	}
+
+	return opportunities, total, nil
+}
+
+// ProcessMetricsRefresh 定时刷新指标过期的关键词，未开启自动刷新时直接返回
+func (s *KeywordService) ProcessMetricsRefresh(ctx context.Context) {
+	if !s.config.KeywordMetrics.AutoRefresh {
+		return
+	}
+
+	interval := time.Duration(s.config.KeywordMetrics.RefreshInterval) * time.Hour
+	if interval <= 0 {
+		interval = defaultMetricsRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshed, err := s.RefreshStaleMetrics()
+		if err != nil {
+			log.Printf("定时刷新关键词指标失败: %v", err)
+		} else if refreshed > 0 {
+			log.Printf("定时刷新了%d个关键词的指标", refreshed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshStaleMetrics 刷新最新指标已过期或还没有指标的关键词，最久未刷新的优先，
+// 刷新数量受剩余5118配额限制（扣除为手动获取保留的次数），返回刷新的关键词数
+func (s *KeywordService) RefreshStaleMetrics() (int, error) {
+	if !s.api5118Client.Enabled() {
+		return 0, nil
+	}
+
+	cfg := s.config.KeywordMetrics
+	staleDays := cfg.StaleDays
+	if staleDays <= 0 {
+		staleDays = defaultMetricsStaleDays
+	}
+	batch := cfg.RefreshBatch
+	if batch <= 0 {
+		batch = defaultMetricsRefreshBatch
+	}
+	opts := KeywordMetricsOptions{Trend: cfg.RefreshTrend}
+
+	// 按剩余配额计算本次最多可以刷新的关键词数
+	quota, err := s.quota(s.api5118Client)
+	if err != nil {
+		return 0, err
+	}
+	if quota.DailyQuota > 0 {
+		budget := quota.DailyQuota - int(quota.UsedToday) - cfg.QuotaReserve
+		for batch > 0 && metricsCalls(batch, opts) > budget {
+			batch--
+		}
+		if batch == 0 {
+			return 0, nil
+		}
+	}
+
+	var ids []uint
+	if err := s.db.Table("keywords").
+		Select("keywords.id").
+		Joins("LEFT JOIN (?) AS latest ON latest.keyword_id = keywords.id", s.db.Table("keyword_metrics").
+			Select("keyword_id, MAX(fetched_at) AS fetched_at").Group("keyword_id")).
+		Where("keywords.deleted_at IS NULL AND keywords.status <> ?", KeywordStatusBlocked).
+		Where("latest.fetched_at IS NULL OR latest.fetched_at < ?", time.Now().AddDate(0, 0, -staleDays)).
+		Order("latest.fetched_at ASC NULLS FIRST, keywords.search_volume DESC, keywords.id ASC").
+		Limit(batch).
+		Pluck("keywords.id", &ids).Error; err != nil {
+		return 0, fmt.Errorf("查询待刷新的关键词失败: %w", err)
+	}
+
+	refreshed := 0
+	for start := 0; start < len(ids); start += MaxMetricsRefresh {
+		end := min(start+MaxMetricsRefresh, len(ids))
+		metrics, err := s.RefreshMetrics(ids[start:end], opts)
+		if err != nil {
+			return refreshed, err
+		}
+		refreshed += len(metrics)
+	}
+	return refreshed, nil
+}
+
+// GetMetricHistory 获取关键词最近若干个月的指标历史，并根据月度搜索量计算季节性：
+// 优先使用最新的5118指数趋势，没有趋势时按月汇总指标快照的搜索量
+func (s *KeywordService) GetMetricHistory(keywordID uint, months int) (*KeywordMetricHistory, error) {
+	if months <= 0 {
+		months = defaultMetricsHistoryMonths
+	}
+
+	var keyword models.Keyword
+	if err := s.db.First(&keyword, keywordID).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	now := time.Now()
+	history := &KeywordMetricHistory{Keyword: keyword}
+	if err := s.db.Where("keyword_id = ? AND fetched_at >= ?", keywordID, now.AddDate(0, -months, 0)).
+		Order("fetched_at ASC, id ASC").
+		Find(&history.Metrics).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词指标失败: %w", err)
+	}
+
+	var latest models.KeywordMetric
+	if err := s.db.Where("keyword_id = ? AND trend IS NOT NULL AND trend NOT IN ('', 'null', '[]')", keywordID).
+		Order("fetched_at DESC, id DESC").
+		Limit(1).
+		Find(&latest).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词趋势失败: %w", err)
+	}
+
+	if len(latest.Trend) > 0 {
+		history.Trend = latest.Trend
+		history.TrendSource = "trend"
+	} else {
+		history.Trend = monthlyVolumes(history.Metrics)
+		history.TrendSource = "history"
+	}
+	history.Seasonality = seo.AnalyzeSeasonality(history.Trend, now)
+
+	return history, nil
+}
+
+// monthlyVolumes 按月汇总指标快照的平均搜索量
+func monthlyVolumes(metrics []models.KeywordMetric) []models.TrendPoint {
+	var points []models.TrendPoint
+	var sum, count int
+	for i, metric := range metrics {
+		sum += metric.SearchVolume
+		count++
+
+		month := metric.FetchedAt.Format("2006-01")
+		if i+1 < len(metrics) && metrics[i+1].FetchedAt.Format("2006-01") == month {
+			continue
+		}
+		points = append(points, models.TrendPoint{Month: month, Volume: sum / count})
+		sum, count = 0, 0
+	}
+	return points
+}
@@ -2,52 +2,92 @@ package services
 
 import (
 	"fmt"
+	"log"
+	"sync"
 
 	"github.com/NietzscheX/seo-generate/config"
 	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/ai"
 	"github.com/NietzscheX/seo-generate/pkg/seo"
 	"gorm.io/gorm"
 )
 
 // KeywordService 关键词服务
 type KeywordService struct {
-	db            *gorm.DB
-	config        *config.Config
-	api5118Client *seo.API5118Client
+	db                 *gorm.DB
+	config             *config.Config
+	api5118Client      *seo.API5118Client
+	sources            []seo.KeywordSource
+	embeddingClient    *ai.EmbeddingClient
+	intentClassifier   *ai.IntentClassifier
+	categoryClassifier *ai.CategoryClassifier
+	blocklistMu        sync.RWMutex
+	blocklist          *seo.KeywordBlocklist
 }
 
 // NewKeywordService 创建关键词服务
 func NewKeywordService(db *gorm.DB, cfg *config.Config) *KeywordService {
-	return &KeywordService{
+	api5118Client := seo.NewAPI5118Client(cfg)
+	s := &KeywordService{
 		db:            db,
 		config:        cfg,
-		api5118Client: seo.NewAPI5118Client(cfg),
+		api5118Client: api5118Client,
+		sources: []seo.KeywordSource{
+			api5118Client,
+			seo.NewAPI5118LongTail(api5118Client),
+			seo.NewBaiduSuggestClient(cfg),
+			seo.NewRelatedSearchParser(),
+			ai.NewKeywordExpander(cfg),
+		},
+		embeddingClient:    ai.NewEmbeddingClient(cfg),
+		intentClassifier:   ai.NewIntentClassifier(cfg),
+		categoryClassifier: ai.NewCategoryClassifier(cfg),
 	}
+
+	// 5118按调用次数计费，保存每次调用的日志用于统计配额
+	api5118Client.SetLogger(s.saveAPILog)
+	return s
 }
 
-// FetchKeywordsByCategory 按分类获取关键词
-func (s *KeywordService) FetchKeywordsByCategory(category string, limit int) ([]models.Keyword, error) {
-	// 从5118 API获取关键词
-	keywords, err := s.api5118Client.GetKeywordsByCategory(category, limit)
-	if err != nil {
-		return nil, fmt.Errorf("从5118获取关键词失败: %w", err)
+// saveAPILog 保存API调用日志
+func (s *KeywordService) saveAPILog(apiLog *models.APILog) {
+	if err := s.db.Create(apiLog).Error; err != nil {
+		log.Printf("保存API调用日志失败: %v", err)
 	}
+}
+
+// KeywordSourceImport 表格导入的关键词来源
+const KeywordSourceImport = "import"
+
+// 关键词写入时的操作
+const (
+	KeywordActionCreate    = "create"    // 新建
+	KeywordActionUpdate    = "update"    // 已存在，提高了搜索量
+	KeywordActionUnchanged = "unchanged" // 已存在，没有变化
+)
 
-	// 清洗关键词
-	cleanedKeywords := seo.CleanKeywords(keywords)
+// SaveKeywords 保存关键词到数据库，source为关键词来源（为空时保留关键词自带的来源）
+func (s *KeywordService) SaveKeywords(keywords []models.Keyword, source string) error {
+	// 开始事务
+	tx := s.db.Begin()
 
-	// 保存关键词到数据库
-	if err := s.SaveKeywords(cleanedKeywords); err != nil {
-		return nil, fmt.Errorf("保存关键词失败: %w", err)
+	if _, err := upsertKeywords(tx, keywords, source); err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	return cleanedKeywords, nil
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return nil
 }
 
-// SaveKeywords 保存关键词到数据库
-func (s *KeywordService) SaveKeywords(keywords []models.Keyword) error {
-	// 开始事务
-	tx := s.db.Begin()
+// upsertKeywords 在事务中写入关键词：不存在时创建，已存在时只在搜索量更高时更新，来源保持不变。
+// 写入后keywords[i]为数据库中的记录，返回每个关键词对应的操作
+func upsertKeywords(tx *gorm.DB, keywords []models.Keyword, source string) ([]string, error) {
+	actions := make([]string, len(keywords))
 
 	for i := range keywords {
 		// 检查关键词是否已存在
@@ -56,28 +96,35 @@ func (s *KeywordService) SaveKeywords(keywords []models.Keyword) error {
 
 		if result.Error == nil {
 			// 关键词已存在，更新搜索量
+			actions[i] = KeywordActionUnchanged
 			if keywords[i].SearchVolume > existingKeyword.SearchVolume {
-				tx.Model(&existingKeyword).Update("search_volume", keywords[i].SearchVolume)
+				if err := tx.Model(&existingKeyword).Update("search_volume", keywords[i].SearchVolume).Error; err != nil {
+					return nil, fmt.Errorf("更新关键词失败: %w", err)
+				}
+				actions[i] = KeywordActionUpdate
 			}
+			keywords[i] = existingKeyword
 		} else if result.Error == gorm.ErrRecordNotFound {
 			// 关键词不存在，创建新记录
+			if source != "" {
+				keywords[i].Source = source
+			}
+			keywords[i].Status = KeywordStatusNew
+			keywords[i].Priority = seo.PriorityScore(keywords[i].SearchVolume, 0)
+			if keywords[i].Intent == "" {
+				applyIntent(&keywords[i], seo.ClassifyIntent(keywords[i].Word), IntentSourceRule)
+			}
 			if err := tx.Create(&keywords[i]).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("创建关键词失败: %w", err)
+				return nil, fmt.Errorf("创建关键词失败: %w", err)
 			}
+			actions[i] = KeywordActionCreate
 		} else {
 			// 其他错误
-			tx.Rollback()
-			return fmt.Errorf("查询关键词失败: %w", result.Error)
+			return nil, fmt.Errorf("查询关键词失败: %w", result.Error)
 		}
 	}
 
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("提交事务失败: %w", err)
-	}
-
-	return nil
+	return actions, nil
 }
 
 // GetKeywordsByCategory 从数据库获取指定分类的关键词
@@ -112,28 +159,6 @@ func (s *KeywordService) GetKeywordByID(id uint) (*models.Keyword, error) {
 	return &keyword, nil
 }
 
-// SearchKeywords 搜索关键词
-func (s *KeywordService) SearchKeywords(query string, page, pageSize int) ([]models.Keyword, int64, error) {
-	var keywords []models.Keyword
-	var total int64
-
-	// 构建查询
-	dbQuery := s.db.Model(&models.Keyword{}).Where("word LIKE ?", "%"+query+"%")
-
-	// 统计总数
-	if err := dbQuery.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("统计关键词数量失败: %w", err)
-	}
-
-	// 分页查询
-	offset := (page - 1) * pageSize
-	if err := dbQuery.Offset(offset).Limit(pageSize).Find(&keywords).Error; err != nil {
-		return nil, 0, fmt.Errorf("搜索关键词失败: %w", err)
-	}
-
-	return keywords, total, nil
-}
-
 // AssignKeywordToCategory 将关键词分配到分类
 func (s *KeywordService) AssignKeywordToCategory(keywordID, categoryID uint) error {
 	// 查询关键词
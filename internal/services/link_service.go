@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// InternalLinkService 自动内链服务
+type InternalLinkService struct {
+	db         *gorm.DB
+	config     *config.Config
+	seoService *seo.SEOService
+}
+
+// NewInternalLinkService 创建自动内链服务
+func NewInternalLinkService(db *gorm.DB, cfg *config.Config, seoService *seo.SEOService) *InternalLinkService {
+	return &InternalLinkService{
+		db:         db,
+		config:     cfg,
+		seoService: seoService,
+	}
+}
+
+// orderKeywords 按ID加载关键词，第一个关键词为主关键词
+func orderKeywords(db *gorm.DB) *gorm.DB {
+	return db.Order("keywords.id ASC")
+}
+
+// optedOut 文章是否在自动内链排除列表中
+func (s *InternalLinkService) optedOut(articleID uint) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.LinkOptOut{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查询内链排除列表失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// linkTargets 可被链接的文章：已发布、不在排除列表中且有关键词
+func (s *InternalLinkService) linkTargets() ([]seo.LinkTarget, error) {
+	var articles []models.Article
+	if err := s.db.Preload("Keywords", orderKeywords).
+		Where("status = ?", "published").
+		Where("id NOT IN (?)", s.db.Model(&models.LinkOptOut{}).Select("article_id")).
+		Order("published_at ASC, id ASC").
+		Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("查询可链接文章失败: %w", err)
+	}
+
+	targets := make([]seo.LinkTarget, 0, len(articles))
+	for _, article := range articles {
+		if len(article.Keywords) == 0 {
+			continue
+		}
+		targets = append(targets, seo.LinkTarget{
+			ArticleID: article.ID,
+			Slug:      article.Slug,
+			Keyword:   article.Keywords[0].Word,
+		})
+	}
+	return targets, nil
+}
+
+// LinkArticle 重新计算文章的自动内链并更新正文：先还原之前添加的链接，
+// 文章已发布且不在排除列表中时再链接到其他已发布文章
+func (s *InternalLinkService) LinkArticle(article *models.Article) ([]models.ArticleLink, error) {
+	var existing []models.ArticleLink
+	if err := s.db.Where("source_id = ?", article.ID).Order("id").Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("查询站内链接失败: %w", err)
+	}
+
+	previous := make([]seo.InternalLink, 0, len(existing))
+	for _, link := range existing {
+		previous = append(previous, seo.InternalLink{TargetID: link.TargetID, Keyword: link.Keyword, URL: link.URL})
+	}
+	content := seo.RemoveInternalLinks(article.Content, previous)
+
+	optedOut, err := s.optedOut(article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inserted []seo.InternalLink
+	if article.Status == "published" && !optedOut {
+		targets, err := s.linkTargets()
+		if err != nil {
+			return nil, err
+		}
+		content, inserted = s.seoService.InsertInternalLinks(content, article.Slug, targets, s.config.SEO.InternalLinkMax)
+	}
+
+	links := make([]models.ArticleLink, 0, len(inserted))
+	for _, link := range inserted {
+		links = append(links, models.ArticleLink{
+			SourceID: article.ID,
+			TargetID: link.TargetID,
+			Keyword:  link.Keyword,
+			URL:      link.URL,
+		})
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Where("source_id = ?", article.ID).Delete(&models.ArticleLink{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("删除站内链接失败: %w", err)
+	}
+
+	if len(links) > 0 {
+		if err := tx.Create(&links).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("保存站内链接失败: %w", err)
+		}
+	}
+
+	if content != article.Content {
+		if err := tx.Model(article).Update("content", content).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("更新文章内容失败: %w", err)
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+	article.Content = content
+
+	return links, nil
+}
+
+// Relink 重新计算指定文章的自动内链
+func (s *InternalLinkService) Relink(articleID uint) ([]models.ArticleLink, error) {
+	var article models.Article
+	if err := s.db.First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+	return s.LinkArticle(&article)
+}
+
+// RelinkSources 文章取消发布或被排除后，重新计算所有链接到它的文章，使链接不再指向该文章
+func (s *InternalLinkService) RelinkSources(targetID uint) error {
+	var sourceIDs []uint
+	if err := s.db.Model(&models.ArticleLink{}).
+		Where("target_id = ?", targetID).
+		Distinct().Pluck("source_id", &sourceIDs).Error; err != nil {
+		return fmt.Errorf("查询链接来源失败: %w", err)
+	}
+
+	for _, sourceID := range sourceIDs {
+		if _, err := s.Relink(sourceID); err != nil {
+			return fmt.Errorf("重新计算文章%d的站内链接失败: %w", sourceID, err)
+		}
+	}
+	return nil
+}
+
+// ArticleDeleted 文章删除后清除它的链接记录，并重新计算链接到它的文章
+func (s *InternalLinkService) ArticleDeleted(articleID uint) error {
+	if err := s.db.Where("source_id = ?", articleID).Delete(&models.ArticleLink{}).Error; err != nil {
+		return fmt.Errorf("删除站内链接失败: %w", err)
+	}
+	if err := s.db.Where("article_id = ?", articleID).Delete(&models.LinkOptOut{}).Error; err != nil {
+		return fmt.Errorf("删除内链排除记录失败: %w", err)
+	}
+	return s.RelinkSources(articleID)
+}
+
+// GetArticleLinks 获取文章自动添加的站内链接
+func (s *InternalLinkService) GetArticleLinks(articleID uint) ([]models.ArticleLink, error) {
+	var links []models.ArticleLink
+	if err := s.db.Where("source_id = ?", articleID).Order("id").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("查询站内链接失败: %w", err)
+	}
+	return links, nil
+}
+
+// GetOptOuts 获取自动内链排除列表
+func (s *InternalLinkService) GetOptOuts() ([]models.LinkOptOut, error) {
+	var optOuts []models.LinkOptOut
+	if err := s.db.Order("created_at DESC, id DESC").Find(&optOuts).Error; err != nil {
+		return nil, fmt.Errorf("查询内链排除列表失败: %w", err)
+	}
+	return optOuts, nil
+}
+
+// AddOptOut 将文章加入排除列表，移除它添加的链接和指向它的链接
+func (s *InternalLinkService) AddOptOut(articleID uint, reason string) (*models.LinkOptOut, error) {
+	var article models.Article
+	if err := s.db.First(&article, articleID).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+
+	optOut := models.LinkOptOut{ArticleID: articleID, Reason: reason}
+	if err := s.db.Where(models.LinkOptOut{ArticleID: articleID}).
+		Assign(models.LinkOptOut{Reason: reason}).
+		FirstOrCreate(&optOut).Error; err != nil {
+		return nil, fmt.Errorf("保存内链排除记录失败: %w", err)
+	}
+
+	if _, err := s.LinkArticle(&article); err != nil {
+		return nil, err
+	}
+	if err := s.RelinkSources(articleID); err != nil {
+		return nil, err
+	}
+	return &optOut, nil
+}
+
+// RemoveOptOut 将文章移出排除列表并重新计算它的自动内链
+func (s *InternalLinkService) RemoveOptOut(articleID uint) error {
+	if err := s.db.Where("article_id = ?", articleID).Delete(&models.LinkOptOut{}).Error; err != nil {
+		return fmt.Errorf("删除内链排除记录失败: %w", err)
+	}
+
+	if _, err := s.Relink(articleID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,759 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/ai"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// 提示模板类型
+const (
+	PromptKindArticle    = "article"    // 单次生成全文
+	PromptKindOutline    = "outline"    // 分段模式：大纲
+	PromptKindSection    = "section"    // 分段模式：正文小节
+	PromptKindIntro      = "intro"      // 分段模式：引言
+	PromptKindConclusion = "conclusion" // 分段模式：总结
+)
+
+// DefaultArticlePrompt 默认全文提示模板
+const DefaultArticlePrompt = `
+请根据以下关键词，创作一篇关于养生/中医/修行的高质量文章：
+
+主要关键词: {{.Keyword}}
+{{- if .Category}}
+所属分类: {{.Category}}
+{{- end}}
+{{- if .SecondaryKeywords}}
+次要关键词: {{join .SecondaryKeywords "、"}}
+{{- end}}
+{{- if .RelatedKeywords}}
+相关关键词: {{join .RelatedKeywords "、"}}
+{{- end}}
+
+文章要求:
+1. 标题需要包含主关键词，吸引人点击
+2. 内容长度在{{.MinLength}}-{{.MaxLength}}字之间
+3. 分段清晰，每段不超过300字
+4. 使用二级标题(##)和三级标题(###)组织内容
+5. 内容需要专业、准确、有深度
+6. 适当引用中医经典或科学研究支持观点
+7. 结尾要有总结和实用建议
+{{- if .SecondaryKeywords}}
+8. 次要关键词是同一主题下的其他搜索词，用小标题或段落逐一覆盖，一篇文章满足这些搜索需求
+{{- end}}
+
+文章格式:
+- 使用Markdown格式
+- 标题使用一级标题(#)
+- 正文分段使用空行隔开
+- 重要概念可以使用**加粗**标记
+- 可以适当使用列表展示步骤或要点
+
+请确保内容原创、有价值，避免虚假或误导性信息。
+`
+
+// DefaultOutlinePrompt 默认大纲提示模板
+const DefaultOutlinePrompt = `
+请为一篇关于养生/中医/修行的文章设计大纲。
+
+主要关键词: {{.Keyword}}
+{{- if .SecondaryKeywords}}
+次要关键词: {{join .SecondaryKeywords "、"}}
+{{- end}}
+相关关键词: {{if .RelatedKeywords}}{{join .RelatedKeywords "、"}}{{else}}无{{end}}
+
+大纲要求:
+1. 标题需要包含主关键词，吸引人点击
+2. 设计{{.MinSections}}-{{.MaxSections}}个二级标题，覆盖读者搜索该关键词时最关心的问题
+3. 每个二级标题下列出2-4个要点
+4. 合理融入相关关键词，不要堆砌
+{{- if .SecondaryKeywords}}
+5. 次要关键词是同一主题下的其他搜索词，每个次要关键词都要有对应的二级标题或要点
+{{- end}}
+
+只返回JSON，不要包含任何其他内容，格式如下:
+{"title": "文章标题", "sections": [{"heading": "二级标题", "points": ["要点1", "要点2"]}]}
+`
+
+// DefaultSectionPrompt 默认正文小节提示模板
+const DefaultSectionPrompt = `
+你正在撰写一篇题为《{{.Title}}》的养生/中医/修行文章，主要关键词是「{{.Keyword}}」。
+
+文章完整大纲:
+{{.Outline}}
+{{if .Previous}}
+上一节结尾:
+{{.Previous}}
+{{end}}
+现在请撰写其中一节:
+
+## {{.Heading}}
+
+本节要点:
+{{range .Points}}- {{.}}
+{{end}}
+写作要求:
+1. 以"## {{.Heading}}"开头，只写这一节，不要写文章标题、引言或总结
+2. 本节长度在{{.MinLength}}-{{.MaxLength}}字之间
+3. 可以使用三级标题(###)、列表和**加粗**组织内容
+4. 内容专业、准确，适当引用中医经典或科学研究
+5. 与前文自然衔接，不要重复前文已讲过的内容
+
+请使用Markdown格式，确保内容原创、有价值，避免虚假或误导性信息。
+`
+
+// DefaultIntroPrompt 默认引言提示模板
+const DefaultIntroPrompt = `
+请为一篇题为《{{.Title}}》的养生/中医/修行文章撰写引言，主要关键词是「{{.Keyword}}」。
+
+文章包含以下章节:
+{{.Outline}}
+
+写作要求:
+1. 长度在150-250字之间
+2. 在第一段自然地包含主关键词
+3. 点明读者关心的问题，概括文章将解答的内容
+4. 不要使用任何标题，直接输出正文段落
+`
+
+// DefaultConclusionPrompt 默认总结提示模板
+const DefaultConclusionPrompt = `
+请为一篇题为《{{.Title}}》的养生/中医/修行文章撰写总结，主要关键词是「{{.Keyword}}」。
+
+文章包含以下章节:
+{{.Outline}}
+
+写作要求:
+1. 以"## 总结与建议"开头
+2. 长度在200-300字之间
+3. 概括全文要点，并给出3-5条实用建议
+4. 提醒读者身体不适时及时就医，不要夸大功效
+`
+
+// defaultPromptTemplate 系统内置模板
+type defaultPromptTemplate struct {
+	Name   string
+	Kind   string
+	Intent string
+	Body   string
+}
+
+// defaultPromptTemplates 各类型的默认模板
+var defaultPromptTemplates = []defaultPromptTemplate{
+	{Name: "默认全文模板", Kind: PromptKindArticle, Body: DefaultArticlePrompt},
+	{Name: "默认大纲模板", Kind: PromptKindOutline, Body: DefaultOutlinePrompt},
+	{Name: "默认小节模板", Kind: PromptKindSection, Body: DefaultSectionPrompt},
+	{Name: "默认引言模板", Kind: PromptKindIntro, Body: DefaultIntroPrompt},
+	{Name: "默认总结模板", Kind: PromptKindConclusion, Body: DefaultConclusionPrompt},
+}
+
+// intentStructure 领域内容意图的默认模板：在通用模板中插入对应的文章结构要求
+type intentStructure struct {
+	Label   string // 模板名称后缀
+	Article string // 全文模板的结构要求，插入到"文章格式"之前
+	Outline string // 大纲模板的结构要求，插入到输出格式说明之前
+}
+
+// intentStructures 各领域内容意图的文章结构
+var intentStructures = map[string]intentStructure{
+	seo.DomainIntentRecipe: {
+		Label: "食谱",
+		Article: `文章结构:
+- 开头说明这道食疗方的功效和适合人群
+- 使用"## 食材清单"列出全部食材及用量
+- 使用"## 制作步骤"按顺序编号写出每一步，注明火候和时间
+- 说明食用方法、适宜人群和禁忌人群
+`,
+		Outline: `大纲结构:
+- 必须包含"食材清单"和"制作步骤"两个二级标题，食材清单的要点为食材及用量，制作步骤的要点为按顺序的步骤
+- 另设二级标题说明功效、食用方法和禁忌
+`,
+	},
+	seo.DomainIntentExercise: {
+		Label: "功法",
+		Article: `文章结构:
+- 开头介绍功法或运动的来历和主要作用
+- 使用"## 动作要领"按顺序编号分解每个动作，说明姿势、呼吸和意念
+- 说明练习时长、频次和循序渐进的方法
+- 使用"## 注意事项"列出常见错误和不适合练习的人群
+`,
+		Outline: `大纲结构:
+- 必须包含"动作要领"二级标题，要点为按顺序分解的动作
+- 另设二级标题说明练习频次和注意事项
+`,
+	},
+	seo.DomainIntentSymptom: {
+		Label: "症状",
+		Article: `文章结构:
+- 使用"## 常见表现"描述症状的具体表现
+- 使用"## 常见原因"从中医辨证角度分析成因，并提及现代医学的常见原因
+- 使用"## 调理方法"从饮食、作息、穴位、运动等方面给出调理建议
+- 使用"## 何时就医"说明需要及时就医的情况
+`,
+		Outline: `大纲结构:
+- 必须包含"常见表现"、"常见原因"、"调理方法"和"何时就医"四个二级标题
+`,
+	},
+	seo.DomainIntentTheory: {
+		Label: "理论",
+		Article: `文章结构:
+- 开头用通俗的语言解释概念
+- 使用"## 经典出处"引用相关的中医经典原文并解释
+- 说明该理论在中医体系中的作用和与其他概念的关系
+- 使用"## 生活中的应用"结合日常养生举例说明
+`,
+		Outline: `大纲结构:
+- 必须包含"经典出处"和"生活中的应用"两个二级标题，其余章节由浅入深解释概念
+`,
+	},
+}
+
+// defaultIntentTemplates 各领域内容意图的默认全文和大纲模板
+func defaultIntentTemplates() []defaultPromptTemplate {
+	var templates []defaultPromptTemplate
+	for _, intent := range seo.DomainIntents {
+		structure, ok := intentStructures[intent]
+		if !ok {
+			continue
+		}
+		templates = append(templates,
+			defaultPromptTemplate{
+				Name:   "默认全文模板（" + structure.Label + "）",
+				Kind:   PromptKindArticle,
+				Intent: intent,
+				Body:   strings.Replace(DefaultArticlePrompt, "\n文章格式:", "\n"+structure.Article+"\n文章格式:", 1),
+			},
+			defaultPromptTemplate{
+				Name:   "默认大纲模板（" + structure.Label + "）",
+				Kind:   PromptKindOutline,
+				Intent: intent,
+				Body:   strings.Replace(DefaultOutlinePrompt, "\n只返回JSON", "\n"+structure.Outline+"\n只返回JSON", 1),
+			},
+		)
+	}
+	return templates
+}
+
+// promptFuncs 模板中可用的函数
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// PromptData 提示模板变量
+type PromptData struct {
+	Keyword         string   `json:"keyword"`
+	Category        string   `json:"category"`
+	RelatedKeywords []string `json:"related_keywords"`
+	// 以聚类为目标生成时，聚类中除主关键词外的其他关键词
+	SecondaryKeywords []string `json:"secondary_keywords"`
+	// 关键词的搜索意图和领域内容意图，用于选择模板，也可以在模板中使用
+	Intent       string `json:"intent"`
+	DomainIntent string `json:"domain_intent"`
+	MinLength    int    `json:"min_length"`
+	MaxLength    int    `json:"max_length"`
+	SiteName     string `json:"site_name"`
+
+	// 分段生成使用的变量
+	Title       string   `json:"title"`
+	Outline     string   `json:"outline"`
+	Heading     string   `json:"heading"`
+	Points      []string `json:"points"`
+	Previous    string   `json:"previous"`
+	MinSections int      `json:"min_sections"`
+	MaxSections int      `json:"max_sections"`
+}
+
+// RenderedPrompt 渲染后的提示
+type RenderedPrompt struct {
+	TemplateID        uint   `json:"template_id"`
+	TemplateVersionID uint   `json:"template_version_id"`
+	Version           int    `json:"version"`
+	SystemPrompt      string `json:"system_prompt"`
+	Prompt            string `json:"prompt"`
+}
+
+// PromptService 提示模板服务
+type PromptService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+// NewPromptService 创建提示模板服务
+func NewPromptService(db *gorm.DB, cfg *config.Config) *PromptService {
+	return &PromptService{
+		db:     db,
+		config: cfg,
+	}
+}
+
+// InitDefaultTemplates 初始化默认提示模板，已存在的类型和意图不会覆盖
+func (s *PromptService) InitDefaultTemplates() error {
+	for _, def := range append(defaultPromptTemplates, defaultIntentTemplates()...) {
+		var count int64
+		if err := s.db.Model(&models.PromptTemplate{}).Where("kind = ? AND intent = ?", def.Kind, def.Intent).Count(&count).Error; err != nil {
+			return fmt.Errorf("检查提示模板失败: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if _, err := s.CreateTemplate(def.Name, def.Kind, def.Intent, "系统内置模板", true, ai.DefaultSystemPrompt, def.Body, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListTemplates 获取提示模板列表
+func (s *PromptService) ListTemplates(kind string) ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+
+	query := s.db.Preload("Categories")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	if err := query.Order("kind, id").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("查询提示模板失败: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate 获取提示模板及其所有版本
+func (s *PromptService) GetTemplate(id uint) (*models.PromptTemplate, error) {
+	var tmpl models.PromptTemplate
+	if err := s.db.Preload("Categories").
+		Preload("Versions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("version DESC")
+		}).
+		First(&tmpl, id).Error; err != nil {
+		return nil, fmt.Errorf("查询提示模板失败: %w", err)
+	}
+	return &tmpl, nil
+}
+
+// CreateTemplate 创建提示模板及其第一个版本，intent为空时适用所有意图
+func (s *PromptService) CreateTemplate(name, kind, intent, description string, isDefault bool, systemPrompt, body string, categoryIDs []uint) (*models.PromptTemplate, error) {
+	if !validPromptKind(kind) {
+		return nil, fmt.Errorf("无效的模板类型: %s", kind)
+	}
+	if intent != "" && !seo.ValidIntent(intent) && !seo.ValidDomainIntent(intent) {
+		return nil, fmt.Errorf("无效的关键词意图: %s", intent)
+	}
+	if err := validatePromptBody(body); err != nil {
+		return nil, err
+	}
+
+	tmpl := models.PromptTemplate{
+		Name:          name,
+		Kind:          kind,
+		Intent:        intent,
+		Description:   description,
+		IsDefault:     isDefault,
+		ActiveVersion: 1,
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	// 同一类型和意图只保留一个默认模板
+	if isDefault {
+		if err := tx.Model(&models.PromptTemplate{}).Where("kind = ? AND intent = ?", kind, intent).Update("is_default", false).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("更新默认模板失败: %w", err)
+		}
+	}
+
+	if err := tx.Create(&tmpl).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建提示模板失败: %w", err)
+	}
+
+	version := models.PromptTemplateVersion{
+		TemplateID:   tmpl.ID,
+		Version:      1,
+		SystemPrompt: systemPrompt,
+		Body:         body,
+		Note:         "初始版本",
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建模板版本失败: %w", err)
+	}
+
+	if err := s.assignCategories(tx, &tmpl, categoryIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetTemplate(tmpl.ID)
+}
+
+// CreateVersion 为模板创建新版本
+func (s *PromptService) CreateVersion(templateID uint, systemPrompt, body, note string, userID *uint, activate bool) (*models.PromptTemplateVersion, error) {
+	if err := validatePromptBody(body); err != nil {
+		return nil, err
+	}
+
+	var tmpl models.PromptTemplate
+	if err := s.db.First(&tmpl, templateID).Error; err != nil {
+		return nil, fmt.Errorf("查询提示模板失败: %w", err)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	var latest int
+	if err := tx.Model(&models.PromptTemplateVersion{}).
+		Where("template_id = ?", templateID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("查询模板版本失败: %w", err)
+	}
+
+	version := models.PromptTemplateVersion{
+		TemplateID:   templateID,
+		Version:      latest + 1,
+		SystemPrompt: systemPrompt,
+		Body:         body,
+		Note:         note,
+		CreatedBy:    userID,
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("创建模板版本失败: %w", err)
+	}
+
+	if activate {
+		if err := tx.Model(&tmpl).Update("active_version", version.Version).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("启用模板版本失败: %w", err)
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return &version, nil
+}
+
+// ActivateVersion 启用模板的指定版本，可用于回滚
+func (s *PromptService) ActivateVersion(templateID uint, version int) (*models.PromptTemplate, error) {
+	if _, err := s.getVersion(templateID, version); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&models.PromptTemplate{}).Where("id = ?", templateID).
+		Update("active_version", version).Error; err != nil {
+		return nil, fmt.Errorf("启用模板版本失败: %w", err)
+	}
+
+	return s.GetTemplate(templateID)
+}
+
+// AssignCategories 设置模板适用的分类
+func (s *PromptService) AssignCategories(templateID uint, categoryIDs []uint) (*models.PromptTemplate, error) {
+	var tmpl models.PromptTemplate
+	if err := s.db.First(&tmpl, templateID).Error; err != nil {
+		return nil, fmt.Errorf("查询提示模板失败: %w", err)
+	}
+
+	// 开始事务
+	tx := s.db.Begin()
+
+	if err := tx.Model(&tmpl).Association("Categories").Clear(); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("清除分类关联失败: %w", err)
+	}
+
+	if err := s.assignCategories(tx, &tmpl, categoryIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return s.GetTemplate(templateID)
+}
+
+// assignCategories 关联模板和分类
+func (s *PromptService) assignCategories(tx *gorm.DB, tmpl *models.PromptTemplate, categoryIDs []uint) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	var categories []models.Category
+	if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return fmt.Errorf("查询分类失败: %w", err)
+	}
+
+	if err := tx.Model(tmpl).Association("Categories").Append(categories); err != nil {
+		return fmt.Errorf("关联分类失败: %w", err)
+	}
+	return nil
+}
+
+// Resolve 查找指定类型在给定分类和意图下启用的模板版本，intents按优先级排列，空值会被忽略。
+// 查找顺序：分类下的意图模板、分类下的通用模板、意图默认模板、通用默认模板，
+// 分类模板是人工指定的，优先于系统内置的意图模板
+func (s *PromptService) Resolve(kind string, categoryIDs []uint, intents ...string) (*models.PromptTemplateVersion, error) {
+	var candidates []string
+	for _, intent := range intents {
+		if intent != "" {
+			candidates = append(candidates, intent)
+		}
+	}
+	candidates = append(candidates, "")
+
+	var tmpl models.PromptTemplate
+	err := gorm.ErrRecordNotFound
+
+	if len(categoryIDs) > 0 {
+		for _, intent := range candidates {
+			err = s.db.Joins("JOIN prompt_template_categories ON prompt_template_categories.prompt_template_id = prompt_templates.id").
+				Where("prompt_templates.kind = ? AND prompt_templates.intent = ? AND prompt_template_categories.category_id IN ?", kind, intent, categoryIDs).
+				Order("prompt_templates.id").
+				First(&tmpl).Error
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+		}
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		for _, intent := range candidates {
+			err = s.db.Where("kind = ? AND intent = ? AND is_default = ?", kind, intent, true).First(&tmpl).Error
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询%s提示模板失败: %w", kind, err)
+	}
+
+	return s.getVersion(tmpl.ID, tmpl.ActiveVersion)
+}
+
+// getVersion 获取模板的指定版本
+func (s *PromptService) getVersion(templateID uint, version int) (*models.PromptTemplateVersion, error) {
+	var v models.PromptTemplateVersion
+	if err := s.db.Where("template_id = ? AND version = ?", templateID, version).First(&v).Error; err != nil {
+		return nil, fmt.Errorf("查询模板版本失败: %w", err)
+	}
+	return &v, nil
+}
+
+// GetVersionByID 根据ID获取模板版本
+func (s *PromptService) GetVersionByID(id uint) (*models.PromptTemplateVersion, error) {
+	var v models.PromptTemplateVersion
+	if err := s.db.First(&v, id).Error; err != nil {
+		return nil, fmt.Errorf("查询模板版本失败: %w", err)
+	}
+	return &v, nil
+}
+
+// Render 使用模板版本渲染提示
+func (s *PromptService) Render(version *models.PromptTemplateVersion, data PromptData) (*RenderedPrompt, error) {
+	prompt, err := executePrompt(version.Body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt := version.SystemPrompt
+	if systemPrompt == "" {
+		systemPrompt = ai.DefaultSystemPrompt
+	}
+
+	return &RenderedPrompt{
+		TemplateID:        version.TemplateID,
+		TemplateVersionID: version.ID,
+		Version:           version.Version,
+		SystemPrompt:      systemPrompt,
+		Prompt:            prompt,
+	}, nil
+}
+
+// RenderKind 查找指定类型的模板并渲染，领域内容意图的模板优先于搜索意图的模板
+func (s *PromptService) RenderKind(kind string, categoryIDs []uint, data PromptData) (*RenderedPrompt, error) {
+	version, err := s.Resolve(kind, categoryIDs, data.DomainIntent, data.Intent)
+	if err != nil {
+		return nil, err
+	}
+	return s.Render(version, data)
+}
+
+// Preview 预览模板渲染结果，body不为空时渲染未保存的模板内容
+func (s *PromptService) Preview(templateID uint, version int, systemPrompt, body string, data PromptData) (*RenderedPrompt, error) {
+	if body != "" {
+		return s.Render(&models.PromptTemplateVersion{
+			TemplateID:   templateID,
+			SystemPrompt: systemPrompt,
+			Body:         body,
+		}, data)
+	}
+
+	if version <= 0 {
+		var tmpl models.PromptTemplate
+		if err := s.db.First(&tmpl, templateID).Error; err != nil {
+			return nil, fmt.Errorf("查询提示模板失败: %w", err)
+		}
+		version = tmpl.ActiveVersion
+	}
+
+	v, err := s.getVersion(templateID, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.Render(v, data)
+}
+
+// BuildData 根据关键词和分类构建模板变量
+func (s *PromptService) BuildData(keyword models.Keyword, categoryIDs []uint) (PromptData, error) {
+	data := PromptData{
+		Keyword:      keyword.Word,
+		Intent:       keyword.Intent,
+		DomainIntent: keyword.DomainIntent,
+		MinLength:    s.config.Content.ArticleMinLength,
+		MaxLength:    s.config.Content.ArticleMaxLength,
+		SiteName:     s.config.SEO.SiteName,
+	}
+
+	// 分类名称：优先使用指定的分类，否则使用关键词所属分类
+	var category models.Category
+	var err error
+	if len(categoryIDs) > 0 {
+		err = s.db.Where("id IN ?", categoryIDs).Order("id").First(&category).Error
+	} else {
+		err = s.db.Joins("JOIN category_keywords ON category_keywords.category_id = categories.id").
+			Where("category_keywords.keyword_id = ?", keyword.ID).
+			Order("categories.id").
+			First(&category).Error
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return data, fmt.Errorf("查询分类失败: %w", err)
+	}
+	data.Category = category.Name
+
+	related, err := s.relatedKeywords(keyword, 5)
+	if err != nil {
+		return data, err
+	}
+	data.RelatedKeywords = related
+
+	return data, nil
+}
+
+// relatedKeywords 查询同分类下搜索量较高的其他关键词
+func (s *PromptService) relatedKeywords(keyword models.Keyword, limit int) ([]string, error) {
+	categoryIDs := s.db.Table("category_keywords").Select("category_id").Where("keyword_id = ?", keyword.ID)
+	keywordIDs := s.db.Table("category_keywords").Select("keyword_id").Where("category_id IN (?)", categoryIDs)
+
+	var words []string
+	if err := s.db.Model(&models.Keyword{}).
+		Where("id IN (?) AND id != ?", keywordIDs, keyword.ID).
+		Order("search_volume DESC").
+		Limit(limit).
+		Pluck("word", &words).Error; err != nil {
+		return nil, fmt.Errorf("查询相关关键词失败: %w", err)
+	}
+	return words, nil
+}
+
+// AddClusterData 以聚类为目标生成时，加入聚类中的次要关键词（按搜索量从高到低，最多limit个），并从相关关键词中去掉
+func (s *PromptService) AddClusterData(data *PromptData, clusterID, primaryKeywordID uint, limit int) error {
+	var words []string
+	if err := s.db.Model(&models.Keyword{}).
+		Where("cluster_id = ? AND id != ?", clusterID, primaryKeywordID).
+		Order("search_volume DESC, id ASC").
+		Limit(limit).
+		Pluck("word", &words).Error; err != nil {
+		return fmt.Errorf("查询聚类关键词失败: %w", err)
+	}
+	data.SecondaryKeywords = words
+
+	secondary := make(map[string]bool, len(words))
+	for _, word := range words {
+		secondary[word] = true
+	}
+	related := data.RelatedKeywords[:0]
+	for _, word := range data.RelatedKeywords {
+		if !secondary[word] {
+			related = append(related, word)
+		}
+	}
+	data.RelatedKeywords = related
+
+	return nil
+}
+
+// executePrompt 执行模板
+func executePrompt(body string, data PromptData) (string, error) {
+	tmpl, err := template.New("prompt").Funcs(promptFuncs).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("解析提示模板失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染提示模板失败: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// validatePromptBody 使用示例数据校验模板能否正常渲染
+func validatePromptBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("模板内容不能为空")
+	}
+
+	_, err := executePrompt(body, PromptData{
+		Keyword:           "示例关键词",
+		Category:          "示例分类",
+		RelatedKeywords:   []string{"相关词1", "相关词2"},
+		SecondaryKeywords: []string{"次要词1", "次要词2"},
+		Intent:            seo.IntentInformational,
+		DomainIntent:      seo.DomainIntentRecipe,
+		MinLength:         1000,
+		MaxLength:         3000,
+		SiteName:          "示例站点",
+		Title:             "示例标题",
+		Outline:           "1. 示例章节",
+		Heading:           "示例章节",
+		Points:            []string{"要点1", "要点2"},
+		Previous:          "上一节内容",
+		MinSections:       3,
+		MaxSections:       6,
+	})
+	return err
+}
+
+// validPromptKind 判断模板类型是否有效
+func validPromptKind(kind string) bool {
+	for _, def := range defaultPromptTemplates {
+		if def.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
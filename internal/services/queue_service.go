@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/NietzscheX/seo-generate/config"
@@ -32,6 +33,7 @@ type GenerationTask struct {
 	ID          string     `json:"id"`
 	KeywordID   uint       `json:"keyword_id"`
 	CategoryIDs []uint     `json:"category_ids"`
+	ClusterID   *uint      `json:"cluster_id,omitempty"` // 以关键词聚类为目标生成，关键词为聚类的主关键词
 	Status      TaskStatus `json:"status"`
 	Error       string     `json:"error,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
@@ -79,6 +81,11 @@ func (s *QueueService) AddTask(ctx context.Context, task *GenerationTask) error
 		return fmt.Errorf("添加任务到队列失败: %v", err)
 	}
 
+	// 关键词进入生成队列，不再出现在选题队列中
+	if err := markKeywordsQueued(s.db, []uint{task.KeywordID}); err != nil {
+		log.Printf("关键词 %d 更新为生成中失败: %v", task.KeywordID, err)
+	}
+
 	return nil
 }
 
@@ -137,7 +144,19 @@ func (s *QueueService) ProcessTasks(ctx context.Context) {
 			task.UpdatedAt = time.Now()
 			s.updateTaskStatus(ctx, &task)
 
-			// 获取关键词
+			// 获取关键词，以聚类为目标时使用聚类当前的主关键词
+			if task.ClusterID != nil {
+				var keywordCluster models.KeywordCluster
+				if err := s.db.First(&keywordCluster, *task.ClusterID).Error; err != nil || keywordCluster.PrimaryKeywordID == nil {
+					task.Status = TaskStatusFailed
+					task.Error = fmt.Sprintf("获取关键词聚类失败: %v", err)
+					s.updateTaskStatus(ctx, &task)
+					s.releaseKeyword(task.KeywordID)
+					continue
+				}
+				task.KeywordID = *keywordCluster.PrimaryKeywordID
+			}
+
 			var keyword models.Keyword
 			if err := s.db.First(&keyword, task.KeywordID).Error; err != nil {
 				task.Status = TaskStatusFailed
@@ -147,11 +166,14 @@ func (s *QueueService) ProcessTasks(ctx context.Context) {
 			}
 
 			// 生成文章
-			article, err := s.contentService.GenerateArticle(ctx, keyword, task.CategoryIDs)
+			article, err := s.contentService.GenerateArticleWithOptions(ctx, keyword, task.CategoryIDs, GenerateOptions{
+				ClusterID: task.ClusterID,
+			})
 			if err != nil {
 				task.Status = TaskStatusFailed
 				task.Error = fmt.Sprintf("生成文章失败: %v", err)
 				s.updateTaskStatus(ctx, &task)
+				s.releaseKeyword(task.KeywordID)
 				continue
 			}
 
@@ -169,6 +191,13 @@ func (s *QueueService) ProcessTasks(ctx context.Context) {
 	}
 }
 
+// releaseKeyword 生成失败时关键词离开生成队列，重新回到选题队列
+func (s *QueueService) releaseKeyword(keywordID uint) {
+	if _, err := releaseQueuedKeywords(s.db, []uint{keywordID}); err != nil {
+		log.Printf("关键词 %d 恢复为可选题失败: %v", keywordID, err)
+	}
+}
+
 // updateTaskStatus 更新任务状态
 func (s *QueueService) updateTaskStatus(ctx context.Context, task *GenerationTask) {
 	taskJSON, _ := json.Marshal(task)
@@ -219,3 +248,34 @@ func (s *QueueService) BatchAddTasks(ctx context.Context, keywordIDs []uint, cat
 
 	return taskIDs, nil
 }
+
+// BatchAddClusterTasks 批量添加以关键词聚类为目标的任务，每个聚类生成一篇文章
+func (s *QueueService) BatchAddClusterTasks(ctx context.Context, clusterIDs []uint, categoryIDs []uint, userID uint) ([]string, error) {
+	var taskIDs []string
+	for _, clusterID := range clusterIDs {
+		var keywordCluster models.KeywordCluster
+		if err := s.db.First(&keywordCluster, clusterID).Error; err != nil {
+			return nil, fmt.Errorf("获取关键词聚类失败: %v", err)
+		}
+		if keywordCluster.PrimaryKeywordID == nil {
+			return nil, fmt.Errorf("关键词聚类%d没有主关键词", clusterID)
+		}
+
+		id := clusterID
+		task := &GenerationTask{
+			ID:          fmt.Sprintf("task_cluster_%d_%d", clusterID, time.Now().UnixNano()),
+			KeywordID:   *keywordCluster.PrimaryKeywordID,
+			CategoryIDs: categoryIDs,
+			ClusterID:   &id,
+			UserID:      userID,
+		}
+
+		if err := s.AddTask(ctx, task); err != nil {
+			return nil, fmt.Errorf("添加任务失败: %v", err)
+		}
+
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	return taskIDs, nil
+}
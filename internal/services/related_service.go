@@ -0,0 +1,279 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/segment"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxRelatedArticles 每篇文章缓存的最多相关文章数
+	MaxRelatedArticles = 20
+
+	// relatedCacheTTL 缓存的最长有效期，文章变更时会提前失效
+	relatedCacheTTL = time.Hour
+	// relatedQueryTerms 用作相似度查询的关键词数量
+	relatedQueryTerms = 30
+	// relatedTitleWeight 标题在索引中重复的次数，使标题中的词权重更高
+	relatedTitleWeight = 3
+	// relatedRecencyHalfLife 时效得分减半的天数
+	relatedRecencyHalfLife = 90.0
+
+	// 各项得分的权重
+	relatedTextWeight     = 0.6
+	relatedCategoryWeight = 0.25
+	relatedRecencyWeight  = 0.15
+)
+
+// RelatedArticle 相关文章及其得分
+type RelatedArticle struct {
+	models.Article
+	Score         float64 `json:"score"`
+	TextScore     float64 `json:"text_score"`
+	CategoryScore float64 `json:"category_score"`
+	RecencyScore  float64 `json:"recency_score"`
+}
+
+// relatedCorpus 已发布文章的分词索引
+type relatedCorpus struct {
+	index      *segment.Index
+	segmenter  *segment.Segmenter
+	articles   map[uint]*models.Article
+	tokens     map[uint][]string
+	categories map[uint]map[uint]bool
+	expiresAt  time.Time
+}
+
+// relatedEntry 缓存的相关文章
+type relatedEntry struct {
+	articles  []RelatedArticle
+	expiresAt time.Time
+}
+
+// RelatedService 相关文章服务：综合正文相似度（BM25）、分类重合度和发布时间排序，结果缓存在内存中
+type RelatedService struct {
+	db     *gorm.DB
+	mu     sync.RWMutex
+	corpus *relatedCorpus
+	cache  map[uint]*relatedEntry
+}
+
+// NewRelatedService 创建相关文章服务
+func NewRelatedService(db *gorm.DB) *RelatedService {
+	return &RelatedService{
+		db:    db,
+		cache: make(map[uint]*relatedEntry),
+	}
+}
+
+// Invalidate 清空索引和缓存，文章发布、更新或删除后调用
+func (s *RelatedService) Invalidate() {
+	s.mu.Lock()
+	s.corpus = nil
+	s.cache = make(map[uint]*relatedEntry)
+	s.mu.Unlock()
+}
+
+// GetRelatedArticles 获取相关文章，按得分从高到低排列
+func (s *RelatedService) GetRelatedArticles(articleID uint, limit int) ([]RelatedArticle, error) {
+	if limit <= 0 || limit > MaxRelatedArticles {
+		limit = MaxRelatedArticles
+	}
+
+	s.mu.RLock()
+	entry, ok := s.cache[articleID]
+	s.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		articles, err := s.rank(articleID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = &relatedEntry{articles: articles, expiresAt: time.Now().Add(relatedCacheTTL)}
+		s.mu.Lock()
+		s.cache[articleID] = entry
+		s.mu.Unlock()
+	}
+
+	if len(entry.articles) > limit {
+		return entry.articles[:limit], nil
+	}
+	return entry.articles, nil
+}
+
+// getCorpus 获取分词索引，过期后重新构建
+func (s *RelatedService) getCorpus() (*relatedCorpus, error) {
+	s.mu.RLock()
+	corpus := s.corpus
+	s.mu.RUnlock()
+	if corpus != nil && time.Now().Before(corpus.expiresAt) {
+		return corpus, nil
+	}
+
+	corpus, err := s.buildCorpus()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.corpus = corpus
+	s.mu.Unlock()
+
+	return corpus, nil
+}
+
+// buildCorpus 以关键词为词典对已发布文章分词并建立索引
+func (s *RelatedService) buildCorpus() (*relatedCorpus, error) {
+	var words []string
+	if err := s.db.Model(&models.Keyword{}).Pluck("word", &words).Error; err != nil {
+		return nil, fmt.Errorf("查询关键词失败: %w", err)
+	}
+
+	var articles []models.Article
+	if err := s.db.Preload("Categories").Where("status = ?", "published").Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("查询已发布文章失败: %w", err)
+	}
+
+	corpus := &relatedCorpus{
+		segmenter:  segment.NewSegmenter(words),
+		articles:   make(map[uint]*models.Article, len(articles)),
+		tokens:     make(map[uint][]string, len(articles)),
+		categories: make(map[uint]map[uint]bool, len(articles)),
+		expiresAt:  time.Now().Add(relatedCacheTTL),
+	}
+
+	docs := make([]segment.Document, 0, len(articles))
+	for i := range articles {
+		article := &articles[i]
+		tokens := corpus.segmenter.Segment(relatedText(article))
+
+		corpus.articles[article.ID] = article
+		corpus.tokens[article.ID] = tokens
+		corpus.categories[article.ID] = categorySet(article.Categories)
+		docs = append(docs, segment.Document{ID: article.ID, Tokens: tokens})
+	}
+	corpus.index = segment.NewIndex(docs)
+
+	return corpus, nil
+}
+
+// relatedText 用于计算相似度的文本，标题重复多次以提高权重
+func relatedText(article *models.Article) string {
+	parts := make([]string, 0, relatedTitleWeight+2)
+	for i := 0; i < relatedTitleWeight; i++ {
+		parts = append(parts, article.Title)
+	}
+	parts = append(parts, article.Summary, article.Content)
+	return strings.Join(parts, "\n")
+}
+
+// categorySet 文章所属分类的集合
+func categorySet(categories []models.Category) map[uint]bool {
+	set := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		set[category.ID] = true
+	}
+	return set
+}
+
+// rank 计算文章的相关文章排名
+func (s *RelatedService) rank(articleID uint) ([]RelatedArticle, error) {
+	corpus, err := s.getCorpus()
+	if err != nil {
+		return nil, err
+	}
+
+	// 未发布的文章不在索引中，单独分词
+	tokens, ok := corpus.tokens[articleID]
+	categories := corpus.categories[articleID]
+	if !ok {
+		var article models.Article
+		if err := s.db.Preload("Categories").First(&article, articleID).Error; err != nil {
+			return nil, fmt.Errorf("查询文章失败: %w", err)
+		}
+		tokens = corpus.segmenter.Segment(relatedText(&article))
+		categories = categorySet(article.Categories)
+	}
+
+	// 正文相似度按最高分归一化
+	query := corpus.index.KeyTerms(tokens, relatedQueryTerms)
+	textScores := make(map[uint]float64)
+	maxScore := 0.0
+	for _, result := range corpus.index.Search(query) {
+		if result.ID == articleID {
+			continue
+		}
+		textScores[result.ID] = result.Score
+		maxScore = math.Max(maxScore, result.Score)
+	}
+
+	now := time.Now()
+	related := make([]RelatedArticle, 0, len(corpus.articles))
+	for id, article := range corpus.articles {
+		if id == articleID {
+			continue
+		}
+
+		item := RelatedArticle{
+			Article:       *article,
+			CategoryScore: jaccard(categories, corpus.categories[id]),
+			RecencyScore:  recencyScore(article, now),
+		}
+		item.Content = "" // 列表中不需要正文
+		if maxScore > 0 {
+			item.TextScore = textScores[id] / maxScore
+		}
+		item.Score = relatedTextWeight*item.TextScore +
+			relatedCategoryWeight*item.CategoryScore +
+			relatedRecencyWeight*item.RecencyScore
+		related = append(related, item)
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].ID > related[j].ID
+	})
+
+	if len(related) > MaxRelatedArticles {
+		related = related[:MaxRelatedArticles]
+	}
+	return related, nil
+}
+
+// jaccard 两个分类集合的重合度
+func jaccard(a, b map[uint]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// recencyScore 发布时间越近得分越高，每过半衰期减半
+func recencyScore(article *models.Article, now time.Time) float64 {
+	published := article.CreatedAt
+	if article.PublishedAt != nil {
+		published = *article.PublishedAt
+	}
+
+	days := now.Sub(published).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return math.Pow(0.5, days/relatedRecencyHalfLife)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+// RobotsService robots.txt规则服务
+type RobotsService struct {
+	db         *gorm.DB
+	seoService *seo.SEOService
+	mu         sync.RWMutex
+	rules      []seo.RobotsRule
+}
+
+// NewRobotsService 创建robots.txt规则服务
+func NewRobotsService(db *gorm.DB, seoService *seo.SEOService) *RobotsService {
+	return &RobotsService{
+		db:         db,
+		seoService: seoService,
+	}
+}
+
+// InitDefaultRules 初始化默认规则
+func (s *RobotsService) InitDefaultRules() error {
+	// 检查是否已有规则
+	var count int64
+	if err := s.db.Model(&models.RobotsRule{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("检查robots规则数量失败: %w", err)
+	}
+
+	if count > 0 {
+		return nil // 已有规则，不需要初始化
+	}
+
+	defaults := seo.DefaultRobotsRules()
+	rules := make([]models.RobotsRule, 0, len(defaults))
+	for i, rule := range defaults {
+		rules = append(rules, models.RobotsRule{
+			UserAgent: rule.UserAgent,
+			Directive: rule.Directive,
+			Value:     rule.Value,
+			Position:  i,
+			Enabled:   true,
+		})
+	}
+
+	if err := s.db.Create(&rules).Error; err != nil {
+		return fmt.Errorf("创建默认robots规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// getRules 获取已启用的规则，规则变更后重新加载
+func (s *RobotsService) getRules() ([]seo.RobotsRule, error) {
+	s.mu.RLock()
+	rules := s.rules
+	s.mu.RUnlock()
+	if rules != nil {
+		return rules, nil
+	}
+
+	var records []models.RobotsRule
+	if err := s.db.Where("enabled = ?", true).Order("position, id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询robots规则失败: %w", err)
+	}
+
+	rules = make([]seo.RobotsRule, 0, len(records))
+	for _, record := range records {
+		rules = append(rules, seo.RobotsRule{
+			ID:        record.ID,
+			UserAgent: record.UserAgent,
+			Directive: record.Directive,
+			Value:     record.Value,
+		})
+	}
+
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+
+	return rules, nil
+}
+
+// invalidate 清除已加载的规则
+func (s *RobotsService) invalidate() {
+	s.mu.Lock()
+	s.rules = nil
+	s.mu.Unlock()
+}
+
+// RobotsTxt 生成robots.txt
+func (s *RobotsService) RobotsTxt() (string, error) {
+	rules, err := s.getRules()
+	if err != nil {
+		return "", err
+	}
+	return s.seoService.GenerateRobotsTxt(rules), nil
+}
+
+// Test 检测爬虫能否抓取URL
+func (s *RobotsService) Test(userAgent, rawURL string) (*seo.RobotsTestResult, error) {
+	rules, err := s.getRules()
+	if err != nil {
+		return nil, err
+	}
+	return seo.NewRobots(rules).Test(userAgent, rawURL)
+}
+
+// GetRules 获取robots规则列表
+func (s *RobotsService) GetRules(userAgent string) ([]models.RobotsRule, error) {
+	var rules []models.RobotsRule
+
+	query := s.db.Model(&models.RobotsRule{})
+	if userAgent != "" {
+		query = query.Where("user_agent = ?", userAgent)
+	}
+
+	if err := query.Order("position, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询robots规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule 创建robots规则
+func (s *RobotsService) CreateRule(rule models.RobotsRule) (*models.RobotsRule, error) {
+	if err := validateRobotsRule(&rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("创建robots规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return &rule, nil
+}
+
+// UpdateRule 更新robots规则，enabled为空时保持原启用状态
+func (s *RobotsService) UpdateRule(id uint, update models.RobotsRule, enabled *bool) (*models.RobotsRule, error) {
+	var rule models.RobotsRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		return nil, fmt.Errorf("查询robots规则失败: %w", err)
+	}
+
+	if err := validateRobotsRule(&update); err != nil {
+		return nil, err
+	}
+
+	rule.UserAgent = update.UserAgent
+	rule.Directive = update.Directive
+	rule.Value = update.Value
+	rule.Position = update.Position
+	if enabled != nil {
+		rule.Enabled = *enabled
+	}
+
+	if err := s.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("更新robots规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return &rule, nil
+}
+
+// DeleteRule 删除robots规则
+func (s *RobotsService) DeleteRule(id uint) error {
+	if err := s.db.Delete(&models.RobotsRule{}, id).Error; err != nil {
+		return fmt.Errorf("删除robots规则失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// validateRobotsRule 校验robots规则
+func validateRobotsRule(rule *models.RobotsRule) error {
+	return seo.ValidateRobotsRule(seo.RobotsRule{
+		UserAgent: rule.UserAgent,
+		Directive: rule.Directive,
+		Value:     rule.Value,
+	})
+}
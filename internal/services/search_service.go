@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/segment"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+const (
+	// searchSnippetLength 搜索结果摘要的字数
+	searchSnippetLength = 120
+	// searchIndexInterval 后台建立全文索引的间隔，新增和修改的内容在下一轮之后可以搜索到
+	searchIndexInterval = time.Minute
+	// searchIndexArticleBatch 每批建立索引的文章数
+	searchIndexArticleBatch = 100
+	// searchIndexKeywordBatch 每批建立索引的关键词数
+	searchIndexKeywordBatch = 1000
+)
+
+// ArticleSearchResult 文章搜索结果，高亮字段为转义后的HTML，匹配的词用<mark>标记
+type ArticleSearchResult struct {
+	ID             uint              `json:"id"`
+	Title          string            `json:"title"`
+	Slug           string            `json:"slug"`
+	Summary        string            `json:"summary"`
+	PublishedAt    *time.Time        `json:"published_at"`
+	ViewCount      int               `json:"view_count"`
+	Categories     []models.Category `json:"categories"`
+	Rank           float64           `json:"rank"`
+	TitleHighlight string            `json:"title_highlight"`
+	Snippet        string            `json:"snippet"`
+}
+
+// KeywordSearchResult 关键词搜索结果
+type KeywordSearchResult struct {
+	models.Keyword
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+}
+
+// SearchService 全文搜索服务：后台在Go中分词批量生成tsvector，查询时使用相同的分词结果
+type SearchService struct {
+	db *gorm.DB
+}
+
+// NewSearchService 创建全文搜索服务
+func NewSearchService(db *gorm.DB) *SearchService {
+	return &SearchService{
+		db: db,
+	}
+}
+
+// searchTerms 对查询分词，没有可搜索的词时返回空
+func searchTerms(query string) ([]string, string) {
+	terms := segment.Segment(query)
+	return terms, strings.Join(terms, " ")
+}
+
+// searchRank 搜索结果的ID和相关度
+type searchRank struct {
+	ID         uint
+	SearchRank float64
+}
+
+// tsMatch 全文检索的tsquery表达式和参数
+type tsMatch struct {
+	expr  string
+	query string
+}
+
+// hasSingleHan 查询中是否有单个汉字的词，全文索引中的汉字按相邻两字切分，单字无法匹配
+func hasSingleHan(terms []string) bool {
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) == 1 && unicode.Is(unicode.Han, []rune(term)[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyTermQuery 匹配任意一个词的tsquery，分词结果只有字母、数字和汉字，可以直接加引号
+func anyTermQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = "'" + term + "'"
+	}
+	return strings.Join(quoted, " | ")
+}
+
+// searchRanks 按相关度分页查询ID，相关度相同时按tieBreak排序。base每次调用返回新的基础查询。
+// 先要求匹配全部词，没有结果时改为匹配任意一个词；查询中有单个汉字时改为在columns中模糊匹配全部词
+func searchRanks(base func() *gorm.DB, terms []string, tsQuery string, columns []string, tieBreak string, page, pageSize int) ([]searchRank, int64, error) {
+	offset := (page - 1) * pageSize
+
+	if hasSingleHan(terms) {
+		query := base()
+		for _, term := range terms {
+			conditions := make([]string, len(columns))
+			args := make([]interface{}, len(columns))
+			for i, column := range columns {
+				conditions[i] = column + " ILIKE ?"
+				args[i] = "%" + term + "%"
+			}
+			query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		var ranks []searchRank
+		err := query.Select("id, 0 AS search_rank").
+			Order(tieBreak).
+			Offset(offset).Limit(pageSize).
+			Scan(&ranks).Error
+		return ranks, total, err
+	}
+
+	tsQueries := []tsMatch{{"plainto_tsquery('simple', ?)", tsQuery}}
+	if len(terms) > 1 {
+		tsQueries = append(tsQueries, tsMatch{"to_tsquery('simple', ?)", anyTermQuery(terms)})
+	}
+
+	for _, ts := range tsQueries {
+		query := base().Where("search_vector @@ "+ts.expr, ts.query)
+
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		if total == 0 {
+			continue
+		}
+
+		var ranks []searchRank
+		err := query.Select("id, ts_rank_cd(search_vector, "+ts.expr+") AS search_rank", ts.query).
+			Order("search_rank DESC, " + tieBreak).
+			Offset(offset).Limit(pageSize).
+			Scan(&ranks).Error
+		return ranks, total, err
+	}
+
+	return nil, 0, nil
+}
+
+// rankIDs 搜索结果的ID列表
+func rankIDs(ranks []searchRank) []uint {
+	ids := make([]uint, len(ranks))
+	for i, rank := range ranks {
+		ids[i] = rank.ID
+	}
+	return ids
+}
+
+// SearchArticles 搜索已发布文章，按相关度排序
+func (s *SearchService) SearchArticles(query string, page, pageSize int) ([]ArticleSearchResult, int64, error) {
+	terms, tsQuery := searchTerms(query)
+	if len(terms) == 0 {
+		return []ArticleSearchResult{}, 0, nil
+	}
+
+	// 分页查询相关度
+	base := func() *gorm.DB {
+		return s.db.Model(&models.Article{}).Where("status = ?", "published")
+	}
+	ranks, total, err := searchRanks(base, terms, tsQuery, []string{"title", "summary", "content"}, "published_at DESC", page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("搜索文章失败: %w", err)
+	}
+	if len(ranks) == 0 {
+		return []ArticleSearchResult{}, total, nil
+	}
+
+	var articles []models.Article
+	if err := s.db.Preload("Categories").Where("id IN ?", rankIDs(ranks)).Find(&articles).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询文章失败: %w", err)
+	}
+	byID := make(map[uint]*models.Article, len(articles))
+	for i := range articles {
+		byID[articles[i].ID] = &articles[i]
+	}
+
+	results := make([]ArticleSearchResult, 0, len(ranks))
+	for _, rank := range ranks {
+		article, ok := byID[rank.ID]
+		if !ok {
+			continue
+		}
+
+		text := strings.Join(strings.Fields(seo.StripMarkdown(article.Content)), " ")
+		results = append(results, ArticleSearchResult{
+			ID:             article.ID,
+			Title:          article.Title,
+			Slug:           article.Slug,
+			Summary:        article.Summary,
+			PublishedAt:    article.PublishedAt,
+			ViewCount:      article.ViewCount,
+			Categories:     article.Categories,
+			Rank:           rank.SearchRank,
+			TitleHighlight: segment.Highlight(article.Title, terms, 0),
+			Snippet:        segment.Highlight(text, terms, searchSnippetLength),
+		})
+	}
+
+	return results, total, nil
+}
+
+// SearchKeywords 搜索关键词，相关度相同时搜索量高的排在前面
+func (s *SearchService) SearchKeywords(query string, page, pageSize int) ([]KeywordSearchResult, int64, error) {
+	terms, tsQuery := searchTerms(query)
+	if len(terms) == 0 {
+		return []KeywordSearchResult{}, 0, nil
+	}
+
+	// 分页查询相关度
+	base := func() *gorm.DB {
+		return s.db.Model(&models.Keyword{})
+	}
+	ranks, total, err := searchRanks(base, terms, tsQuery, []string{"word"}, "search_volume DESC", page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("搜索关键词失败: %w", err)
+	}
+	if len(ranks) == 0 {
+		return []KeywordSearchResult{}, total, nil
+	}
+
+	var keywords []models.Keyword
+	if err := s.db.Where("id IN ?", rankIDs(ranks)).Find(&keywords).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询关键词失败: %w", err)
+	}
+	byID := make(map[uint]models.Keyword, len(keywords))
+	for _, keyword := range keywords {
+		byID[keyword.ID] = keyword
+	}
+
+	results := make([]KeywordSearchResult, 0, len(ranks))
+	for _, rank := range ranks {
+		keyword, ok := byID[rank.ID]
+		if !ok {
+			continue
+		}
+
+		results = append(results, KeywordSearchResult{
+			Keyword:   keyword,
+			Rank:      rank.SearchRank,
+			Highlight: segment.Highlight(keyword.Word, terms, 0),
+		})
+	}
+
+	return results, total, nil
+}
+
+// ProcessIndexing 定时为新增和修改过的文章和关键词建立全文索引
+func (s *SearchService) ProcessIndexing(ctx context.Context) {
+	ticker := time.NewTicker(searchIndexInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.IndexMissing(); err != nil {
+			log.Printf("建立全文索引失败: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IndexMissing 分批为没有全文检索向量的文章和关键词建立索引，包括新增、文本被修改和升级前的历史数据
+func (s *SearchService) IndexMissing() error {
+	var lastID uint
+	for {
+		var articles []models.Article
+		if err := s.db.Select("id", "title", "summary", "content", "updated_at").
+			Where("search_vector IS NULL AND id > ?", lastID).
+			Order("id").
+			Limit(searchIndexArticleBatch).
+			Find(&articles).Error; err != nil {
+			return fmt.Errorf("查询未索引文章失败: %w", err)
+		}
+		if len(articles) == 0 {
+			break
+		}
+		if err := models.UpdateArticleSearchVectors(s.db, articles); err != nil {
+			return fmt.Errorf("索引文章失败: %w", err)
+		}
+		lastID = articles[len(articles)-1].ID
+	}
+
+	lastID = 0
+	for {
+		var keywords []models.Keyword
+		if err := s.db.Select("id", "word").
+			Where("search_vector IS NULL AND id > ?", lastID).
+			Order("id").
+			Limit(searchIndexKeywordBatch).
+			Find(&keywords).Error; err != nil {
+			return fmt.Errorf("查询未索引关键词失败: %w", err)
+		}
+		if len(keywords) == 0 {
+			break
+		}
+		if err := models.UpdateKeywordSearchVectors(s.db, keywords); err != nil {
+			return fmt.Errorf("索引关键词失败: %w", err)
+		}
+		lastID = keywords[len(keywords)-1].ID
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"gorm.io/gorm"
+)
+
+const (
+	// SitemapIndexName Sitemap索引文件名
+	SitemapIndexName = "sitemap.xml"
+	// sitemapPagesName 首页Sitemap文件名
+	sitemapPagesName = "sitemap-pages.xml"
+	// sitemapArticlesPrefix 文章Sitemap文件名前缀，后接页码
+	sitemapArticlesPrefix = "sitemap-articles-"
+	// sitemapImagesPrefix 图片Sitemap文件名前缀，后接页码
+	sitemapImagesPrefix = "sitemap-images-"
+	// sitemapCategoriesPrefix 分类页Sitemap文件名前缀，后接页码
+	sitemapCategoriesPrefix = "sitemap-categories-"
+
+	// sitemapCacheTTL 缓存的最长有效期，文章变更时会提前失效
+	sitemapCacheTTL = time.Hour
+)
+
+// ErrSitemapNotFound 请求的Sitemap文件不存在
+var ErrSitemapNotFound = errors.New("Sitemap不存在")
+
+// SitemapFile 生成好的Sitemap文件
+type SitemapFile struct {
+	Data    []byte
+	Gzip    []byte
+	ModTime time.Time
+
+	expiresAt time.Time
+}
+
+// SitemapService Sitemap服务，生成结果缓存在内存中
+type SitemapService struct {
+	db         *gorm.DB
+	seoService *seo.SEOService
+	mu         sync.RWMutex
+	cache      map[string]*SitemapFile
+}
+
+// NewSitemapService 创建Sitemap服务
+func NewSitemapService(db *gorm.DB, seoService *seo.SEOService) *SitemapService {
+	return &SitemapService{
+		db:         db,
+		seoService: seoService,
+		cache:      make(map[string]*SitemapFile),
+	}
+}
+
+// Invalidate 清空缓存，文章发布、更新或删除后调用
+func (s *SitemapService) Invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string]*SitemapFile)
+	s.mu.Unlock()
+}
+
+// Get 获取Sitemap文件，name为sitemap.xml、sitemap-pages.xml、sitemap-articles-N.xml、sitemap-images-N.xml或sitemap-categories-N.xml
+func (s *SitemapService) Get(name string) (*SitemapFile, error) {
+	s.mu.RLock()
+	file, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(file.expiresAt) {
+		return file, nil
+	}
+
+	content, err := s.generate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 预先压缩，供.gz地址和支持gzip的客户端使用
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		return nil, fmt.Errorf("压缩Sitemap失败: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("压缩Sitemap失败: %w", err)
+	}
+
+	now := time.Now()
+	file = &SitemapFile{
+		Data:      []byte(content),
+		Gzip:      buf.Bytes(),
+		ModTime:   now,
+		expiresAt: now.Add(sitemapCacheTTL),
+	}
+
+	s.mu.Lock()
+	s.cache[name] = file
+	s.mu.Unlock()
+
+	return file, nil
+}
+
+// generate 根据文件名生成Sitemap
+func (s *SitemapService) generate(name string) (string, error) {
+	if name == SitemapIndexName {
+		return s.generateIndex()
+	}
+	if name == sitemapPagesName {
+		return s.generatePages()
+	}
+	if page, ok := sitemapPage(name, sitemapArticlesPrefix); ok {
+		return s.generateArticles(page)
+	}
+	if page, ok := sitemapPage(name, sitemapImagesPrefix); ok {
+		return s.generateImages(page)
+	}
+	if page, ok := sitemapPage(name, sitemapCategoriesPrefix); ok {
+		return s.generateCategories(page)
+	}
+	return "", ErrSitemapNotFound
+}
+
+// sitemapPage 从文件名中解析页码，页码从1开始
+func sitemapPage(name, prefix string) (int, bool) {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".xml") {
+		return 0, false
+	}
+	page, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".xml"))
+	if err != nil || page < 1 {
+		return 0, false
+	}
+	return page, true
+}
+
+// publishedArticles 已发布文章的查询，规范URL指向其他文章的不列入Sitemap
+func (s *SitemapService) publishedArticles() *gorm.DB {
+	return s.db.Model(&models.Article{}).Where("status = ? AND canonical_url = ''", "published")
+}
+
+// withImages 只保留正文中包含Markdown图片的文章
+func withImages(db *gorm.DB) *gorm.DB {
+	return db.Where("content LIKE ?", "%![%")
+}
+
+// articleTimes 只查询文章的修改时间，按ID排序保证分页稳定
+func articleTimes(query *gorm.DB) ([]models.Article, error) {
+	var articles []models.Article
+	if err := query.Select("id, updated_at, published_at").Order("id ASC").Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("查询文章修改时间失败: %w", err)
+	}
+	return articles, nil
+}
+
+// generateIndex 生成Sitemap索引，每个文件的lastmod为其中最新文章的修改时间
+func (s *SitemapService) generateIndex() (string, error) {
+	articles, err := articleTimes(s.publishedArticles())
+	if err != nil {
+		return "", err
+	}
+	withImage, err := articleTimes(withImages(s.publishedArticles()))
+	if err != nil {
+		return "", err
+	}
+	categories, err := s.categoryTimes(0, 0)
+	if err != nil {
+		return "", err
+	}
+
+	entries := []seo.SitemapEntry{{
+		Loc:     s.seoService.SitemapURL(sitemapPagesName),
+		LastMod: seo.FormatLastMod(seo.LatestLastMod(articles)),
+	}}
+	entries = append(entries, s.pageEntries(sitemapArticlesPrefix, len(articles), func(start, end int) time.Time {
+		return seo.LatestLastMod(articles[start:end])
+	})...)
+	entries = append(entries, s.pageEntries(sitemapImagesPrefix, len(withImage), func(start, end int) time.Time {
+		return seo.LatestLastMod(withImage[start:end])
+	})...)
+	entries = append(entries, s.pageEntries(sitemapCategoriesPrefix, len(categories), func(start, end int) time.Time {
+		var lastMod time.Time
+		for _, category := range categories[start:end] {
+			if category.LastMod.After(lastMod) {
+				lastMod = category.LastMod
+			}
+		}
+		return lastMod
+	})...)
+
+	return s.seoService.GenerateSitemapIndex(entries)
+}
+
+// pageEntries 按MaxSitemapURLs将total条记录分页生成索引项，lastMod返回[start, end)范围内最新的修改时间
+func (s *SitemapService) pageEntries(prefix string, total int, lastMod func(start, end int) time.Time) []seo.SitemapEntry {
+	var entries []seo.SitemapEntry
+	for start := 0; start < total; start += seo.MaxSitemapURLs {
+		end := min(start+seo.MaxSitemapURLs, total)
+		entries = append(entries, seo.SitemapEntry{
+			Loc:     s.seoService.SitemapURL(fmt.Sprintf("%s%d.xml", prefix, start/seo.MaxSitemapURLs+1)),
+			LastMod: seo.FormatLastMod(lastMod(start, end)),
+		})
+	}
+	return entries
+}
+
+// generateArticles 生成第page页的文章Sitemap
+func (s *SitemapService) generateArticles(page int) (string, error) {
+	var articles []models.Article
+	if err := s.publishedArticles().
+		Select("id, slug, updated_at, published_at").
+		Order("id ASC").
+		Offset((page - 1) * seo.MaxSitemapURLs).Limit(seo.MaxSitemapURLs).
+		Find(&articles).Error; err != nil {
+		return "", fmt.Errorf("查询文章失败: %w", err)
+	}
+	if len(articles) == 0 {
+		return "", ErrSitemapNotFound
+	}
+
+	return s.seoService.GenerateArticleSitemap(articles)
+}
+
+// generateImages 生成第page页的图片Sitemap
+func (s *SitemapService) generateImages(page int) (string, error) {
+	var articles []models.Article
+	if err := withImages(s.publishedArticles()).
+		Select("id, slug, content, updated_at, published_at").
+		Order("id ASC").
+		Offset((page - 1) * seo.MaxSitemapURLs).Limit(seo.MaxSitemapURLs).
+		Find(&articles).Error; err != nil {
+		return "", fmt.Errorf("查询文章失败: %w", err)
+	}
+	if len(articles) == 0 {
+		return "", ErrSitemapNotFound
+	}
+
+	return s.seoService.GenerateImageSitemap(articles)
+}
+
+// categoryTimes 查询有已发布文章的分类及其最近的文章修改时间，按ID排序保证分页稳定；limit为0时查询全部
+func (s *SitemapService) categoryTimes(offset, limit int) ([]seo.SitemapCategory, error) {
+	var rows []struct {
+		Name        string
+		UpdatedAt   *time.Time
+		PublishedAt *time.Time
+	}
+	query := s.db.Model(&models.Category{}).
+		Select("categories.name, MAX(articles.updated_at) AS updated_at, MAX(articles.published_at) AS published_at").
+		Joins("JOIN article_categories ON article_categories.category_id = categories.id").
+		Joins("JOIN articles ON articles.id = article_categories.article_id").
+		Where("articles.status = ?", "published").
+		Group("categories.id, categories.name").
+		Order("categories.id ASC")
+	if limit > 0 {
+		query = query.Offset(offset).Limit(limit)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询分类失败: %w", err)
+	}
+
+	categories := make([]seo.SitemapCategory, 0, len(rows))
+	for _, row := range rows {
+		categories = append(categories, seo.SitemapCategory{
+			Name:    row.Name,
+			LastMod: laterTime(row.UpdatedAt, row.PublishedAt),
+		})
+	}
+	return categories, nil
+}
+
+// generateCategories 生成第page页的分类页Sitemap，只包含有已发布文章的分类
+func (s *SitemapService) generateCategories(page int) (string, error) {
+	categories, err := s.categoryTimes((page-1)*seo.MaxSitemapURLs, seo.MaxSitemapURLs)
+	if err != nil {
+		return "", err
+	}
+	if len(categories) == 0 {
+		return "", ErrSitemapNotFound
+	}
+
+	return s.seoService.GenerateCategorySitemap(categories)
+}
+
+// generatePages 生成首页的Sitemap
+func (s *SitemapService) generatePages() (string, error) {
+	// 首页的修改时间为最新文章的修改时间
+	var latest struct {
+		UpdatedAt   *time.Time
+		PublishedAt *time.Time
+	}
+	if err := s.publishedArticles().
+		Select("MAX(updated_at) AS updated_at, MAX(published_at) AS published_at").
+		Scan(&latest).Error; err != nil {
+		return "", fmt.Errorf("查询文章修改时间失败: %w", err)
+	}
+
+	return s.seoService.GeneratePagesSitemap(laterTime(latest.UpdatedAt, latest.PublishedAt))
+}
+
+// laterTime 两个可能为空的时间中较晚的一个
+func laterTime(a, b *time.Time) time.Time {
+	var t time.Time
+	if a != nil {
+		t = *a
+	}
+	if b != nil && b.After(t) {
+		t = *b
+	}
+	return t
+}
@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// SubmissionQueueKey 搜索引擎URL提交队列
+	SubmissionQueueKey = "submission:queue"
+	// SubmissionDelayedKey 延迟提交的任务，按到期时间排序，到期后移回提交队列
+	SubmissionDelayedKey = "submission:delayed"
+
+	// maxSubmissionAttempts 网络错误、限流或服务端错误时的最多尝试次数
+	maxSubmissionAttempts = 3
+	// submissionRetryDelay 重试前的等待时间，按尝试次数递增
+	submissionRetryDelay = time.Minute
+	// submissionTimeout 单次提交的超时时间
+	submissionTimeout = 30 * time.Second
+	// submissionPollInterval 等待提交任务的最长时间，超时后检查到期的延迟任务
+	submissionPollInterval = 10 * time.Second
+)
+
+// submitOutcome 单个搜索引擎的提交结果
+type submitOutcome int
+
+const (
+	submitDone      submitOutcome = iota // 已成功，或失败且不可重试
+	submitRetry                          // 失败，可以重试
+	submitQuotaWait                      // 当日配额已用完，次日配额恢复后重新提交
+)
+
+// URL提交状态
+const (
+	SubmissionStatusSuccess = "success"
+	SubmissionStatusFailed  = "failed"
+	SubmissionStatusSkipped = "skipped"
+)
+
+// SubmissionJob 队列中的URL提交任务
+type SubmissionJob struct {
+	ArticleID uint      `json:"article_id"`
+	URL       string    `json:"url"`
+	Action    string    `json:"action"`
+	Engines   []string  `json:"engines,omitempty"` // 为空时提交到所有已启用的搜索引擎，重试时只包含失败的搜索引擎
+	Attempt   int       `json:"attempt"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SubmissionQuota 搜索引擎当日配额使用情况
+type SubmissionQuota struct {
+	Engine     string `json:"engine"`
+	Enabled    bool   `json:"enabled"`
+	DailyQuota int    `json:"daily_quota"` // 0表示不限制
+	UsedToday  int64  `json:"used_today"`
+	Remaining  *int   `json:"remaining"` // 搜索引擎最近返回的剩余配额
+	Exhausted  bool   `json:"exhausted"`
+}
+
+// SubmissionService 搜索引擎URL提交服务
+type SubmissionService struct {
+	db         *gorm.DB
+	redis      *redis.Client
+	config     *config.Config
+	submitters []seo.URLSubmitter
+}
+
+// NewSubmissionService 创建搜索引擎URL提交服务
+func NewSubmissionService(db *gorm.DB, redis *redis.Client, cfg *config.Config) *SubmissionService {
+	return &SubmissionService{
+		db:     db,
+		redis:  redis,
+		config: cfg,
+		submitters: []seo.URLSubmitter{
+			seo.NewBaiduSubmitter(cfg),
+			seo.NewIndexNowSubmitter(cfg),
+			seo.NewBingSubmitter(cfg),
+		},
+	}
+}
+
+// Enqueue 将URL加入提交队列，没有启用任何搜索引擎时直接忽略
+func (s *SubmissionService) Enqueue(ctx context.Context, articleID uint, url, action string) error {
+	enabled := false
+	for _, submitter := range s.submitters {
+		if submitter.Enabled() && submitter.Supports(action) {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return nil
+	}
+
+	return s.push(ctx, &SubmissionJob{
+		ArticleID: articleID,
+		URL:       url,
+		Action:    action,
+		Attempt:   1,
+		CreatedAt: time.Now(),
+	})
+}
+
+// push 将任务加入队列
+func (s *SubmissionService) push(ctx context.Context, job *SubmissionJob) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("序列化提交任务失败: %w", err)
+	}
+	if err := s.redis.RPush(ctx, SubmissionQueueKey, jobJSON).Err(); err != nil {
+		return fmt.Errorf("添加提交任务到队列失败: %w", err)
+	}
+	return nil
+}
+
+// schedule 将任务加入延迟队列，到期后重新提交。延迟任务保存在redis中，服务重启后不会丢失
+func (s *SubmissionService) schedule(ctx context.Context, job *SubmissionJob, at time.Time) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("序列化提交任务失败: %w", err)
+	}
+	if err := s.redis.ZAdd(ctx, SubmissionDelayedKey, redis.Z{Score: float64(at.Unix()), Member: jobJSON}).Err(); err != nil {
+		return fmt.Errorf("添加延迟提交任务失败: %w", err)
+	}
+	return nil
+}
+
+// promoteDelayed 将到期的延迟任务移回提交队列
+func (s *SubmissionService) promoteDelayed(ctx context.Context) error {
+	jobs, err := s.redis.ZRangeByScore(ctx, SubmissionDelayedKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("查询到期的延迟提交任务失败: %w", err)
+	}
+
+	for _, job := range jobs {
+		// 移除成功的才加入队列，避免多个进程重复提交
+		removed, err := s.redis.ZRem(ctx, SubmissionDelayedKey, job).Result()
+		if err != nil {
+			return fmt.Errorf("移除延迟提交任务失败: %w", err)
+		}
+		if removed == 0 {
+			continue
+		}
+		if err := s.redis.RPush(ctx, SubmissionQueueKey, job).Err(); err != nil {
+			return fmt.Errorf("添加提交任务到队列失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ProcessSubmissions 处理队列中的提交任务，并定期将到期的延迟任务移回队列
+func (s *SubmissionService) ProcessSubmissions(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if err := s.promoteDelayed(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("处理延迟提交任务失败: %v", err)
+			}
+
+			// 从队列中获取任务
+			result, err := s.redis.BLPop(ctx, submissionPollInterval, SubmissionQueueKey).Result()
+			if err != nil {
+				if err != redis.Nil && ctx.Err() == nil {
+					log.Printf("获取提交任务失败: %v", err)
+				}
+				continue
+			}
+
+			var job SubmissionJob
+			if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
+				log.Printf("解析提交任务失败: %v", err)
+				continue
+			}
+
+			s.process(ctx, &job)
+		}
+	}
+}
+
+// process 向各搜索引擎提交URL，可重试的失败稍后重试，配额用完的在次日配额恢复后重新提交
+func (s *SubmissionService) process(ctx context.Context, job *SubmissionJob) {
+	var retry, quotaWait []string
+	for _, submitter := range s.submitters {
+		if !submitter.Enabled() || !submitter.Supports(job.Action) || !jobIncludes(job, submitter.Name()) {
+			continue
+		}
+		switch s.submit(ctx, submitter, job) {
+		case submitRetry:
+			if job.Attempt < maxSubmissionAttempts {
+				retry = append(retry, submitter.Name())
+			}
+		case submitQuotaWait:
+			quotaWait = append(quotaWait, submitter.Name())
+		}
+	}
+
+	if len(retry) > 0 {
+		next := *job
+		next.Engines = retry
+		next.Attempt++
+		if err := s.schedule(ctx, &next, time.Now().Add(time.Duration(job.Attempt)*submissionRetryDelay)); err != nil {
+			log.Printf("URL %s 加入重试队列失败: %v", job.URL, err)
+		}
+	}
+
+	if len(quotaWait) > 0 {
+		// 配额按自然日统计，次日零点恢复，尝试次数不变
+		now := time.Now()
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		next := *job
+		next.Engines = quotaWait
+		if err := s.schedule(ctx, &next, tomorrow); err != nil {
+			log.Printf("URL %s 加入次日提交队列失败: %v", job.URL, err)
+		}
+	}
+}
+
+// jobIncludes 任务是否需要提交到该搜索引擎
+func jobIncludes(job *SubmissionJob, engine string) bool {
+	if len(job.Engines) == 0 {
+		return true
+	}
+	for _, name := range job.Engines {
+		if name == engine {
+			return true
+		}
+	}
+	return false
+}
+
+// submit 提交到单个搜索引擎并记录结果
+func (s *SubmissionService) submit(ctx context.Context, submitter seo.URLSubmitter, job *SubmissionJob) submitOutcome {
+	record := models.URLSubmission{
+		ArticleID: job.ArticleID,
+		Engine:    submitter.Name(),
+		URL:       job.URL,
+		Action:    job.Action,
+		Attempt:   job.Attempt,
+	}
+
+	// 当日配额用完时跳过，次日重新提交
+	quota, err := s.quota(submitter)
+	if err != nil {
+		log.Printf("查询%s提交配额失败: %v", submitter.Name(), err)
+	} else if quota.Exhausted {
+		record.Status = SubmissionStatusSkipped
+		record.Error = "当日提交配额已用完，次日重新提交"
+		s.saveRecord(&record)
+		return submitQuotaWait
+	}
+
+	submitCtx, cancel := context.WithTimeout(ctx, submissionTimeout)
+	result, err := submitter.Submit(submitCtx, []string{job.URL}, job.Action)
+	cancel()
+
+	if result != nil {
+		record.StatusCode = result.StatusCode
+		record.Remaining = result.Remaining
+		record.Response = result.Response
+	}
+	if err != nil {
+		record.Status = SubmissionStatusFailed
+		record.Error = err.Error()
+		log.Printf("URL %s 提交到%s失败(第%d次): %v", job.URL, submitter.Name(), job.Attempt, err)
+	} else {
+		record.Status = SubmissionStatusSuccess
+	}
+	s.saveRecord(&record)
+
+	if err != nil && retryableSubmission(result) {
+		return submitRetry
+	}
+	return submitDone
+}
+
+// retryableSubmission 网络错误、限流和服务端错误可以重试
+func retryableSubmission(result *seo.SubmitResult) bool {
+	return result == nil || result.StatusCode == 429 || result.StatusCode >= 500
+}
+
+// saveRecord 保存提交记录
+func (s *SubmissionService) saveRecord(record *models.URLSubmission) {
+	if err := s.db.Create(record).Error; err != nil {
+		log.Printf("保存URL提交记录失败: %v", err)
+	}
+}
+
+// dailyQuota 配置的每日配额，0表示不限制
+func (s *SubmissionService) dailyQuota(engine string) int {
+	switch engine {
+	case "baidu":
+		return s.config.Indexing.BaiduDailyQuota
+	case "indexnow":
+		return s.config.Indexing.IndexNowDailyQuota
+	case "bing":
+		return s.config.Indexing.BingDailyQuota
+	}
+	return 0
+}
+
+// quota 统计搜索引擎当日的配额使用情况
+func (s *SubmissionService) quota(submitter seo.URLSubmitter) (*SubmissionQuota, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	quota := &SubmissionQuota{
+		Engine:     submitter.Name(),
+		Enabled:    submitter.Enabled(),
+		DailyQuota: s.dailyQuota(submitter.Name()),
+	}
+
+	// 统计当日成功提交的URL数量
+	if err := s.db.Model(&models.URLSubmission{}).
+		Where("engine = ? AND status = ? AND created_at >= ?", submitter.Name(), SubmissionStatusSuccess, today).
+		Count(&quota.UsedToday).Error; err != nil {
+		return nil, fmt.Errorf("统计提交数量失败: %w", err)
+	}
+
+	// 搜索引擎当日最近返回的剩余配额
+	var latest models.URLSubmission
+	err := s.db.Where("engine = ? AND remaining IS NOT NULL AND created_at >= ?", submitter.Name(), today).
+		Order("created_at DESC, id DESC").
+		First(&latest).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("查询剩余配额失败: %w", err)
+	}
+	quota.Remaining = latest.Remaining
+
+	quota.Exhausted = (quota.DailyQuota > 0 && quota.UsedToday >= int64(quota.DailyQuota)) ||
+		(quota.Remaining != nil && *quota.Remaining <= 0)
+	return quota, nil
+}
+
+// GetQuotas 获取各搜索引擎当日的配额使用情况
+func (s *SubmissionService) GetQuotas() ([]SubmissionQuota, error) {
+	quotas := make([]SubmissionQuota, 0, len(s.submitters))
+	for _, submitter := range s.submitters {
+		quota, err := s.quota(submitter)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, *quota)
+	}
+	return quotas, nil
+}
+
+// GetSubmissions 获取URL提交记录
+func (s *SubmissionService) GetSubmissions(page, pageSize int, engine, status string, articleID uint) ([]models.URLSubmission, int64, error) {
+	var submissions []models.URLSubmission
+	var total int64
+
+	query := s.db.Model(&models.URLSubmission{})
+	if engine != "" {
+		query = query.Where("engine = ?", engine)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if articleID > 0 {
+		query = query.Where("article_id = ?", articleID)
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计提交记录数量失败: %w", err)
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(pageSize).
+		Find(&submissions).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询提交记录失败: %w", err)
+	}
+
+	return submissions, total, nil
+}
+
+// IndexNowKey IndexNow密钥文件名和内容，未启用时返回空字符串
+func (s *SubmissionService) IndexNowKey() string {
+	return s.config.Indexing.IndexNowKey
+}
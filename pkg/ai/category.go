@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+)
+
+// maxCategoryBatch 单次让模型分类的最多关键词数
+const maxCategoryBatch = 50
+
+// categorySystemPrompt 关键词分类的系统提示
+const categorySystemPrompt = "你是一名中医养生网站的编辑，擅长把搜索关键词归入合适的栏目。"
+
+// categoryPrompt 关键词分类提示
+const categoryPrompt = `请把以下关键词归入最合适的栏目。
+
+可选栏目:
+%s
+
+关键词:
+%s
+
+要求:
+1. category必须是可选栏目之一，原样返回栏目名称
+2. confidence为0-1之间的数字，表示归类的把握程度
+3. 没有合适栏目的关键词不要返回
+
+只返回一个JSON对象，不要包含任何其他内容，格式如下:
+{"items": [{"keyword": "关键词", "category": "栏目名称", "confidence": 0.8}]}`
+
+// CategoryChoice 模型给出的关键词栏目
+type CategoryChoice struct {
+	Keyword    string  `json:"keyword"`
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+// CategoryClassifier 使用大模型将关键词归入分类，优先使用DeepSeek，失败时使用Ollama
+type CategoryClassifier struct {
+	config         *config.Config
+	deepseekClient *DeepSeekClient
+	ollamaClient   *OllamaClient
+}
+
+// NewCategoryClassifier 创建大模型分类器
+func NewCategoryClassifier(cfg *config.Config) *CategoryClassifier {
+	return &CategoryClassifier{
+		config:         cfg,
+		deepseekClient: NewDeepSeekClient(cfg),
+		ollamaClient:   NewOllamaClient(cfg),
+	}
+}
+
+// Enabled 是否配置了DeepSeek或Ollama
+func (c *CategoryClassifier) Enabled() bool {
+	return c.config.AI.DeepseekAPIKey != "" || c.config.AI.OllamaEndpoint != ""
+}
+
+// Classify 将关键词归入给定的分类，结果以关键词为键；模型返回的分类不在候选中的结果会被丢弃
+func (c *CategoryClassifier) Classify(ctx context.Context, words, categories []string) (map[string]CategoryChoice, error) {
+	results := make(map[string]CategoryChoice, len(words))
+	for start := 0; start < len(words); start += maxCategoryBatch {
+		end := min(start+maxCategoryBatch, len(words))
+		if err := c.classify(ctx, words[start:end], categories, results); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// classify 分类一批关键词
+func (c *CategoryClassifier) classify(ctx context.Context, words, categories []string, results map[string]CategoryChoice) error {
+	prompt := fmt.Sprintf(categoryPrompt, strings.Join(categories, "\n"), strings.Join(words, "\n"))
+	content, err := c.generate(ctx, prompt)
+	if err != nil {
+		return err
+	}
+
+	var output struct {
+		Items []CategoryChoice `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(content)), &output); err != nil {
+		return fmt.Errorf("解析模型输出失败: %w", err)
+	}
+
+	requested := make(map[string]bool, len(words))
+	for _, word := range words {
+		requested[word] = true
+	}
+	allowed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		allowed[category] = true
+	}
+	for _, item := range output.Items {
+		item.Keyword = strings.TrimSpace(item.Keyword)
+		item.Category = strings.TrimSpace(item.Category)
+		if !requested[item.Keyword] || !allowed[item.Category] {
+			continue
+		}
+		item.Confidence = max(0, min(item.Confidence, 1))
+		results[item.Keyword] = item
+	}
+	return nil
+}
+
+// generate 先使用DeepSeek，失败时使用Ollama
+func (c *CategoryClassifier) generate(ctx context.Context, prompt string) (string, error) {
+	timeout := time.Duration(c.config.AI.Timeout) * time.Second
+
+	deepseekCtx, cancel := context.WithTimeout(ctx, timeout)
+	content, err := c.deepseekClient.GenerateJSONWithSystem(deepseekCtx, categorySystemPrompt, prompt)
+	cancel()
+	if err == nil {
+		return content, nil
+	}
+
+	ollamaCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	content, ollamaErr := c.ollamaClient.GenerateJSONWithSystem(ollamaCtx, categorySystemPrompt, prompt)
+	if ollamaErr != nil {
+		return "", fmt.Errorf("DeepSeek: %v; Ollama: %w", err, ollamaErr)
+	}
+	return content, nil
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/NietzscheX/seo-generate/internal/models"
 )
 
+// DefaultSystemPrompt 默认系统提示，数据库中没有配置提示模板时使用
+const DefaultSystemPrompt = "你是一个专业的内容创作者，擅长撰写养生、中医和修行相关的高质量文章。请根据用户提供的关键词和要求，创作SEO友好的内容。"
+
 // DeepSeekClient DeepSeek API客户端
 type DeepSeekClient struct {
 	config     *config.Config
@@ -35,11 +38,17 @@ func NewDeepSeekClient(cfg *config.Config) *DeepSeekClient {
 
 // ChatCompletionRequest 聊天完成请求
 type ChatCompletionRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature"`
-	MaxTokens   int       `json:"max_tokens"`
-	Stream      bool      `json:"stream"`
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	MaxTokens      int             `json:"max_tokens"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat 输出格式，json_object表示要求模型只返回JSON对象
+type ResponseFormat struct {
+	Type string `json:"type"`
 }
 
 // Message 聊天消息
@@ -85,8 +94,23 @@ type Delta struct {
 	Content string `json:"content"`
 }
 
-// GenerateContent 生成内容
+// GenerateContent 使用默认系统提示生成内容
 func (c *DeepSeekClient) GenerateContent(ctx context.Context, prompt string) (string, error) {
+	return c.GenerateContentWithSystem(ctx, DefaultSystemPrompt, prompt)
+}
+
+// GenerateContentWithSystem 使用指定系统提示生成内容
+func (c *DeepSeekClient) GenerateContentWithSystem(ctx context.Context, system, prompt string) (string, error) {
+	return c.generate(ctx, system, prompt, nil)
+}
+
+// GenerateJSONWithSystem 使用JSON输出模式生成内容，提示中需要说明JSON格式
+func (c *DeepSeekClient) GenerateJSONWithSystem(ctx context.Context, system, prompt string) (string, error) {
+	return c.generate(ctx, system, prompt, &ResponseFormat{Type: "json_object"})
+}
+
+// generate 调用聊天完成接口，format为空时返回普通文本
+func (c *DeepSeekClient) generate(ctx context.Context, system, prompt string, format *ResponseFormat) (string, error) {
 	url := fmt.Sprintf("%s/chat/completions", c.config.AI.DeepseekAPIURL)
 
 	// 构建请求体
@@ -95,16 +119,17 @@ func (c *DeepSeekClient) GenerateContent(ctx context.Context, prompt string) (st
 		Messages: []Message{
 			{
 				Role:    "system",
-				Content: "你是一个专业的内容创作者，擅长撰写养生、中医和修行相关的高质量文章。请根据用户提供的关键词和要求，创作SEO友好的内容。",
+				Content: system,
 			},
 			{
 				Role:    "user",
 				Content: prompt,
 			},
 		},
-		Temperature: c.config.AI.Temperature,
-		MaxTokens:   c.config.AI.MaxTokens,
-		Stream:      false,
+		Temperature:    c.config.AI.Temperature,
+		MaxTokens:      c.config.AI.MaxTokens,
+		Stream:         false,
+		ResponseFormat: format,
 	})
 	if err != nil {
 		return "", fmt.Errorf("序列化请求体失败: %w", err)
@@ -174,8 +199,13 @@ func (c *DeepSeekClient) GenerateContent(ctx context.Context, prompt string) (st
 	return response.Choices[0].Message.Content, nil
 }
 
-// StreamGenerateContent 流式生成内容
+// StreamGenerateContent 使用默认系统提示流式生成内容
 func (c *DeepSeekClient) StreamGenerateContent(ctx context.Context, prompt string, contentChan chan<- string, errorChan chan<- error) {
+	c.StreamGenerateContentWithSystem(ctx, DefaultSystemPrompt, prompt, contentChan, errorChan)
+}
+
+// StreamGenerateContentWithSystem 使用指定系统提示流式生成内容
+func (c *DeepSeekClient) StreamGenerateContentWithSystem(ctx context.Context, system, prompt string, contentChan chan<- string, errorChan chan<- error) {
 	url := fmt.Sprintf("%s/chat/completions", c.config.AI.DeepseekAPIURL)
 
 	// 构建请求体
@@ -184,7 +214,7 @@ func (c *DeepSeekClient) StreamGenerateContent(ctx context.Context, prompt strin
 		Messages: []Message{
 			{
 				Role:    "system",
-				Content: "你是一个专业的内容创作者，擅长撰写养生、中医和修行相关的高质量文章。请根据用户提供的关键词和要求，创作SEO友好的内容。",
+				Content: system,
 			},
 			{
 				Role:    "user",
@@ -294,11 +324,9 @@ func (c *DeepSeekClient) StreamGenerateContent(ctx context.Context, prompt strin
 	close(contentChan)
 }
 
-// FilterContent 内容安全过滤
+// FilterContent 清理不可打印字符和无效编码
+// 敏感词和医疗宣传用语的合规检查由 compliance 包完成
 func FilterContent(content string) string {
-	// 这里可以实现内容安全过滤逻辑
-	// 例如：过滤敏感词、违禁内容等
-
 	// 移除不可打印字符
 	var result strings.Builder
 	for _, r := range content {
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+)
+
+// maxEmbeddingBatch 单次请求最多计算向量的文本数
+const maxEmbeddingBatch = 100
+
+// EmbeddingClient Ollama向量接口客户端
+type EmbeddingClient struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// NewEmbeddingClient 创建向量接口客户端
+func NewEmbeddingClient(cfg *config.Config) *EmbeddingClient {
+	return &EmbeddingClient{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.AI.Timeout) * time.Second,
+		},
+	}
+}
+
+// Enabled 是否配置了向量模型
+func (c *EmbeddingClient) Enabled() bool {
+	return c.config.AI.EmbeddingModel != "" && c.config.AI.OllamaEndpoint != ""
+}
+
+// Model 向量模型名称
+func (c *EmbeddingClient) Model() string {
+	return c.config.AI.EmbeddingModel
+}
+
+// Embed 计算文本的向量，结果与输入一一对应
+func (c *EmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += maxEmbeddingBatch {
+		end := min(start+maxEmbeddingBatch, len(texts))
+		batch, err := c.embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// embed 调用Ollama的/embed接口
+func (c *EmbeddingClient) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	url := fmt.Sprintf("%s/embed", c.config.AI.OllamaEndpoint)
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model": c.config.AI.EmbeddingModel,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API错误: %s", string(respBody))
+	}
+
+	var response struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("向量数量与输入不一致: %d/%d", len(response.Embeddings), len(texts))
+	}
+
+	return response.Embeddings, nil
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+)
+
+// maxIntentBatch 单次让模型分类的最多关键词数
+const maxIntentBatch = 50
+
+// intentSystemPrompt 意图分类的系统提示
+const intentSystemPrompt = "你是一名熟悉百度搜索的中文SEO专家，擅长判断用户搜索关键词背后的意图。"
+
+// intentPrompt 意图分类提示
+const intentPrompt = `请判断以下中医养生关键词的搜索意图和内容类型。
+
+搜索意图(intent)只能是以下之一:
+- informational: 了解知识、寻找方法
+- commercial: 比较、挑选产品或服务
+- navigational: 寻找特定网站、机构或入口
+- transactional: 购买、预约、报名、下载
+
+内容类型(domain_intent)只能是以下之一，都不符合时返回空字符串:
+- symptom: 症状、病因和调理
+- recipe: 食谱、药膳、茶饮
+- exercise: 功法、运动、练习
+- theory: 中医理论、概念、经典
+
+关键词:
+%s
+
+只返回一个JSON对象，不要包含任何其他内容，格式如下:
+{"items": [{"keyword": "关键词", "intent": "informational", "domain_intent": "recipe"}]}`
+
+// IntentClassifier 使用大模型判断关键词意图，优先使用DeepSeek，失败时使用Ollama
+type IntentClassifier struct {
+	config         *config.Config
+	deepseekClient *DeepSeekClient
+	ollamaClient   *OllamaClient
+}
+
+// NewIntentClassifier 创建大模型意图分类器
+func NewIntentClassifier(cfg *config.Config) *IntentClassifier {
+	return &IntentClassifier{
+		config:         cfg,
+		deepseekClient: NewDeepSeekClient(cfg),
+		ollamaClient:   NewOllamaClient(cfg),
+	}
+}
+
+// Enabled 是否配置了DeepSeek或Ollama
+func (c *IntentClassifier) Enabled() bool {
+	return c.config.AI.DeepseekAPIKey != "" || c.config.AI.OllamaEndpoint != ""
+}
+
+// Classify 判断关键词的意图，结果以关键词为键，模型没有返回或返回无效取值的关键词不在结果中
+func (c *IntentClassifier) Classify(ctx context.Context, words []string) (map[string]seo.IntentResult, error) {
+	results := make(map[string]seo.IntentResult, len(words))
+	for start := 0; start < len(words); start += maxIntentBatch {
+		end := min(start+maxIntentBatch, len(words))
+		if err := c.classify(ctx, words[start:end], results); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// classify 分类一批关键词
+func (c *IntentClassifier) classify(ctx context.Context, words []string, results map[string]seo.IntentResult) error {
+	prompt := fmt.Sprintf(intentPrompt, strings.Join(words, "\n"))
+	content, err := c.generate(ctx, prompt)
+	if err != nil {
+		return err
+	}
+
+	var output struct {
+		Items []seo.IntentResult `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(content)), &output); err != nil {
+		return fmt.Errorf("解析模型输出失败: %w", err)
+	}
+
+	requested := make(map[string]bool, len(words))
+	for _, word := range words {
+		requested[word] = true
+	}
+	for _, item := range output.Items {
+		item.Keyword = strings.TrimSpace(item.Keyword)
+		item.Intent = strings.ToLower(strings.TrimSpace(item.Intent))
+		item.DomainIntent = strings.ToLower(strings.TrimSpace(item.DomainIntent))
+		if !requested[item.Keyword] || !seo.ValidIntent(item.Intent) || !seo.ValidDomainIntent(item.DomainIntent) {
+			continue
+		}
+		item.Confidence = 1
+		results[item.Keyword] = item
+	}
+	return nil
+}
+
+// generate 先使用DeepSeek，失败时使用Ollama
+func (c *IntentClassifier) generate(ctx context.Context, prompt string) (string, error) {
+	timeout := time.Duration(c.config.AI.Timeout) * time.Second
+
+	deepseekCtx, cancel := context.WithTimeout(ctx, timeout)
+	content, err := c.deepseekClient.GenerateJSONWithSystem(deepseekCtx, intentSystemPrompt, prompt)
+	cancel()
+	if err == nil {
+		return content, nil
+	}
+
+	ollamaCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	content, ollamaErr := c.ollamaClient.GenerateJSONWithSystem(ollamaCtx, intentSystemPrompt, prompt)
+	if ollamaErr != nil {
+		return "", fmt.Errorf("DeepSeek: %v; Ollama: %w", err, ollamaErr)
+	}
+	return content, nil
+}
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/seo"
+)
+
+// maxExpandKeywords 单次让模型扩展的最多关键词数
+const maxExpandKeywords = 100
+
+// keywordExpandSystemPrompt 关键词扩展的系统提示
+const keywordExpandSystemPrompt = "你是一名熟悉百度搜索的中文SEO专家，擅长根据种子词挖掘用户真实会搜索的长尾关键词。"
+
+// keywordExpandPrompt 关键词扩展提示
+const keywordExpandPrompt = `请围绕种子词"%s"扩展%d个用户可能在百度搜索的关键词。
+要求:
+1. 覆盖疑问词（如何、怎么、为什么）、人群、症状、食疗、功效、禁忌等不同角度
+2. 每个关键词2-20个字，符合真实搜索习惯，不要编造品牌、机构或药品名称
+3. 不要重复，不要包含种子词本身
+
+只返回一个JSON对象，不要包含任何其他内容，格式如下:
+{"keywords": ["关键词1", "关键词2"]}`
+
+// KeywordExpander 使用大模型扩展关键词，优先使用DeepSeek，失败时使用Ollama
+type KeywordExpander struct {
+	config         *config.Config
+	deepseekClient *DeepSeekClient
+	ollamaClient   *OllamaClient
+}
+
+// NewKeywordExpander 创建大模型关键词扩展来源
+func NewKeywordExpander(cfg *config.Config) *KeywordExpander {
+	return &KeywordExpander{
+		config:         cfg,
+		deepseekClient: NewDeepSeekClient(cfg),
+		ollamaClient:   NewOllamaClient(cfg),
+	}
+}
+
+// Name 来源名称
+func (e *KeywordExpander) Name() string {
+	return seo.KeywordSourceLLM
+}
+
+// Enabled 是否配置了DeepSeek或Ollama
+func (e *KeywordExpander) Enabled() bool {
+	return e.config.AI.DeepseekAPIKey != "" || e.config.AI.OllamaEndpoint != ""
+}
+
+// Fetch 让模型围绕种子词扩展关键词，模型给出的关键词没有搜索量
+func (e *KeywordExpander) Fetch(ctx context.Context, query seo.KeywordQuery) (*seo.KeywordFetchResult, error) {
+	seed := strings.TrimSpace(query.Seed)
+	if seed == "" {
+		return nil, fmt.Errorf("%w: 关键词扩展需要种子词", seo.ErrInvalidKeywordQuery)
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > maxExpandKeywords {
+		limit = maxExpandKeywords
+	}
+	prompt := fmt.Sprintf(keywordExpandPrompt, seed, limit)
+
+	result := &seo.KeywordFetchResult{Requests: 1}
+	content, err := e.generate(ctx, prompt)
+	if err != nil {
+		return result, err
+	}
+
+	var output struct {
+		Keywords []string `json:"keywords"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(content)), &output); err != nil {
+		return result, fmt.Errorf("解析模型输出失败: %w", err)
+	}
+
+	for _, word := range output.Keywords {
+		word = strings.TrimSpace(word)
+		if word == "" || word == seed {
+			continue
+		}
+		result.Keywords = append(result.Keywords, models.Keyword{
+			Word:   word,
+			Source: seo.KeywordSourceLLM,
+		})
+		if len(result.Keywords) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// generate 先使用DeepSeek，失败时使用Ollama
+func (e *KeywordExpander) generate(ctx context.Context, prompt string) (string, error) {
+	timeout := time.Duration(e.config.AI.Timeout) * time.Second
+
+	deepseekCtx, cancel := context.WithTimeout(ctx, timeout)
+	content, err := e.deepseekClient.GenerateJSONWithSystem(deepseekCtx, keywordExpandSystemPrompt, prompt)
+	cancel()
+	if err == nil {
+		return content, nil
+	}
+
+	ollamaCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	content, ollamaErr := e.ollamaClient.GenerateJSONWithSystem(ollamaCtx, keywordExpandSystemPrompt, prompt)
+	if ollamaErr != nil {
+		return "", fmt.Errorf("DeepSeek: %v; Ollama: %w", err, ollamaErr)
+	}
+	return content, nil
+}
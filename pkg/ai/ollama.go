@@ -36,6 +36,7 @@ type OllamaRequest struct {
 	System      string  `json:"system"`
 	Temperature float64 `json:"temperature"`
 	Stream      bool    `json:"stream"`
+	Format      string  `json:"format,omitempty"` // json表示要求模型只返回JSON
 }
 
 // OllamaResponse Ollama响应
@@ -46,17 +47,33 @@ type OllamaResponse struct {
 	Done      bool   `json:"done"`
 }
 
-// GenerateContent 生成内容
+// GenerateContent 使用默认系统提示生成内容
 func (c *OllamaClient) GenerateContent(ctx context.Context, prompt string) (string, error) {
+	return c.GenerateContentWithSystem(ctx, DefaultSystemPrompt, prompt)
+}
+
+// GenerateContentWithSystem 使用指定系统提示生成内容
+func (c *OllamaClient) GenerateContentWithSystem(ctx context.Context, system, prompt string) (string, error) {
+	return c.generate(ctx, system, prompt, "")
+}
+
+// GenerateJSONWithSystem 使用JSON输出模式生成内容，提示中需要说明JSON格式
+func (c *OllamaClient) GenerateJSONWithSystem(ctx context.Context, system, prompt string) (string, error) {
+	return c.generate(ctx, system, prompt, "json")
+}
+
+// generate 调用生成接口，format为空时返回普通文本
+func (c *OllamaClient) generate(ctx context.Context, system, prompt, format string) (string, error) {
 	url := fmt.Sprintf("%s/generate", c.config.AI.OllamaEndpoint)
 
 	// 构建请求体
 	requestBody, err := json.Marshal(OllamaRequest{
 		Model:       "llama3", // 使用默认模型，可以根据需要修改
 		Prompt:      prompt,
-		System:      "你是一个专业的内容创作者，擅长撰写养生、中医和修行相关的高质量文章。请根据用户提供的关键词和要求，创作SEO友好的内容。",
+		System:      system,
 		Temperature: c.config.AI.Temperature,
 		Stream:      false,
+		Format:      format,
 	})
 	if err != nil {
 		return "", fmt.Errorf("序列化请求体失败: %w", err)
@@ -120,15 +137,20 @@ func (c *OllamaClient) GenerateContent(ctx context.Context, prompt string) (stri
 	return response.Response, nil
 }
 
-// StreamGenerateContent 流式生成内容
+// StreamGenerateContent 使用默认系统提示流式生成内容
 func (c *OllamaClient) StreamGenerateContent(ctx context.Context, prompt string, contentChan chan<- string, errorChan chan<- error) {
+	c.StreamGenerateContentWithSystem(ctx, DefaultSystemPrompt, prompt, contentChan, errorChan)
+}
+
+// StreamGenerateContentWithSystem 使用指定系统提示流式生成内容
+func (c *OllamaClient) StreamGenerateContentWithSystem(ctx context.Context, system, prompt string, contentChan chan<- string, errorChan chan<- error) {
 	url := fmt.Sprintf("%s/generate", c.config.AI.OllamaEndpoint)
 
 	// 构建请求体
 	requestBody, err := json.Marshal(OllamaRequest{
 		Model:       "llama3", // 使用默认模型，可以根据需要修改
 		Prompt:      prompt,
-		System:      "你是一个专业的内容创作者，擅长撰写养生、中医和修行相关的高质量文章。请根据用户提供的关键词和要求，创作SEO友好的内容。",
+		System:      system,
 		Temperature: c.config.AI.Temperature,
 		Stream:      true,
 	})
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// 结构化输出字段长度限制
+const (
+	MaxMetaTitleLength       = 60
+	MaxMetaDescriptionLength = 160
+	MaxSuggestedTags         = 10
+)
+
+// ArticleOutputInstruction 追加在提示后面，说明结构化输出的JSON格式
+const ArticleOutputInstruction = `
+输出格式:
+只返回一个JSON对象，不要包含任何其他内容，字段如下:
+{
+  "title": "文章标题，包含主关键词",
+  "meta_title": "SEO标题，不超过60字",
+  "meta_description": "SEO描述，80-160字，概括文章并包含主关键词",
+  "summary": "文章摘要，100-200字",
+  "faq": [{"question": "读者常问的问题", "answer": "简洁准确的回答"}],
+  "body": "Markdown格式的正文，不包含一级标题，使用二级标题(##)和三级标题(###)组织内容",
+  "tags": ["标签1", "标签2"]
+}
+faq给出3-5个问答，tags给出3-8个标签。
+`
+
+// FAQItem 常见问题
+type FAQItem struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// ArticleOutput 结构化输出的文章
+type ArticleOutput struct {
+	Title           string    `json:"title"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	Summary         string    `json:"summary"`
+	FAQ             []FAQItem `json:"faq"`
+	Body            string    `json:"body"`
+	Tags            []string  `json:"tags"`
+}
+
+// Validate 校验必填字段和长度限制，返回所有问题
+func (o *ArticleOutput) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(o.Title) == "" {
+		problems = append(problems, "title不能为空")
+	}
+	if strings.TrimSpace(o.Body) == "" {
+		problems = append(problems, "body不能为空")
+	}
+	if strings.TrimSpace(o.MetaTitle) == "" {
+		problems = append(problems, "meta_title不能为空")
+	} else if n := utf8.RuneCountInString(o.MetaTitle); n > MaxMetaTitleLength {
+		problems = append(problems, fmt.Sprintf("meta_title长度为%d，不能超过%d字", n, MaxMetaTitleLength))
+	}
+	if strings.TrimSpace(o.MetaDescription) == "" {
+		problems = append(problems, "meta_description不能为空")
+	} else if n := utf8.RuneCountInString(o.MetaDescription); n > MaxMetaDescriptionLength {
+		problems = append(problems, fmt.Sprintf("meta_description长度为%d，不能超过%d字", n, MaxMetaDescriptionLength))
+	}
+	for i, item := range o.FAQ {
+		if strings.TrimSpace(item.Question) == "" || strings.TrimSpace(item.Answer) == "" {
+			problems = append(problems, fmt.Sprintf("faq第%d项的question和answer都不能为空", i+1))
+		}
+	}
+	if len(o.Tags) > MaxSuggestedTags {
+		problems = append(problems, fmt.Sprintf("tags不能超过%d个", MaxSuggestedTags))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "；"))
+	}
+	return nil
+}
+
+// Markdown 返回带一级标题的完整正文
+func (o *ArticleOutput) Markdown() string {
+	body := strings.TrimSpace(o.Body)
+	if strings.HasPrefix(body, "# ") {
+		return body
+	}
+	return "# " + strings.TrimSpace(o.Title) + "\n\n" + body
+}
+
+// ParseArticleOutput 解析并校验模型返回的结构化文章
+func ParseArticleOutput(raw string) (*ArticleOutput, error) {
+	var output ArticleOutput
+	if err := json.Unmarshal([]byte(ExtractJSON(raw)), &output); err != nil {
+		return nil, fmt.Errorf("JSON格式无效: %w", err)
+	}
+
+	if err := output.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// RepairPrompt 构建修复提示，让模型根据校验错误修正上一次的输出
+func RepairPrompt(prompt, raw string, err error) string {
+	return fmt.Sprintf(`%s
+
+你上一次返回的内容没有通过校验:
+%s
+
+上一次返回的内容:
+%s
+
+请修正上述问题，重新返回完整的JSON对象，不要包含任何其他内容。`, prompt, err.Error(), raw)
+}
+
+// ExtractJSON 从模型输出中提取JSON对象，兼容代码块包裹和前后说明文字
+func ExtractJSON(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end <= start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+)
+
+// 默认的相似度阈值
+const (
+	// DefaultLexicalThreshold 分词重合度（Dice系数）达到该值时归为同一聚类
+	DefaultLexicalThreshold = 0.4
+	// DefaultEmbeddingThreshold 向量余弦相似度达到该值时归为同一聚类
+	DefaultEmbeddingThreshold = 0.85
+)
+
+// Item 待聚类的关键词
+type Item struct {
+	ID     uint
+	Weight int       // 搜索量，聚类中权重最高的作为主关键词
+	Tokens []string  // 分词结果
+	Vector []float64 // 向量，为空时只按分词重合度比较
+}
+
+// Options 聚类参数，阈值为0时使用默认值
+type Options struct {
+	LexicalThreshold   float64
+	EmbeddingThreshold float64
+	// Exclusive 互斥词到取值的映射，两个关键词包含的互斥词取值完全不同时（如"冬季"和"夏季"）不归为同一聚类
+	Exclusive map[string]string
+}
+
+// Group 聚类结果，Members为Item的下标，第一个为主关键词
+type Group struct {
+	Members []int
+}
+
+// Build 按权重从高到低依次处理关键词：与已有聚类的主关键词足够相似时加入最相似的聚类，否则成为新聚类的主关键词。
+// 只与主关键词比较，避免"A像B、B像C"把不相关的词串在一起
+func Build(items []Item, opts Options) []Group {
+	if opts.LexicalThreshold <= 0 {
+		opts.LexicalThreshold = DefaultLexicalThreshold
+	}
+	if opts.EmbeddingThreshold <= 0 {
+		opts.EmbeddingThreshold = DefaultEmbeddingThreshold
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if items[order[a]].Weight != items[order[b]].Weight {
+			return items[order[a]].Weight > items[order[b]].Weight
+		}
+		return items[order[a]].ID < items[order[b]].ID
+	})
+
+	sets := make([]map[string]bool, len(items))
+	values := make([]map[string]bool, len(items))
+	for i, item := range items {
+		sets[i] = tokenSet(item.Tokens)
+		values[i] = exclusiveValues(item.Tokens, opts.Exclusive)
+	}
+
+	var groups []Group
+	for _, i := range order {
+		best, bestScore := -1, 0.0
+		for g := range groups {
+			leader := groups[g].Members[0]
+			if conflicts(values[i], values[leader]) {
+				continue
+			}
+			score, ok := similar(sets[i], sets[leader], items[i].Vector, items[leader].Vector, opts)
+			if ok && score > bestScore {
+				best, bestScore = g, score
+			}
+		}
+
+		if best < 0 {
+			groups = append(groups, Group{Members: []int{i}})
+			continue
+		}
+		groups[best].Members = append(groups[best].Members, i)
+	}
+
+	return groups
+}
+
+// similar 两个关键词是否足够相似，返回用于选择聚类的相似度
+func similar(a, b map[string]bool, va, vb []float64, opts Options) (float64, bool) {
+	lexical := dice(a, b)
+	ok := lexical >= opts.LexicalThreshold
+	score := lexical
+
+	if len(va) > 0 && len(va) == len(vb) {
+		cosine := Cosine(va, vb)
+		if cosine >= opts.EmbeddingThreshold {
+			ok = true
+		}
+		score = math.Max(score, cosine)
+	}
+
+	return score, ok
+}
+
+// exclusiveValues 分词中互斥词的取值
+func exclusiveValues(tokens []string, exclusive map[string]string) map[string]bool {
+	values := make(map[string]bool)
+	for _, token := range tokens {
+		if value, ok := exclusive[token]; ok {
+			values[value] = true
+		}
+	}
+	return values
+}
+
+// conflicts 两个关键词都包含互斥词且取值没有交集
+func conflicts(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	for value := range a {
+		if b[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenSet 去重后的分词集合
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		set[token] = true
+	}
+	return set
+}
+
+// Dice 两组分词的重合度：两倍交集大小除以两组大小之和，关键词较短时比Jaccard系数更稳定
+func Dice(a, b []string) float64 {
+	return dice(tokenSet(a), tokenSet(b))
+}
+
+// dice 计算两个集合的Dice系数
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// Cosine 两个向量的余弦相似度，长度不同或为零向量时返回0
+func Cosine(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
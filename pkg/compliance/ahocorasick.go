@@ -0,0 +1,129 @@
+package compliance
+
+// Match 词典匹配结果
+type Match struct {
+	Pattern int // 命中的词条下标
+	Start   int // 起始字节偏移
+	End     int // 结束字节偏移（不含）
+}
+
+// acNode Aho-Corasick自动机节点
+type acNode struct {
+	children map[rune]int
+	fail     int
+	outputs  []int
+}
+
+// Matcher 基于Aho-Corasick算法的多模式匹配器
+type Matcher struct {
+	nodes    []acNode
+	patterns []string
+}
+
+// NewMatcher 根据词条构建匹配器
+func NewMatcher(patterns []string) *Matcher {
+	m := &Matcher{
+		nodes:    []acNode{{children: make(map[rune]int)}},
+		patterns: patterns,
+	}
+
+	// 构建字典树
+	for i, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		cur := 0
+		for _, r := range pattern {
+			next, ok := m.nodes[cur].children[r]
+			if !ok {
+				m.nodes = append(m.nodes, acNode{children: make(map[rune]int)})
+				next = len(m.nodes) - 1
+				m.nodes[cur].children[r] = next
+			}
+			cur = next
+		}
+		m.nodes[cur].outputs = append(m.nodes[cur].outputs, i)
+	}
+
+	// 广度优先构建失败指针
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[cur].children {
+			fail := m.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].children[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].children[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[m.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// FindAll 查找文本中所有命中的词条（允许重叠）
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	cur := 0
+
+	for pos, r := range text {
+		for cur != 0 {
+			if _, ok := m.nodes[cur].children[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if next, ok := m.nodes[cur].children[r]; ok {
+			cur = next
+		}
+
+		end := pos + len(string(r))
+		for _, idx := range m.nodes[cur].outputs {
+			matches = append(matches, Match{
+				Pattern: idx,
+				Start:   end - len(m.patterns[idx]),
+				End:     end,
+			})
+		}
+	}
+
+	return matches
+}
+
+// FindLongest 查找文本中不重叠的命中，优先取最左、最长的词条
+func (m *Matcher) FindLongest(text string) []Match {
+	all := m.FindAll(text)
+
+	// 按起点升序、长度降序选择
+	best := make(map[int]Match)
+	for _, match := range all {
+		if existing, ok := best[match.Start]; !ok || match.End > existing.End {
+			best[match.Start] = match
+		}
+	}
+
+	var result []Match
+	last := 0
+	for pos := 0; pos < len(text); pos++ {
+		match, ok := best[pos]
+		if !ok || pos < last {
+			continue
+		}
+		result = append(result, match)
+		last = match.End
+	}
+
+	return result
+}
@@ -0,0 +1,190 @@
+package compliance
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity 规则严重程度
+type Severity string
+
+const (
+	SeverityBlock   Severity = "block"   // 阻止发布
+	SeverityFlag    Severity = "flag"    // 标记人工复核
+	SeverityRewrite Severity = "rewrite" // 自动改写
+)
+
+// 规则分类
+const (
+	CategoryAdLaw        = "ad_law"       // 广告法禁用词
+	CategoryCureClaim    = "cure_claim"   // 疾病治疗承诺
+	CategoryDosage       = "dosage"       // 处方剂量
+	CategorySuperstition = "superstition" // 封建迷信
+)
+
+// contextRadius 命中上下文截取的字符数
+const contextRadius = 20
+
+// Rule 合规规则
+type Rule struct {
+	ID          uint     `json:"id"`
+	Pattern     string   `json:"pattern"`
+	Category    string   `json:"category"`
+	Severity    Severity `json:"severity"`
+	Replacement string   `json:"replacement"`
+}
+
+// Finding 合规检查命中结果
+type Finding struct {
+	RuleID      uint     `json:"rule_id"`
+	Term        string   `json:"term"`
+	Category    string   `json:"category"`
+	Severity    Severity `json:"severity"`
+	Offset      int      `json:"offset"` // 字符偏移
+	Context     string   `json:"context"`
+	Replacement string   `json:"replacement,omitempty"`
+}
+
+// Engine 合规规则引擎
+type Engine struct {
+	rules   []Rule
+	matcher *Matcher
+}
+
+// NewEngine 根据规则创建规则引擎
+func NewEngine(rules []Rule) *Engine {
+	patterns := make([]string, len(rules))
+	for i, rule := range rules {
+		patterns[i] = rule.Pattern
+	}
+
+	return &Engine{
+		rules:   rules,
+		matcher: NewMatcher(patterns),
+	}
+}
+
+// Check 检查内容，返回所有命中的规则
+func (e *Engine) Check(content string) []Finding {
+	matches := e.matcher.FindLongest(content)
+
+	findings := make([]Finding, 0, len(matches))
+	for _, match := range matches {
+		findings = append(findings, e.newFinding(content, match))
+	}
+
+	return findings
+}
+
+// Apply 检查内容并执行自动改写，返回改写后的内容和所有命中
+func (e *Engine) Apply(content string) (string, []Finding) {
+	matches := e.matcher.FindLongest(content)
+	if len(matches) == 0 {
+		return content, nil
+	}
+
+	var builder strings.Builder
+	findings := make([]Finding, 0, len(matches))
+	last := 0
+
+	for _, match := range matches {
+		finding := e.newFinding(content, match)
+		findings = append(findings, finding)
+
+		if finding.Severity != SeverityRewrite {
+			continue
+		}
+
+		builder.WriteString(content[last:match.Start])
+		builder.WriteString(finding.Replacement)
+		last = match.End
+	}
+	builder.WriteString(content[last:])
+
+	return builder.String(), findings
+}
+
+// HasBlocking 判断命中结果中是否包含阻止发布的规则
+func HasBlocking(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// newFinding 根据匹配结果构建命中记录
+func (e *Engine) newFinding(content string, match Match) Finding {
+	rule := e.rules[match.Pattern]
+
+	// 截取命中位置前后的上下文
+	before := []rune(content[:match.Start])
+	after := []rune(content[match.End:])
+	if len(before) > contextRadius {
+		before = before[len(before)-contextRadius:]
+	}
+	if len(after) > contextRadius {
+		after = after[:contextRadius]
+	}
+
+	return Finding{
+		RuleID:      rule.ID,
+		Term:        content[match.Start:match.End],
+		Category:    rule.Category,
+		Severity:    rule.Severity,
+		Offset:      utf8.RuneCountInString(content[:match.Start]),
+		Context:     string(before) + content[match.Start:match.End] + string(after),
+		Replacement: rule.Replacement,
+	}
+}
+
+// DefaultRules 默认规则词典
+func DefaultRules() []Rule {
+	var rules []Rule
+
+	// 广告法禁用词：绝对化用语自动改写，疗效承诺阻止发布
+	rewrites := []struct {
+		pattern     string
+		replacement string
+	}{
+		{"最有效", "较为常用"},
+		{"最好的", "较好的"},
+		{"最佳", "较好"},
+		{"国家级", ""},
+		{"万能", "多用途"},
+		{"纯天然", "天然来源"},
+		{"无副作用", "副作用相对较少"},
+		{"绝对安全", "相对安全"},
+		{"神药", "常用药材"},
+	}
+	for _, item := range rewrites {
+		rules = append(rules, Rule{Pattern: item.pattern, Category: CategoryAdLaw, Severity: SeverityRewrite, Replacement: item.replacement})
+	}
+	for _, pattern := range []string{"根治", "包治", "包治百病", "药到病除", "永不复发", "一次见效", "立竿见影", "100%有效", "无效退款", "保证治愈"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategoryAdLaw, Severity: SeverityBlock})
+	}
+
+	// 疾病治疗承诺
+	for _, pattern := range []string{"治愈癌症", "治疗癌症", "抗癌", "治好糖尿病", "降血糖药", "代替药物", "停药", "不用吃药", "治愈高血压", "彻底治愈"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategoryCureClaim, Severity: SeverityBlock})
+	}
+	for _, pattern := range []string{"治疗", "疗效", "治愈", "消炎", "杀菌", "预防癌症", "增强免疫力"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategoryCureClaim, Severity: SeverityFlag})
+	}
+
+	// 处方剂量
+	for _, pattern := range []string{"每次服用", "每日服用", "每日三次", "每日两次", "一日三次", "一日两次", "剂量", "处方", "煎服", "水煎服", "克/次", "毫克", "遵医嘱加量"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategoryDosage, Severity: SeverityFlag})
+	}
+
+	// 封建迷信
+	for _, pattern := range []string{"驱邪", "辟邪", "招财", "转运", "开光", "符水", "改命", "化煞", "鬼神", "附体"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategorySuperstition, Severity: SeverityFlag})
+	}
+	for _, pattern := range []string{"符咒治病", "请神治病", "驱鬼治病"} {
+		rules = append(rules, Rule{Pattern: pattern, Category: CategorySuperstition, Severity: SeverityBlock})
+	}
+
+	return rules
+}
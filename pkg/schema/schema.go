@@ -0,0 +1,121 @@
+// Package schema 构建schema.org结构化数据（JSON-LD），输出前校验各类型的必填属性
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Context schema.org的JSON-LD上下文
+const Context = "https://schema.org"
+
+// Node 可以输出为JSON-LD的schema.org类型
+type Node interface {
+	// Validate 校验必填属性
+	Validate() error
+}
+
+// ValidationError 结构化数据校验错误
+type ValidationError struct {
+	Type     string
+	Problems []string
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s结构化数据无效: %s", e.Type, strings.Join(e.Problems, "；"))
+}
+
+// validator 收集校验问题
+type validator struct {
+	typ      string
+	problems []string
+}
+
+// require 属性为空时记录问题
+func (v *validator) require(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.problems = append(v.problems, name+"不能为空")
+	}
+}
+
+// check 条件不满足时记录问题
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// nested 校验嵌套的类型
+func (v *validator) nested(name string, node Node) {
+	if err := node.Validate(); err != nil {
+		v.problems = append(v.problems, name+": "+err.Error())
+	}
+}
+
+// err 没有问题时返回nil
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Type: v.typ, Problems: v.problems}
+}
+
+// Marshal 校验并输出单个类型的JSON-LD
+func Marshal(node Node) ([]byte, error) {
+	if err := node.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("序列化结构化数据失败: %w", err)
+	}
+
+	// 在顶层对象中加入@context
+	return append([]byte(`{"@context":"`+Context+`",`), data[1:]...), nil
+}
+
+// MarshalGraph 校验并将多个类型输出为一个@graph文档
+func MarshalGraph(nodes ...Node) ([]byte, error) {
+	for _, node := range nodes {
+		if err := node.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Context string `json:"@context"`
+		Graph   []Node `json:"@graph"`
+	}{
+		Context: Context,
+		Graph:   nodes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化结构化数据失败: %w", err)
+	}
+	return data, nil
+}
+
+// Indent 格式化JSON-LD，便于调试和比对
+func Indent(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// withType 在类型的JSON输出中加入@type
+func withType(typ string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) <= 2 {
+		return []byte(`{"@type":"` + typ + `"}`), nil
+	}
+	return append([]byte(`{"@type":"`+typ+`",`), data[1:]...), nil
+}
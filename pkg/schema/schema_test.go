@@ -0,0 +1,230 @@
+package schema
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "更新golden文件")
+
+// assertGolden 比对输出与testdata中的golden文件，-update时重新生成
+func assertGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	got, err := Indent(data)
+	if err != nil {
+		t.Fatalf("格式化JSON失败: %v\n%s", err, data)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("写入golden文件失败: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取golden文件失败: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s 输出与golden文件不一致\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
+
+func siteOrganization() *Organization {
+	return &Organization{
+		Name: "养生健康网",
+		URL:  "https://example.com",
+		Logo: &ImageObject{URL: "https://example.com/static/images/logo.png"},
+	}
+}
+
+func TestMarshalGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+	}{
+		{
+			name: "article_person",
+			node: Article{
+				Headline:         "失眠吃什么好？中医推荐的安神食疗",
+				Description:      "从中医角度介绍改善失眠的食物和食疗方。",
+				Image:            []string{"https://example.com/uploads/sleep.jpg"},
+				Author:           Person{Name: "张医生", JobTitle: "中医师"},
+				Publisher:        siteOrganization(),
+				DatePublished:    "2024-03-01T08:00:00+08:00",
+				DateModified:     "2024-03-02T10:30:00+08:00",
+				MainEntityOfPage: &WebPage{ID: "https://example.com/health/shimian-chi-shenme"},
+				Keywords:         "失眠,安神,食疗",
+				ArticleSection:   []string{"养生方法"},
+			},
+		},
+		{
+			name: "article_organization",
+			node: Article{
+				Headline:      "八段锦的养生功效",
+				Author:        siteOrganization(),
+				Publisher:     siteOrganization(),
+				DatePublished: "2024-03-01T08:00:00+08:00",
+			},
+		},
+		{
+			name: "breadcrumb",
+			node: NewBreadcrumbList(
+				ListItem{Name: "首页", Item: "https://example.com"},
+				ListItem{Name: "养生方法", Item: "https://example.com/categories/养生方法"},
+				ListItem{Name: "运动养生", Item: "https://example.com/categories/运动养生"},
+				ListItem{Name: "八段锦的养生功效"},
+			),
+		},
+		{
+			name: "howto",
+			node: HowTo{
+				Name:      "八段锦第一式：两手托天理三焦",
+				TotalTime: "PT5M",
+				Step: []HowToStep{
+					{Text: "两脚平行开立，与肩同宽，两臂自然下垂。"},
+					{Text: "两手从体前缓缓上举，掌心向上托起至头顶。"},
+					{Name: "还原", Text: "两臂从体侧缓缓下落，还原成预备姿势。"},
+				},
+			},
+		},
+		{
+			name: "website",
+			node: WebSite{
+				Name:            "养生健康网",
+				URL:             "https://example.com",
+				Publisher:       siteOrganization(),
+				PotentialAction: NewSearchAction("https://example.com/search?q={search_term_string}"),
+			},
+		},
+		{
+			name: "organization",
+			node: siteOrganization(),
+		},
+		{
+			name: "faqpage",
+			node: FAQPage{
+				MainEntity: []Question{
+					{Name: "失眠可以喝牛奶吗？", AcceptedAnswer: Answer{Text: "可以，睡前喝一杯温牛奶有助于入睡。"}},
+					{Name: "酸枣仁怎么吃？", AcceptedAnswer: Answer{Text: "可以煮粥或泡水，每次10-15克。"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.node)
+			if err != nil {
+				t.Fatalf("Marshal失败: %v", err)
+			}
+			assertGolden(t, tt.name, data)
+		})
+	}
+}
+
+func TestMarshalGraphGolden(t *testing.T) {
+	data, err := MarshalGraph(
+		Article{
+			Headline:      "八段锦的养生功效",
+			Author:        Person{Name: "李教练"},
+			Publisher:     siteOrganization(),
+			DatePublished: "2024-03-01T08:00:00+08:00",
+		},
+		NewBreadcrumbList(
+			ListItem{Name: "首页", Item: "https://example.com"},
+			ListItem{Name: "八段锦的养生功效"},
+		),
+	)
+	if err != nil {
+		t.Fatalf("MarshalGraph失败: %v", err)
+	}
+	assertGolden(t, "graph", data)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		problems int
+	}{
+		{
+			name:     "article missing required",
+			node:     Article{},
+			problems: 4, // headline, datePublished, author, publisher
+		},
+		{
+			name: "article nested author",
+			node: Article{
+				Headline:      "标题",
+				Author:        Person{},
+				Publisher:     siteOrganization(),
+				DatePublished: "2024-03-01",
+			},
+			problems: 1,
+		},
+		{
+			name:     "breadcrumb empty",
+			node:     BreadcrumbList{},
+			problems: 1,
+		},
+		{
+			name: "breadcrumb missing link",
+			node: NewBreadcrumbList(
+				ListItem{Name: "首页"},
+				ListItem{Name: "文章"},
+			),
+			problems: 1,
+		},
+		{
+			name: "breadcrumb wrong position",
+			node: BreadcrumbList{ItemListElement: []ListItem{
+				{Position: 2, Name: "首页", Item: "https://example.com"},
+			}},
+			problems: 1,
+		},
+		{
+			name:     "howto one step",
+			node:     HowTo{Name: "练习", Step: []HowToStep{{Text: "站立"}}},
+			problems: 1,
+		},
+		{
+			name: "website bad search target",
+			node: WebSite{
+				Name:            "养生健康网",
+				URL:             "https://example.com",
+				PotentialAction: NewSearchAction("https://example.com/search"),
+			},
+			problems: 1,
+		},
+		{
+			name:     "faqpage empty answer",
+			node:     FAQPage{MainEntity: []Question{{Name: "问题"}}},
+			problems: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.node.Validate()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("期望ValidationError，实际为 %v", err)
+			}
+			if len(verr.Problems) != tt.problems {
+				t.Errorf("期望%d个问题，实际为%d个: %v", tt.problems, len(verr.Problems), verr.Problems)
+			}
+
+			if _, err := Marshal(tt.node); err == nil {
+				t.Error("校验失败时Marshal应返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHeadlineLength 搜索引擎对文章标题的长度限制
+const maxHeadlineLength = 110
+
+// Author 文章作者，可以是Organization或Person
+type Author interface {
+	Node
+	isAuthor()
+}
+
+// ImageObject 图片
+type ImageObject struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Validate 校验必填属性
+func (i ImageObject) Validate() error {
+	v := validator{typ: "ImageObject"}
+	v.require("url", i.URL)
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (i ImageObject) MarshalJSON() ([]byte, error) {
+	type alias ImageObject
+	return withType("ImageObject", alias(i))
+}
+
+// Organization 组织
+type Organization struct {
+	Name   string       `json:"name"`
+	URL    string       `json:"url,omitempty"`
+	Logo   *ImageObject `json:"logo,omitempty"`
+	SameAs []string     `json:"sameAs,omitempty"`
+}
+
+func (Organization) isAuthor() {}
+
+// Validate 校验必填属性
+func (o Organization) Validate() error {
+	v := validator{typ: "Organization"}
+	v.require("name", o.Name)
+	if o.Logo != nil {
+		v.nested("logo", o.Logo)
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (o Organization) MarshalJSON() ([]byte, error) {
+	type alias Organization
+	return withType("Organization", alias(o))
+}
+
+// Person 个人
+type Person struct {
+	Name     string `json:"name"`
+	URL      string `json:"url,omitempty"`
+	JobTitle string `json:"jobTitle,omitempty"`
+}
+
+func (Person) isAuthor() {}
+
+// Validate 校验必填属性
+func (p Person) Validate() error {
+	v := validator{typ: "Person"}
+	v.require("name", p.Name)
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (p Person) MarshalJSON() ([]byte, error) {
+	type alias Person
+	return withType("Person", alias(p))
+}
+
+// WebPage 网页
+type WebPage struct {
+	ID string `json:"@id"`
+}
+
+// MarshalJSON 输出JSON-LD
+func (p WebPage) MarshalJSON() ([]byte, error) {
+	type alias WebPage
+	return withType("WebPage", alias(p))
+}
+
+// Article 文章
+type Article struct {
+	Headline         string        `json:"headline"`
+	Description      string        `json:"description,omitempty"`
+	Image            []string      `json:"image,omitempty"`
+	Author           Author        `json:"author"`
+	Publisher        *Organization `json:"publisher"`
+	DatePublished    string        `json:"datePublished"`
+	DateModified     string        `json:"dateModified,omitempty"`
+	MainEntityOfPage *WebPage      `json:"mainEntityOfPage,omitempty"`
+	Keywords         string        `json:"keywords,omitempty"`
+	ArticleSection   []string      `json:"articleSection,omitempty"`
+}
+
+// Validate 校验必填属性
+func (a Article) Validate() error {
+	v := validator{typ: "Article"}
+	v.require("headline", a.Headline)
+	v.check(utf8.RuneCountInString(a.Headline) <= maxHeadlineLength, "headline不能超过%d个字符", maxHeadlineLength)
+	v.require("datePublished", a.DatePublished)
+	if a.Author == nil {
+		v.problems = append(v.problems, "author不能为空")
+	} else {
+		v.nested("author", a.Author)
+	}
+	if a.Publisher == nil {
+		v.problems = append(v.problems, "publisher不能为空")
+	} else {
+		v.nested("publisher", a.Publisher)
+	}
+	for _, image := range a.Image {
+		v.require("image", image)
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (a Article) MarshalJSON() ([]byte, error) {
+	type alias Article
+	return withType("Article", alias(a))
+}
+
+// ListItem 面包屑中的一项
+type ListItem struct {
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item,omitempty"`
+}
+
+// MarshalJSON 输出JSON-LD
+func (i ListItem) MarshalJSON() ([]byte, error) {
+	type alias ListItem
+	return withType("ListItem", alias(i))
+}
+
+// BreadcrumbList 面包屑导航
+type BreadcrumbList struct {
+	ItemListElement []ListItem `json:"itemListElement"`
+}
+
+// NewBreadcrumbList 按顺序创建面包屑，最后一项表示当前页面，可以不带链接
+func NewBreadcrumbList(items ...ListItem) *BreadcrumbList {
+	list := &BreadcrumbList{ItemListElement: make([]ListItem, 0, len(items))}
+	for i, item := range items {
+		item.Position = i + 1
+		list.ItemListElement = append(list.ItemListElement, item)
+	}
+	return list
+}
+
+// Validate 校验必填属性
+func (b BreadcrumbList) Validate() error {
+	v := validator{typ: "BreadcrumbList"}
+	v.check(len(b.ItemListElement) > 0, "itemListElement不能为空")
+	for i, item := range b.ItemListElement {
+		v.check(item.Position == i+1, "第%d项的position应为%d", i+1, i+1)
+		v.check(strings.TrimSpace(item.Name) != "", "第%d项的name不能为空", i+1)
+		if i < len(b.ItemListElement)-1 {
+			v.check(strings.TrimSpace(item.Item) != "", "第%d项的item不能为空", i+1)
+		}
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (b BreadcrumbList) MarshalJSON() ([]byte, error) {
+	type alias BreadcrumbList
+	return withType("BreadcrumbList", alias(b))
+}
+
+// HowToStep 操作步骤
+type HowToStep struct {
+	Name string `json:"name,omitempty"`
+	Text string `json:"text"`
+	URL  string `json:"url,omitempty"`
+}
+
+// MarshalJSON 输出JSON-LD
+func (s HowToStep) MarshalJSON() ([]byte, error) {
+	type alias HowToStep
+	return withType("HowToStep", alias(s))
+}
+
+// HowTo 操作指南
+type HowTo struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Image       []string    `json:"image,omitempty"`
+	TotalTime   string      `json:"totalTime,omitempty"` // ISO 8601时长，例如PT15M
+	Step        []HowToStep `json:"step"`
+}
+
+// Validate 校验必填属性
+func (h HowTo) Validate() error {
+	v := validator{typ: "HowTo"}
+	v.require("name", h.Name)
+	v.check(len(h.Step) >= 2, "step至少需要两步")
+	for i, step := range h.Step {
+		v.check(strings.TrimSpace(step.Text) != "", "第%d步的text不能为空", i+1)
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (h HowTo) MarshalJSON() ([]byte, error) {
+	type alias HowTo
+	return withType("HowTo", alias(h))
+}
+
+// searchTermPlaceholder 站内搜索地址中的搜索词占位符
+const searchTermPlaceholder = "{search_term_string}"
+
+// SearchAction 站内搜索
+type SearchAction struct {
+	Target     string `json:"target"`      // 包含{search_term_string}的搜索地址
+	QueryInput string `json:"query-input"` // 固定为required name=search_term_string
+}
+
+// NewSearchAction 创建站内搜索，target中使用{search_term_string}表示搜索词
+func NewSearchAction(target string) *SearchAction {
+	return &SearchAction{
+		Target:     target,
+		QueryInput: "required name=search_term_string",
+	}
+}
+
+// Validate 校验必填属性
+func (s SearchAction) Validate() error {
+	v := validator{typ: "SearchAction"}
+	v.require("target", s.Target)
+	v.check(strings.Contains(s.Target, searchTermPlaceholder), "target必须包含%s", searchTermPlaceholder)
+	v.require("query-input", s.QueryInput)
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (s SearchAction) MarshalJSON() ([]byte, error) {
+	type alias SearchAction
+	return withType("SearchAction", alias(s))
+}
+
+// WebSite 网站
+type WebSite struct {
+	Name            string        `json:"name"`
+	URL             string        `json:"url"`
+	Publisher       *Organization `json:"publisher,omitempty"`
+	PotentialAction *SearchAction `json:"potentialAction,omitempty"`
+}
+
+// Validate 校验必填属性
+func (w WebSite) Validate() error {
+	v := validator{typ: "WebSite"}
+	v.require("name", w.Name)
+	v.require("url", w.URL)
+	if w.Publisher != nil {
+		v.nested("publisher", w.Publisher)
+	}
+	if w.PotentialAction != nil {
+		v.nested("potentialAction", w.PotentialAction)
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (w WebSite) MarshalJSON() ([]byte, error) {
+	type alias WebSite
+	return withType("WebSite", alias(w))
+}
+
+// Answer 问题的回答
+type Answer struct {
+	Text string `json:"text"`
+}
+
+// MarshalJSON 输出JSON-LD
+func (a Answer) MarshalJSON() ([]byte, error) {
+	type alias Answer
+	return withType("Answer", alias(a))
+}
+
+// Question 常见问题
+type Question struct {
+	Name           string `json:"name"`
+	AcceptedAnswer Answer `json:"acceptedAnswer"`
+}
+
+// MarshalJSON 输出JSON-LD
+func (q Question) MarshalJSON() ([]byte, error) {
+	type alias Question
+	return withType("Question", alias(q))
+}
+
+// FAQPage 常见问题页
+type FAQPage struct {
+	MainEntity []Question `json:"mainEntity"`
+}
+
+// Validate 校验必填属性
+func (f FAQPage) Validate() error {
+	v := validator{typ: "FAQPage"}
+	v.check(len(f.MainEntity) > 0, "mainEntity不能为空")
+	for i, q := range f.MainEntity {
+		v.check(strings.TrimSpace(q.Name) != "", "第%d个问题的name不能为空", i+1)
+		v.check(strings.TrimSpace(q.AcceptedAnswer.Text) != "", "第%d个问题的回答不能为空", i+1)
+	}
+	return v.err()
+}
+
+// MarshalJSON 输出JSON-LD
+func (f FAQPage) MarshalJSON() ([]byte, error) {
+	type alias FAQPage
+	return withType("FAQPage", alias(f))
+}
+
+// 确保类型实现了json.Marshaler
+var (
+	_ json.Marshaler = Article{}
+	_ json.Marshaler = Organization{}
+	_ json.Marshaler = Person{}
+	_ Author         = Organization{}
+	_ Author         = Person{}
+)
@@ -0,0 +1,151 @@
+package segment
+
+import (
+	"math"
+	"sort"
+)
+
+// BM25参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Document 已分词的文档
+type Document struct {
+	ID     uint
+	Tokens []string
+}
+
+// Result 检索结果
+type Result struct {
+	ID    uint
+	Score float64
+}
+
+// Index BM25倒排索引
+type Index struct {
+	freqs   map[uint]map[string]int
+	lengths map[uint]int
+	df      map[string]int
+	avgLen  float64
+}
+
+// NewIndex 创建BM25索引
+func NewIndex(docs []Document) *Index {
+	index := &Index{
+		freqs:   make(map[uint]map[string]int, len(docs)),
+		lengths: make(map[uint]int, len(docs)),
+		df:      make(map[string]int),
+	}
+
+	total := 0
+	for _, doc := range docs {
+		freq := termFreqs(doc.Tokens)
+		index.freqs[doc.ID] = freq
+		index.lengths[doc.ID] = len(doc.Tokens)
+		total += len(doc.Tokens)
+		for term := range freq {
+			index.df[term]++
+		}
+	}
+	if len(docs) > 0 {
+		index.avgLen = float64(total) / float64(len(docs))
+	}
+
+	return index
+}
+
+// termFreqs 统计词频
+func termFreqs(tokens []string) map[string]int {
+	freq := make(map[string]int)
+	for _, token := range tokens {
+		freq[token]++
+	}
+	return freq
+}
+
+// Len 索引中的文档数
+func (idx *Index) Len() int {
+	return len(idx.freqs)
+}
+
+// Contains 文档是否在索引中
+func (idx *Index) Contains(id uint) bool {
+	_, ok := idx.freqs[id]
+	return ok
+}
+
+// IDF 词语的逆文档频率
+func (idx *Index) IDF(term string) float64 {
+	n := float64(len(idx.freqs))
+	df := float64(idx.df[term])
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// Score 计算查询与文档的BM25得分
+func (idx *Index) Score(query []string, id uint) float64 {
+	freq, ok := idx.freqs[id]
+	if !ok || idx.avgLen == 0 {
+		return 0
+	}
+
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.lengths[id])/idx.avgLen)
+	score := 0.0
+	for _, term := range query {
+		tf := float64(freq[term])
+		if tf == 0 {
+			continue
+		}
+		score += idx.IDF(term) * tf * (bm25K1 + 1) / (tf + norm)
+	}
+	return score
+}
+
+// Search 返回与查询相关的文档，按得分从高到低排列
+func (idx *Index) Search(query []string) []Result {
+	var results []Result
+	for id := range idx.freqs {
+		if score := idx.Score(query, id); score > 0 {
+			results = append(results, Result{ID: id, Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
+
+// KeyTerms 按TF-IDF选出文本中最有代表性的n个词，用作相似文档查询
+func (idx *Index) KeyTerms(tokens []string, n int) []string {
+	freq := termFreqs(tokens)
+
+	type weighted struct {
+		term   string
+		weight float64
+	}
+	terms := make([]weighted, 0, len(freq))
+	for term, tf := range freq {
+		terms = append(terms, weighted{term: term, weight: float64(tf) * idx.IDF(term)})
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].weight != terms[j].weight {
+			return terms[i].weight > terms[j].weight
+		}
+		return terms[i].term < terms[j].term
+	})
+
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	result := make([]string, len(terms))
+	for i, t := range terms {
+		result[i] = t.term
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package segment
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight 转义HTML并用<mark>标记文本中出现的查询词；maxRunes大于0时截取包含第一个匹配的片段
+func Highlight(text string, terms []string, maxRunes int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 标记所有匹配的字符
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		termRunes := []rune(strings.ToLower(term))
+		if len(termRunes) == 0 {
+			continue
+		}
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(termRunes)], termRunes) {
+				for j := i; j < i+len(termRunes); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		first := 0
+		for i, m := range marked {
+			if m {
+				first = i
+				break
+			}
+		}
+
+		// 匹配位置前保留四分之一的上下文
+		start = max(0, first-maxRunes/4)
+		end = min(len(runes), start+maxRunes)
+		start = max(0, end-maxRunes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+
+		part := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			b.WriteString("<mark>" + part + "</mark>")
+		} else {
+			b.WriteString(part)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
+
+// equalRunes 两个字符序列是否相同
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
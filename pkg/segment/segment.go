@@ -0,0 +1,151 @@
+package segment
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxWordLength 词典中词语的最大字数
+const maxWordLength = 8
+
+// stopChars 常见的单字虚词，在连续汉字中作为分隔
+var stopChars = map[rune]bool{
+	'的': true, '了': true, '和': true, '是': true, '在': true, '也': true, '就': true, '都': true,
+	'而': true, '及': true, '与': true, '或': true, '等': true, '着': true, '吗': true, '呢': true,
+}
+
+// stopWords 不参与相似度计算的常见词
+var stopWords = map[string]bool{
+	"我们": true, "你们": true, "他们": true, "可以": true, "一个": true, "什么": true, "如何": true,
+	"怎么": true, "因为": true, "所以": true, "但是": true, "如果": true, "以及": true, "进行": true,
+	"the": true, "a": true, "an": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"is": true, "for": true, "on": true, "with": true,
+}
+
+// defaultSegmenter 不带词典的分词器，建立和查询全文索引时使用，保证两边切分一致
+var defaultSegmenter = NewSegmenter(nil)
+
+// Segment 使用不带词典的分词器切分文本
+func Segment(text string) []string {
+	return defaultSegmenter.Segment(text)
+}
+
+// SearchText 切分后以空格连接，用于生成PostgreSQL的tsvector和tsquery
+func SearchText(text string) string {
+	return strings.Join(defaultSegmenter.Segment(text), " ")
+}
+
+// Segmenter 中文分词器：按词典正向最大匹配，词典中没有的汉字按相邻两字切分
+type Segmenter struct {
+	dict map[string]bool
+}
+
+// NewSegmenter 创建分词器，words为自定义词典（如关键词列表）
+func NewSegmenter(words []string) *Segmenter {
+	dict := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if n := utf8.RuneCountInString(word); n >= 2 && n <= maxWordLength {
+			dict[word] = true
+		}
+	}
+	return &Segmenter{dict: dict}
+}
+
+// Segment 将文本切分为词语，英文和数字按单词切分并转为小写，去掉标点和停用词
+func (s *Segmenter) Segment(text string) []string {
+	var tokens []string
+	var han []rune
+	var word []rune
+
+	flushHan := func() {
+		if len(han) > 0 {
+			tokens = append(tokens, s.segmentHan(han)...)
+			han = han[:0]
+		}
+	}
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case stopChars[r]:
+			flushHan()
+			flushWord()
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushHan()
+			flushWord()
+		}
+	}
+	flushHan()
+	flushWord()
+
+	result := tokens[:0]
+	for _, token := range tokens {
+		if !stopWords[token] {
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// segmentHan 切分连续的汉字：优先匹配词典中最长的词，未匹配的部分按相邻两字切分
+func (s *Segmenter) segmentHan(runes []rune) []string {
+	var tokens []string
+	var unmatched []rune
+
+	flush := func() {
+		tokens = append(tokens, bigrams(unmatched)...)
+		unmatched = unmatched[:0]
+	}
+
+	for i := 0; i < len(runes); {
+		matched := 0
+		for n := min(maxWordLength, len(runes)-i); n >= 2; n-- {
+			if s.dict[string(runes[i:i+n])] {
+				matched = n
+				break
+			}
+		}
+
+		if matched == 0 {
+			unmatched = append(unmatched, runes[i])
+			i++
+			continue
+		}
+
+		flush()
+		tokens = append(tokens, string(runes[i:i+matched]))
+		i += matched
+	}
+	flush()
+
+	return tokens
+}
+
+// bigrams 相邻两字切分，只有一个字时保留单字
+func bigrams(runes []rune) []string {
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) == 1 {
+		return []string{string(runes)}
+	}
+
+	tokens := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		tokens = append(tokens, string(runes[i:i+2]))
+	}
+	return tokens
+}
@@ -2,12 +2,15 @@ package seo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/NietzscheX/seo-generate/config"
 	"github.com/NietzscheX/seo-generate/internal/models"
@@ -17,6 +20,7 @@ import (
 type API5118Client struct {
 	config     *config.Config
 	httpClient *http.Client
+	logger     func(*models.APILog)
 }
 
 // NewAPI5118Client 创建5118 API客户端
@@ -29,37 +33,32 @@ func NewAPI5118Client(cfg *config.Config) *API5118Client {
 	}
 }
 
-// KeywordSearchResponse 关键词搜索响应
-type KeywordSearchResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    struct {
-		Total int `json:"total"`
-		Items []struct {
-			Keyword      string `json:"keyword"`
-			SearchVolume int    `json:"search_volume"`
-		} `json:"items"`
-	} `json:"data"`
+// SetLogger 设置API调用日志的保存方法，每次调用（包括失败的调用）都会记录一条日志
+func (c *API5118Client) SetLogger(logger func(*models.APILog)) {
+	c.logger = logger
 }
 
-// SearchKeywords 搜索关键词
-func (c *API5118Client) SearchKeywords(query string, page, pageSize int) ([]models.Keyword, int, error) {
-	url := fmt.Sprintf("%s/keyword/search", c.config.API5118.BaseURL)
+// api5118Response 5118 API响应
+type api5118Response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// post 调用5118 API并将data字段解析到out
+func (c *API5118Client) post(endpoint string, params map[string]interface{}, out interface{}) error {
+	url := fmt.Sprintf("%s%s", c.config.API5118.BaseURL, endpoint)
 
 	// 构建请求体
-	requestBody, err := json.Marshal(map[string]interface{}{
-		"query":     query,
-		"page":      page,
-		"page_size": pageSize,
-	})
+	requestBody, err := json.Marshal(params)
 	if err != nil {
-		return nil, 0, fmt.Errorf("序列化请求体失败: %w", err)
+		return fmt.Errorf("序列化请求体失败: %w", err)
 	}
 
 	// 创建请求
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
 	if err != nil {
-		return nil, 0, fmt.Errorf("创建请求失败: %w", err)
+		return fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	// 设置请求头
@@ -73,18 +72,22 @@ func (c *API5118Client) SearchKeywords(query string, page, pageSize int) ([]mode
 
 	// 记录API调用日志
 	apiLog := models.APILog{
-		APIName:   "5118",
+		APIName:   KeywordSource5118,
 		Endpoint:  url,
 		Request:   string(requestBody),
 		Duration:  int(duration),
 		CreatedAt: time.Now(),
 	}
+	defer func() {
+		if c.logger != nil {
+			c.logger(&apiLog)
+		}
+	}()
 
 	if err != nil {
 		apiLog.Status = 0
 		apiLog.Response = err.Error()
-		// 这里应该保存日志到数据库，但为简化示例，暂不实现
-		return nil, 0, fmt.Errorf("请求失败: %w", err)
+		return fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -93,47 +96,97 @@ func (c *API5118Client) SearchKeywords(query string, page, pageSize int) ([]mode
 	if err != nil {
 		apiLog.Status = resp.StatusCode
 		apiLog.Response = err.Error()
-		// 保存日志
-		return nil, 0, fmt.Errorf("读取响应体失败: %w", err)
+		return fmt.Errorf("读取响应体失败: %w", err)
 	}
 
 	apiLog.Status = resp.StatusCode
 	apiLog.Response = string(respBody)
-	// 保存日志
 
 	// 解析响应
-	var response KeywordSearchResponse
+	var response api5118Response
 	if err := json.Unmarshal(respBody, &response); err != nil {
-		return nil, 0, fmt.Errorf("解析响应失败: %w", err)
+		return fmt.Errorf("解析响应失败: %w", err)
 	}
 
 	// 检查响应状态
 	if response.Code != 200 {
-		return nil, 0, fmt.Errorf("API错误: %s", response.Message)
+		return fmt.Errorf("API错误: %s", response.Message)
+	}
+
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("解析响应数据失败: %w", err)
+	}
+	return nil
+}
+
+// SearchKeywords 搜索关键词
+func (c *API5118Client) SearchKeywords(query string, page, pageSize int) ([]models.Keyword, int, error) {
+	var data struct {
+		Total int `json:"total"`
+		Items []struct {
+			Keyword      string `json:"keyword"`
+			SearchVolume int    `json:"search_volume"`
+		} `json:"items"`
+	}
+	if err := c.post("/keyword/search", map[string]interface{}{
+		"query":     query,
+		"page":      page,
+		"page_size": pageSize,
+	}, &data); err != nil {
+		return nil, 0, err
 	}
 
 	// 转换为关键词模型
-	keywords := make([]models.Keyword, 0, len(response.Data.Items))
-	for _, item := range response.Data.Items {
+	keywords := make([]models.Keyword, 0, len(data.Items))
+	for _, item := range data.Items {
 		keywords = append(keywords, models.Keyword{
 			Word:         item.Keyword,
 			SearchVolume: item.SearchVolume,
-			Source:       "5118",
-			Status:       "active",
+			Source:       KeywordSource5118,
 		})
 	}
 
-	return keywords, response.Data.Total, nil
+	return keywords, data.Total, nil
 }
 
 // GetKeywordsByCategory 按分类获取关键词
 func (c *API5118Client) GetKeywordsByCategory(category string, limit int) ([]models.Keyword, error) {
+	keywords, _, err := c.fetchPages(context.Background(), category, limit, 0)
+	return keywords, err
+}
+
+// Name 来源名称
+func (c *API5118Client) Name() string {
+	return KeywordSource5118
+}
+
+// Enabled 是否已配置API密钥
+func (c *API5118Client) Enabled() bool {
+	return c.config.API5118.Key != ""
+}
+
+// Fetch 以种子词搜索关键词，每页计为一次请求
+func (c *API5118Client) Fetch(ctx context.Context, query KeywordQuery) (*KeywordFetchResult, error) {
+	if strings.TrimSpace(query.Seed) == "" {
+		return nil, fmt.Errorf("%w: 5118需要种子词", ErrInvalidKeywordQuery)
+	}
+
+	keywords, requests, err := c.fetchPages(ctx, query.Seed, query.Limit, query.MaxRequests)
+	return &KeywordFetchResult{Keywords: keywords, Requests: requests}, err
+}
+
+// fetchPages 分页搜索关键词，maxPages为0时不限制页数，返回关键词和请求的页数
+func (c *API5118Client) fetchPages(ctx context.Context, query string, limit, maxPages int) ([]models.Keyword, int, error) {
 	// 计算需要请求的页数
 	pageSize := 100 // 5118 API每页最大100条
 	totalPages := int(math.Ceil(float64(limit) / float64(pageSize)))
+	if maxPages > 0 && totalPages > maxPages {
+		totalPages = maxPages
+	}
 
 	allKeywords := make([]models.Keyword, 0, limit)
 	var total int
+	requests := 0
 
 	// 分页请求关键词
 	for page := 1; page <= totalPages; page++ {
@@ -147,9 +200,10 @@ func (c *API5118Client) GetKeywordsByCategory(category string, limit int) ([]mod
 		}
 
 		// 搜索关键词
-		keywords, newTotal, err := c.SearchKeywords(category, page, currentPageSize)
+		requests++
+		keywords, newTotal, err := c.SearchKeywords(query, page, currentPageSize)
 		if err != nil {
-			return nil, err
+			return allKeywords, requests, err
 		}
 
 		total = newTotal
@@ -161,7 +215,11 @@ func (c *API5118Client) GetKeywordsByCategory(category string, limit int) ([]mod
 		}
 
 		// 避免请求过快
-		time.Sleep(time.Second)
+		select {
+		case <-ctx.Done():
+			return allKeywords, requests, ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
 
 	// 限制返回数量
@@ -169,7 +227,25 @@ func (c *API5118Client) GetKeywordsByCategory(category string, limit int) ([]mod
 		allKeywords = allKeywords[:limit]
 	}
 
-	return allKeywords, nil
+	return allKeywords, requests, nil
+}
+
+// maxKeywordLength 关键词最多的字数
+const maxKeywordLength = 50
+
+// NormalizeSpaces 去掉关键词首尾的空白，中间连续的空白合并为一个空格
+func NormalizeSpaces(word string) string {
+	return strings.Join(strings.Fields(word), " ")
+}
+
+// ValidKeyword 关键词是否可用：按字数而不是字节计算长度，最多50个字；
+// 单个汉字（如"痣"）可以作为关键词，单个字母或数字不可以
+func ValidKeyword(word string) bool {
+	runes := []rune(word)
+	if len(runes) == 0 || len(runes) > maxKeywordLength {
+		return false
+	}
+	return len(runes) >= 2 || unicode.Is(unicode.Han, runes[0])
 }
 
 // CleanKeywords 清洗关键词
@@ -178,11 +254,12 @@ func CleanKeywords(keywords []models.Keyword) []models.Keyword {
 	uniqueMap := make(map[string]models.Keyword)
 
 	for _, kw := range keywords {
-		// 去除无效字符（这里可以根据需要添加更多过滤规则）
-		word := kw.Word
+		// 去掉多余的空白
+		word := NormalizeSpaces(kw.Word)
+		kw.Word = word
 
 		// 如果关键词不为空且长度合适，则保留
-		if word != "" && len(word) >= 2 && len(word) <= 50 {
+		if ValidKeyword(word) {
 			// 如果已存在相同关键词，保留搜索量更高的
 			if existing, ok := uniqueMap[word]; ok {
 				if kw.SearchVolume > existing.SearchVolume {
@@ -0,0 +1,201 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+// KeywordSource5118LongTail 5118长尾词挖掘来源，与5118共用配额
+const KeywordSource5118LongTail = "5118_longtail"
+
+// MaxMetricsBatch 5118关键词指标接口单次最多查询的关键词数
+const MaxMetricsBatch = 50
+
+// 机会得分的参数
+const (
+	// opportunityVolumeCap 搜索量达到该值时搜索量得分为满分
+	opportunityVolumeCap = 100000
+	// opportunityMobileBonus 移动端搜索占比带来的最高加成，养生类内容主要在移动端阅读
+	opportunityMobileBonus = 0.1
+)
+
+// Keyword5118Metrics 5118返回的关键词指标
+type Keyword5118Metrics struct {
+	Keyword      string  `json:"keyword"`
+	SearchVolume int     `json:"search_volume"`
+	PCVolume     int     `json:"pc_volume"`
+	MobileVolume int     `json:"mobile_volume"`
+	Competition  int     `json:"competition"`   // 竞价激烈程度，0-100
+	Difficulty   int     `json:"difficulty"`    // 自然排名优化难度，0-100
+	BidCompanies int     `json:"bid_companies"` // 竞价公司数量
+	CPC          float64 `json:"cpc"`           // 竞价点击单价（元）
+	LongTailSize int     `json:"long_tail_count"`
+}
+
+// LongTailKeywords 长尾词挖掘，返回关键词及其指标
+func (c *API5118Client) LongTailKeywords(word string, page, pageSize int) ([]Keyword5118Metrics, int, error) {
+	var data struct {
+		Total int                  `json:"total"`
+		Items []Keyword5118Metrics `json:"items"`
+	}
+	if err := c.post("/keyword/longtail", map[string]interface{}{
+		"keyword":   word,
+		"page":      page,
+		"page_size": pageSize,
+	}, &data); err != nil {
+		return nil, 0, err
+	}
+	return data.Items, data.Total, nil
+}
+
+// KeywordMetrics 批量查询关键词的PC和移动端搜索量、竞价竞争度和点击单价，每次最多MaxMetricsBatch个
+func (c *API5118Client) KeywordMetrics(words []string) ([]Keyword5118Metrics, error) {
+	if len(words) > MaxMetricsBatch {
+		return nil, fmt.Errorf("单次最多查询%d个关键词", MaxMetricsBatch)
+	}
+
+	var data struct {
+		Items []Keyword5118Metrics `json:"items"`
+	}
+	if err := c.post("/keyword/metrics", map[string]interface{}{
+		"keywords": words,
+	}, &data); err != nil {
+		return nil, err
+	}
+	return data.Items, nil
+}
+
+// KeywordTrend 查询关键词的月度指数趋势
+func (c *API5118Client) KeywordTrend(word string) ([]models.TrendPoint, error) {
+	var data struct {
+		Items []models.TrendPoint `json:"items"`
+	}
+	if err := c.post("/keyword/trend", map[string]interface{}{
+		"keyword": word,
+	}, &data); err != nil {
+		return nil, err
+	}
+	return data.Items, nil
+}
+
+// RelatedQuestions 查询用户围绕关键词提出的相关问题
+func (c *API5118Client) RelatedQuestions(word string, limit int) ([]string, error) {
+	var data struct {
+		Items []struct {
+			Question string `json:"question"`
+		} `json:"items"`
+	}
+	if err := c.post("/keyword/questions", map[string]interface{}{
+		"keyword":   word,
+		"page_size": limit,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	questions := make([]string, 0, len(data.Items))
+	for _, item := range data.Items {
+		if question := strings.TrimSpace(item.Question); question != "" {
+			questions = append(questions, question)
+		}
+	}
+	return questions, nil
+}
+
+// API5118LongTail 5118长尾词挖掘来源
+type API5118LongTail struct {
+	client *API5118Client
+}
+
+// NewAPI5118LongTail 创建5118长尾词挖掘来源
+func NewAPI5118LongTail(client *API5118Client) *API5118LongTail {
+	return &API5118LongTail{client: client}
+}
+
+// Name 来源名称
+func (s *API5118LongTail) Name() string {
+	return KeywordSource5118LongTail
+}
+
+// Enabled 是否已配置API密钥
+func (s *API5118LongTail) Enabled() bool {
+	return s.client.Enabled()
+}
+
+// Fetch 分页挖掘种子词的长尾词，每页计为一次请求
+func (s *API5118LongTail) Fetch(ctx context.Context, query KeywordQuery) (*KeywordFetchResult, error) {
+	seed := strings.TrimSpace(query.Seed)
+	if seed == "" {
+		return nil, fmt.Errorf("%w: 长尾词挖掘需要种子词", ErrInvalidKeywordQuery)
+	}
+
+	const pageSize = 100
+	result := &KeywordFetchResult{}
+	for page := 1; len(result.Keywords) < query.Limit && query.requestsLeft(result.Requests); page++ {
+		if page > 1 {
+			// 避免请求过快
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+
+		result.Requests++
+		items, total, err := s.client.LongTailKeywords(seed, page, pageSize)
+		if err != nil {
+			return result, err
+		}
+
+		result.Metrics = append(result.Metrics, items...)
+		for _, item := range items {
+			result.Keywords = append(result.Keywords, models.Keyword{
+				Word:         item.Keyword,
+				SearchVolume: item.SearchVolume,
+				Source:       KeywordSource5118LongTail,
+			})
+		}
+		if len(items) == 0 || page*pageSize >= total {
+			break
+		}
+	}
+
+	if len(result.Keywords) > query.Limit {
+		result.Keywords = result.Keywords[:query.Limit]
+	}
+	return result, nil
+}
+
+// PriorityScore 关键词选题得分（0-100）：搜索量按对数计分，自然排名优化难度越高得分越低，难度未知（0）时按中等难度计算
+func PriorityScore(searchVolume, difficulty int) float64 {
+	if searchVolume <= 0 {
+		return 0
+	}
+
+	volumeScore := math.Min(math.Log10(float64(searchVolume)+1)/math.Log10(opportunityVolumeCap+1), 1)
+	ease := 0.5
+	if difficulty > 0 {
+		ease = 1 - float64(min(difficulty, 100))/100
+	}
+
+	score := 100 * volumeScore * (0.3 + 0.7*ease)
+	return math.Round(score*100) / 100
+}
+
+// OpportunityScore 关键词机会得分（0-100）：搜索量按对数计分，竞价越激烈越难获得流量，移动端占比高时略有加成
+func OpportunityScore(searchVolume, mobileVolume, competition int) float64 {
+	if searchVolume <= 0 {
+		return 0
+	}
+
+	volumeScore := math.Min(math.Log10(float64(searchVolume)+1)/math.Log10(opportunityVolumeCap+1), 1)
+	ease := 1 - float64(max(0, min(competition, 100)))/100
+	mobileShare := math.Min(float64(max(mobileVolume, 0))/float64(searchVolume), 1)
+
+	score := 100 * volumeScore * (0.3 + 0.7*ease) * (1 + opportunityMobileBonus*mobileShare)
+	return math.Round(math.Min(score, 100)*100) / 100
+}
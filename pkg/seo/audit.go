@@ -0,0 +1,343 @@
+package seo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SEO审核检查项
+const (
+	AuditCheckTitle          = "title_length"
+	AuditCheckDescription    = "meta_description"
+	AuditCheckDuplicateTitle = "duplicate_title"
+	AuditCheckDuplicateDesc  = "duplicate_description"
+	AuditCheckHeadings       = "missing_h2"
+	AuditCheckKeyword        = "keyword"
+	AuditCheckInternalLinks  = "internal_links"
+	AuditCheckOrphan         = "orphan"
+	AuditCheckThinContent    = "thin_content"
+	AuditCheckImages         = "images"
+)
+
+// SEO审核问题级别
+const (
+	AuditSeverityError   = "error"
+	AuditSeverityWarning = "warning"
+)
+
+// 审核阈值，标题和描述按显示宽度计算（汉字和全角字符计2）
+const (
+	AuditTitleMinWidth       = 20
+	AuditTitleMaxWidth       = 60
+	AuditDescriptionMinWidth = 80
+	AuditDescriptionMaxWidth = 160
+	AuditMinContentLength    = 600 // 正文字数（不含空白和Markdown标记）
+	AuditMinInternalLinks    = 2
+)
+
+// 每个问题扣除的分数
+const (
+	auditErrorPenalty   = 15
+	auditWarningPenalty = 5
+)
+
+var markdownH2Pattern = regexp.MustCompile(`(?m)^\s*##\s+\S`)
+
+// AuditPage 待审核的页面
+type AuditPage struct {
+	ArticleID uint
+	Slug      string
+	Title     string
+	MetaTitle string // 为空时使用Title
+	MetaDesc  string
+	Content   string // Markdown正文
+	Keywords  []string
+}
+
+// AuditIssue SEO审核发现的问题
+type AuditIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// PageAudit 单个页面的审核结果
+type PageAudit struct {
+	ArticleID        uint         `json:"article_id"`
+	Slug             string       `json:"slug"`
+	Score            int          `json:"score"`
+	TitleWidth       int          `json:"title_width"`
+	DescWidth        int          `json:"desc_width"`
+	ContentLength    int          `json:"content_length"`
+	H2Count          int          `json:"h2_count"`
+	MissingKeywords  []string     `json:"missing_keywords"`
+	InternalLinks    int          `json:"internal_links"`
+	InboundLinks     int          `json:"inbound_links"`
+	Images           int          `json:"images"`
+	ImagesWithoutAlt int          `json:"images_without_alt"`
+	Issues           []AuditIssue `json:"issues"`
+}
+
+// SiteAudit 全站审核结果
+type SiteAudit struct {
+	Pages        []PageAudit    `json:"pages"`
+	AverageScore float64        `json:"average_score"`
+	IssueCounts  map[string]int `json:"issue_counts"` // 各检查项有问题的页面数
+}
+
+// DisplayWidth 字符串的显示宽度，汉字、假名、韩文和全角字符计2，其余计1
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWideRune(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// isWideRune 是否为全宽字符
+func isWideRune(r rune) bool {
+	switch {
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hangul, r),
+		unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		return true
+	case r >= 0x3000 && r <= 0x303F: // 中日韩标点
+		return true
+	case r >= 0xFF01 && r <= 0xFF60, r >= 0xFFE0 && r <= 0xFFE6: // 全角字符
+		return true
+	}
+	return false
+}
+
+// InternalLinkSlugs 提取正文中指向本站文章的链接，返回去重后的Slug
+func (s *SEOService) InternalLinkSlugs(content string) []string {
+	// 先去掉图片，避免图片语法被当作链接
+	content = markdownInlineImagePattern.ReplaceAllString(content, "")
+
+	seen := make(map[string]bool)
+	var slugs []string
+	for _, m := range markdownLinkPattern.FindAllStringSubmatch(content, -1) {
+		slug := s.articleSlug(m[2])
+		if slug != "" && !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// articleSlug 解析指向本站文章页的链接，不是文章页时返回空字符串
+func (s *SEOService) articleSlug(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+
+	if u.Host != "" {
+		site, err := url.Parse(s.config.SEO.SiteURL)
+		if err != nil || !strings.EqualFold(u.Host, site.Host) {
+			return ""
+		}
+	}
+
+	slug, ok := strings.CutPrefix(u.Path, "/health/")
+	if !ok {
+		return ""
+	}
+	return strings.Trim(slug, "/")
+}
+
+// AuditSite 审核一组页面：重复标题和描述、入站链接在这组页面之间统计
+func (s *SEOService) AuditSite(pages []AuditPage) *SiteAudit {
+	titles := make(map[string]int)
+	descs := make(map[string]int)
+	inbound := make(map[string]int)
+
+	for _, page := range pages {
+		titles[normalizeAuditText(pageTitle(page))]++
+		if desc := normalizeAuditText(page.MetaDesc); desc != "" {
+			descs[desc]++
+		}
+		for _, slug := range s.InternalLinkSlugs(page.Content) {
+			if slug != page.Slug {
+				inbound[slug]++
+			}
+		}
+	}
+
+	audit := &SiteAudit{
+		Pages:       make([]PageAudit, 0, len(pages)),
+		IssueCounts: make(map[string]int),
+	}
+
+	total := 0
+	for _, page := range pages {
+		result := s.AuditPage(page, inbound[page.Slug])
+
+		if titles[normalizeAuditText(pageTitle(page))] > 1 {
+			result.addIssue(AuditCheckDuplicateTitle, AuditSeverityError, "标题与其他文章重复")
+		}
+		if desc := normalizeAuditText(page.MetaDesc); desc != "" && descs[desc] > 1 {
+			result.addIssue(AuditCheckDuplicateDesc, AuditSeverityWarning, "描述与其他文章重复")
+		}
+
+		checks := make(map[string]bool)
+		for _, issue := range result.Issues {
+			checks[issue.Check] = true
+		}
+		for check := range checks {
+			audit.IssueCounts[check]++
+		}
+
+		total += result.Score
+		audit.Pages = append(audit.Pages, result)
+	}
+
+	if len(pages) > 0 {
+		audit.AverageScore = float64(total) / float64(len(pages))
+	}
+
+	// 得分低的页面排在前面
+	sort.SliceStable(audit.Pages, func(i, j int) bool {
+		return audit.Pages[i].Score < audit.Pages[j].Score
+	})
+
+	return audit
+}
+
+// AuditPage 审核单个页面，inboundLinks为其他页面指向该页面的链接数
+func (s *SEOService) AuditPage(page AuditPage, inboundLinks int) PageAudit {
+	result := PageAudit{
+		ArticleID:       page.ArticleID,
+		Slug:            page.Slug,
+		Score:           100,
+		TitleWidth:      DisplayWidth(pageTitle(page)),
+		DescWidth:       DisplayWidth(strings.TrimSpace(page.MetaDesc)),
+		ContentLength:   contentLength(page.Content),
+		H2Count:         len(markdownH2Pattern.FindAllString(page.Content, -1)),
+		MissingKeywords: []string{},
+		InboundLinks:    inboundLinks,
+		Issues:          []AuditIssue{},
+	}
+
+	// 链接到自身的不计入站内链接
+	for _, slug := range s.InternalLinkSlugs(page.Content) {
+		if slug != page.Slug {
+			result.InternalLinks++
+		}
+	}
+
+	// 标题长度
+	switch {
+	case result.TitleWidth < AuditTitleMinWidth:
+		result.addIssue(AuditCheckTitle, AuditSeverityWarning, fmt.Sprintf("标题过短（显示宽度%d，建议%d-%d）", result.TitleWidth, AuditTitleMinWidth, AuditTitleMaxWidth))
+	case result.TitleWidth > AuditTitleMaxWidth:
+		result.addIssue(AuditCheckTitle, AuditSeverityWarning, fmt.Sprintf("标题过长，搜索结果中会被截断（显示宽度%d，建议%d-%d）", result.TitleWidth, AuditTitleMinWidth, AuditTitleMaxWidth))
+	}
+
+	// 描述长度
+	switch {
+	case result.DescWidth == 0:
+		result.addIssue(AuditCheckDescription, AuditSeverityError, "缺少meta描述")
+	case result.DescWidth < AuditDescriptionMinWidth:
+		result.addIssue(AuditCheckDescription, AuditSeverityWarning, fmt.Sprintf("描述过短（显示宽度%d，建议%d-%d）", result.DescWidth, AuditDescriptionMinWidth, AuditDescriptionMaxWidth))
+	case result.DescWidth > AuditDescriptionMaxWidth:
+		result.addIssue(AuditCheckDescription, AuditSeverityWarning, fmt.Sprintf("描述过长，搜索结果中会被截断（显示宽度%d，建议%d-%d）", result.DescWidth, AuditDescriptionMinWidth, AuditDescriptionMaxWidth))
+	}
+
+	// 小标题
+	if result.H2Count == 0 {
+		result.addIssue(AuditCheckHeadings, AuditSeverityWarning, "正文没有H2小标题")
+	}
+
+	// 关键词：需要出现在标题或正文中
+	text := strings.ToLower(pageTitle(page) + "\n" + page.Title + "\n" + page.Content)
+	for _, keyword := range page.Keywords {
+		if keyword != "" && !strings.Contains(text, strings.ToLower(keyword)) {
+			result.MissingKeywords = append(result.MissingKeywords, keyword)
+		}
+	}
+	if len(page.Keywords) == 0 {
+		result.addIssue(AuditCheckKeyword, AuditSeverityWarning, "文章没有关联关键词")
+	} else if len(result.MissingKeywords) > 0 {
+		result.addIssue(AuditCheckKeyword, AuditSeverityError, "标题和正文中未出现关键词: "+strings.Join(result.MissingKeywords, "、"))
+	} else if !strings.Contains(strings.ToLower(pageTitle(page)), strings.ToLower(page.Keywords[0])) {
+		result.addIssue(AuditCheckKeyword, AuditSeverityWarning, "标题中未出现主关键词: "+page.Keywords[0])
+	}
+
+	// 内链
+	if result.InternalLinks < AuditMinInternalLinks {
+		result.addIssue(AuditCheckInternalLinks, AuditSeverityWarning, fmt.Sprintf("站内链接过少（%d个，建议至少%d个）", result.InternalLinks, AuditMinInternalLinks))
+	}
+	if result.InboundLinks == 0 {
+		result.addIssue(AuditCheckOrphan, AuditSeverityWarning, "孤立页面：没有其他文章链接到该页面")
+	}
+
+	// 内容长度
+	if result.ContentLength < AuditMinContentLength {
+		result.addIssue(AuditCheckThinContent, AuditSeverityError, fmt.Sprintf("内容过少（%d字，建议至少%d字）", result.ContentLength, AuditMinContentLength))
+	}
+
+	// 图片
+	for _, m := range markdownInlineImagePattern.FindAllStringSubmatch(page.Content, -1) {
+		result.Images++
+		if strings.TrimSpace(m[1]) == "" {
+			result.ImagesWithoutAlt++
+		}
+	}
+	if result.Images == 0 {
+		result.addIssue(AuditCheckImages, AuditSeverityWarning, "正文没有图片")
+	} else if result.ImagesWithoutAlt > 0 {
+		result.addIssue(AuditCheckImages, AuditSeverityWarning, fmt.Sprintf("%d张图片缺少alt文本", result.ImagesWithoutAlt))
+	}
+
+	return result
+}
+
+// addIssue 记录问题并扣分
+func (a *PageAudit) addIssue(check, severity, message string) {
+	a.Issues = append(a.Issues, AuditIssue{Check: check, Severity: severity, Message: message})
+
+	if severity == AuditSeverityError {
+		a.Score -= auditErrorPenalty
+	} else {
+		a.Score -= auditWarningPenalty
+	}
+	if a.Score < 0 {
+		a.Score = 0
+	}
+}
+
+// pageTitle 搜索结果中显示的标题
+func pageTitle(page AuditPage) string {
+	if title := strings.TrimSpace(page.MetaTitle); title != "" {
+		return title
+	}
+	return strings.TrimSpace(page.Title)
+}
+
+// normalizeAuditText 比较重复时忽略大小写和空白
+func normalizeAuditText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// contentLength 正文字数：去掉图片和链接地址后统计字母、数字和汉字
+func contentLength(content string) int {
+	content = markdownInlineImagePattern.ReplaceAllString(content, "")
+	content = markdownLinkPattern.ReplaceAllString(content, "$1")
+
+	length := 0
+	for _, r := range content {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			length++
+		}
+	}
+	return length
+}
@@ -0,0 +1,166 @@
+package seo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NietzscheX/seo-generate/config"
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// DefaultBaiduSuggestEndpoint 百度搜索下拉词接口的默认地址
+const DefaultBaiduSuggestEndpoint = "https://suggestion.baidu.com/su"
+
+const (
+	// maxBaiduSuggestRequests 单次获取最多请求下拉词接口的次数
+	maxBaiduSuggestRequests = 30
+	// baiduSuggestDelay 两次请求之间的间隔，避免被限流
+	baiduSuggestDelay = 300 * time.Millisecond
+)
+
+// BaiduSuggestClient 百度搜索下拉词客户端：从种子词开始，依次以得到的下拉词继续查询，逐层扩展
+type BaiduSuggestClient struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// NewBaiduSuggestClient 创建百度搜索下拉词客户端
+func NewBaiduSuggestClient(cfg *config.Config) *BaiduSuggestClient {
+	return &BaiduSuggestClient{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+// Name 来源名称
+func (c *BaiduSuggestClient) Name() string {
+	return KeywordSourceBaiduSuggest
+}
+
+// Enabled 下拉词接口不需要密钥，始终可用
+func (c *BaiduSuggestClient) Enabled() bool {
+	return true
+}
+
+// endpoint 下拉词接口地址
+func (c *BaiduSuggestClient) endpoint() string {
+	if c.config.KeywordSources.BaiduSuggestEndpoint != "" {
+		return c.config.KeywordSources.BaiduSuggestEndpoint
+	}
+	return DefaultBaiduSuggestEndpoint
+}
+
+// Fetch 广度优先扩展下拉词，直到达到数量或请求次数上限
+func (c *BaiduSuggestClient) Fetch(ctx context.Context, query KeywordQuery) (*KeywordFetchResult, error) {
+	seed := strings.TrimSpace(query.Seed)
+	if seed == "" {
+		return nil, fmt.Errorf("%w: 下拉词需要种子词", ErrInvalidKeywordQuery)
+	}
+
+	result := &KeywordFetchResult{}
+	seen := map[string]bool{seed: true}
+	queue := []string{seed}
+
+	for len(queue) > 0 && len(result.Keywords) < query.Limit &&
+		result.Requests < maxBaiduSuggestRequests && query.requestsLeft(result.Requests) {
+		if result.Requests > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(baiduSuggestDelay):
+			}
+		}
+
+		word := queue[0]
+		queue = queue[1:]
+
+		result.Requests++
+		suggestions, err := c.Suggest(ctx, word)
+		if err != nil {
+			return result, err
+		}
+
+		for _, suggestion := range suggestions {
+			if seen[suggestion] {
+				continue
+			}
+			seen[suggestion] = true
+			queue = append(queue, suggestion)
+			result.Keywords = append(result.Keywords, models.Keyword{
+				Word:   suggestion,
+				Source: KeywordSourceBaiduSuggest,
+			})
+			if len(result.Keywords) >= query.Limit {
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Suggest 查询一个词的下拉词
+func (c *BaiduSuggestClient) Suggest(ctx context.Context, word string) ([]string, error) {
+	params := url.Values{}
+	params.Set("wd", word)
+	params.Set("action", "opensearch")
+	params.Set("ie", "utf-8")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint()+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下拉词接口返回状态码%d", resp.StatusCode)
+	}
+
+	// 接口可能按GBK编码返回
+	if !utf8.Valid(body) {
+		if body, err = simplifiedchinese.GBK.NewDecoder().Bytes(body); err != nil {
+			return nil, fmt.Errorf("解码响应失败: %w", err)
+		}
+	}
+
+	// 响应格式为 ["查询词", ["下拉词1", "下拉词2", ...]]
+	var response []json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if len(response) < 2 {
+		return nil, nil
+	}
+
+	var suggestions []string
+	if err := json.Unmarshal(response[1], &suggestions); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	words := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if suggestion = strings.TrimSpace(suggestion); suggestion != "" {
+			words = append(words, suggestion)
+		}
+	}
+	return words, nil
+}
@@ -0,0 +1,149 @@
+package seo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 屏蔽规则的匹配方式，三种方式都按忽略大小写和空白后的关键词匹配
+const (
+	BlockMatchExact    = "exact"    // 关键词与词条完全相同
+	BlockMatchContains = "contains" // 关键词包含词条
+	BlockMatchRegex    = "regex"    // 关键词匹配正则表达式
+)
+
+// 屏蔽规则分类
+const (
+	BlockCategoryBrand      = "brand"      // 品牌、药品商品名
+	BlockCategoryCompetitor = "competitor" // 竞品网站，搜索这类词的用户要找的是对方网站
+	BlockCategoryAdult      = "adult"      // 色情低俗
+	BlockCategoryDangerous  = "dangerous"  // 危险的医疗问题，如自残、致死剂量、违禁药品
+	BlockCategoryCustom     = "custom"     // 其他
+)
+
+// blockCategoryNames 屏蔽规则分类的名称，用于屏蔽原因
+var blockCategoryNames = map[string]string{
+	BlockCategoryBrand:      "品牌词",
+	BlockCategoryCompetitor: "竞品网站",
+	BlockCategoryAdult:      "色情低俗",
+	BlockCategoryDangerous:  "危险医疗问题",
+	BlockCategoryCustom:     "自定义",
+}
+
+// ErrInvalidBlockRule 屏蔽规则无效
+var ErrInvalidBlockRule = errors.New("无效的屏蔽规则")
+
+// KeywordBlockRule 关键词屏蔽规则
+type KeywordBlockRule struct {
+	ID        uint
+	Pattern   string
+	MatchType string
+	Category  string
+}
+
+// Reason 命中规则的关键词的屏蔽原因
+func (r KeywordBlockRule) Reason() string {
+	name, ok := blockCategoryNames[r.Category]
+	if !ok {
+		name = r.Category
+	}
+	return fmt.Sprintf("%s: %s", name, r.Pattern)
+}
+
+// ValidateBlockRule 校验屏蔽规则，正则表达式必须能编译
+func ValidateBlockRule(rule KeywordBlockRule) error {
+	if strings.TrimSpace(rule.Pattern) == "" {
+		return fmt.Errorf("%w: 规则词条不能为空", ErrInvalidBlockRule)
+	}
+	if _, ok := blockCategoryNames[rule.Category]; !ok {
+		return fmt.Errorf("%w: 不支持的规则分类%s", ErrInvalidBlockRule, rule.Category)
+	}
+	switch rule.MatchType {
+	case BlockMatchExact, BlockMatchContains:
+	case BlockMatchRegex:
+		if _, err := regexp.Compile("(?i)" + rule.Pattern); err != nil {
+			return fmt.Errorf("%w: 正则表达式无效: %v", ErrInvalidBlockRule, err)
+		}
+	default:
+		return fmt.Errorf("%w: 不支持的匹配方式%s", ErrInvalidBlockRule, rule.MatchType)
+	}
+	return nil
+}
+
+// KeywordBlocklist 编译后的屏蔽规则，按精确、包含、正则的顺序匹配
+type KeywordBlocklist struct {
+	exact      map[string]KeywordBlockRule
+	contains   []KeywordBlockRule
+	regexes    []*regexp.Regexp
+	regexRules []KeywordBlockRule
+}
+
+// NewKeywordBlocklist 编译屏蔽规则，无效的规则会被跳过
+func NewKeywordBlocklist(rules []KeywordBlockRule) *KeywordBlocklist {
+	blocklist := &KeywordBlocklist{exact: make(map[string]KeywordBlockRule)}
+	for _, rule := range rules {
+		if ValidateBlockRule(rule) != nil {
+			continue
+		}
+		switch rule.MatchType {
+		case BlockMatchExact:
+			key := blockText(rule.Pattern)
+			if _, ok := blocklist.exact[key]; !ok {
+				blocklist.exact[key] = rule
+			}
+		case BlockMatchContains:
+			rule.Pattern = blockText(rule.Pattern)
+			blocklist.contains = append(blocklist.contains, rule)
+		case BlockMatchRegex:
+			blocklist.regexes = append(blocklist.regexes, regexp.MustCompile("(?i)"+rule.Pattern))
+			blocklist.regexRules = append(blocklist.regexRules, rule)
+		}
+	}
+	return blocklist
+}
+
+// Match 返回关键词命中的第一条规则
+func (b *KeywordBlocklist) Match(word string) (KeywordBlockRule, bool) {
+	text := blockText(word)
+	if rule, ok := b.exact[text]; ok {
+		return rule, true
+	}
+	for _, rule := range b.contains {
+		if strings.Contains(text, rule.Pattern) {
+			return rule, true
+		}
+	}
+	for i, re := range b.regexes {
+		if re.MatchString(text) {
+			return b.regexRules[i], true
+		}
+	}
+	return KeywordBlockRule{}, false
+}
+
+// blockText 匹配屏蔽规则时忽略大小写和空白
+func blockText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), ""))
+}
+
+// DefaultKeywordBlockRules 默认屏蔽规则：竞品网站、色情低俗和危险医疗问题。品牌词与站点经营范围有关，由管理员按需添加
+func DefaultKeywordBlockRules() []KeywordBlockRule {
+	var rules []KeywordBlockRule
+
+	for _, pattern := range []string{"丁香医生", "好大夫", "春雨医生", "平安好医生", "寻医问药", "有问必答", "39健康网", "百度百科", "知乎"} {
+		rules = append(rules, KeywordBlockRule{Pattern: pattern, MatchType: BlockMatchContains, Category: BlockCategoryCompetitor})
+	}
+	rules = append(rules, KeywordBlockRule{Pattern: `(官网|官方网站|app下载|网站|论坛)$`, MatchType: BlockMatchRegex, Category: BlockCategoryCompetitor})
+
+	for _, pattern := range []string{"色情", "黄色网站", "成人视频", "约炮", "春药", "催情"} {
+		rules = append(rules, KeywordBlockRule{Pattern: pattern, MatchType: BlockMatchContains, Category: BlockCategoryAdult})
+	}
+
+	for _, pattern := range []string{"自杀", "自残", "致死量", "吃多少会死", "安乐死药", "堕胎药购买", "打胎药哪里买", "迷药", "处方药代购", "代孕"} {
+		rules = append(rules, KeywordBlockRule{Pattern: pattern, MatchType: BlockMatchContains, Category: BlockCategoryDangerous})
+	}
+
+	return rules
+}
@@ -0,0 +1,275 @@
+package seo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 关键词蚕食的匹配方式，按强弱排列
+const (
+	CannibalMatchKeyword    = "keyword"    // 关联同一个关键词
+	CannibalMatchNormalized = "normalized" // 关键词规范化后相同，如"失眠怎么办"和"失眠怎么办呢"
+	CannibalMatchCluster    = "cluster"    // 关键词属于同一个聚类
+)
+
+// 关键词蚕食的处理建议
+const (
+	CannibalActionMerge     = "merge"     // 内容合并到保留文章后301跳转
+	CannibalActionRedirect  = "redirect"  // 直接301跳转到保留文章并归档
+	CannibalActionCanonical = "canonical" // 保留页面，规范URL指向保留文章
+)
+
+// cannibalMergeViewRatio 被替换文章的浏览量达到保留文章的该比例时建议先合并内容，以免丢失有价值的内容
+const cannibalMergeViewRatio = 0.3
+
+// cannibalMatchRank 匹配方式的强弱，数值越小越强
+var cannibalMatchRank = map[string]int{
+	CannibalMatchKeyword:    0,
+	CannibalMatchNormalized: 1,
+	CannibalMatchCluster:    2,
+}
+
+// keywordFormReplacer 统一疑问词的不同写法，较长的写法在前
+var keywordFormReplacer = strings.NewReplacer(
+	"怎么样", "怎么",
+	"怎样", "怎么",
+	"如何", "怎么",
+	"咋", "怎么",
+	"为何", "为什么",
+	"有什么", "有哪些",
+	"的", "",
+)
+
+// keywordTrailingParticles 句末语气词，不影响搜索意图
+var keywordTrailingParticles = []string{"吗", "呢", "啊", "呀", "吧"}
+
+// CannibalKeyword 文章关联的关键词
+type CannibalKeyword struct {
+	ID        uint
+	Word      string
+	ClusterID *uint
+}
+
+// CannibalPage 参与关键词蚕食检查的已发布文章
+type CannibalPage struct {
+	ArticleID   uint
+	Slug        string
+	Title       string
+	Content     string
+	ViewCount   int
+	PublishedAt time.Time
+	Keywords    []CannibalKeyword
+}
+
+// CannibalArticle 关键词蚕食分组中的文章，保留文章没有处理建议
+type CannibalArticle struct {
+	ArticleID     uint     `json:"article_id"`
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	ViewCount     int      `json:"view_count"`
+	ContentLength int      `json:"content_length"`
+	Keywords      []string `json:"keywords"`
+	Match         string   `json:"match,omitempty"`
+	Action        string   `json:"action,omitempty"`
+	Reason        string   `json:"reason,omitempty"`
+}
+
+// CannibalGroup 争夺相同关键词的一组文章
+type CannibalGroup struct {
+	Match    string            `json:"match"`    // 组内最强的匹配方式
+	Keywords []string          `json:"keywords"` // 组内文章共同争夺的关键词
+	WinnerID uint              `json:"winner_id"`
+	Articles []CannibalArticle `json:"articles"` // 保留文章在前
+}
+
+// NormalizeKeyword 规范化关键词：忽略大小写、空白和标点，统一疑问词写法，去掉"的"和句末语气词
+func NormalizeKeyword(word string) string {
+	word = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, word)
+	word = keywordFormReplacer.Replace(word)
+	for {
+		trimmed := word
+		for _, particle := range keywordTrailingParticles {
+			trimmed = strings.TrimSuffix(trimmed, particle)
+		}
+		if trimmed == word {
+			return word
+		}
+		word = trimmed
+	}
+}
+
+// FindCannibalization 找出争夺相同关键词的文章：关联同一关键词、关键词规范化后相同或关键词属于同一聚类的文章归为一组。
+// 每组保留浏览量最高的文章（其次是正文较长、发布较早的），其余文章按匹配方式给出处理建议：
+// 同一聚类的不同关键词建议设置规范URL；争夺同一关键词的，浏览量较高的建议合并内容，否则直接301跳转
+func FindCannibalization(pages []CannibalPage) []CannibalGroup {
+	// 每个匹配键对应的文章和关键词
+	type matchKey struct {
+		match string
+		pages []int
+		words []string
+	}
+	keys := make(map[string]*matchKey)
+	addKey := func(key, match string, page int, word string) {
+		entry, ok := keys[key]
+		if !ok {
+			entry = &matchKey{match: match}
+			keys[key] = entry
+		}
+		if len(entry.pages) == 0 || entry.pages[len(entry.pages)-1] != page {
+			entry.pages = append(entry.pages, page)
+		}
+		if !containsString(entry.words, word) {
+			entry.words = append(entry.words, word)
+		}
+	}
+	for i, page := range pages {
+		for _, keyword := range page.Keywords {
+			addKey("k:"+strconv.FormatUint(uint64(keyword.ID), 10), CannibalMatchKeyword, i, keyword.Word)
+			if normalized := NormalizeKeyword(keyword.Word); normalized != "" {
+				addKey("n:"+normalized, CannibalMatchNormalized, i, keyword.Word)
+			}
+			if keyword.ClusterID != nil {
+				addKey("c:"+strconv.FormatUint(uint64(*keyword.ClusterID), 10), CannibalMatchCluster, i, keyword.Word)
+			}
+		}
+	}
+
+	// 并查集合并共享匹配键的文章，并记录每篇文章最强的匹配方式
+	parent := make([]int, len(pages))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	pageMatch := make(map[int]string)
+	groupWords := make(map[int][]string)
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		entry := keys[key]
+		if len(entry.pages) < 2 {
+			continue
+		}
+		for _, page := range entry.pages[1:] {
+			parent[find(page)] = find(entry.pages[0])
+		}
+		for _, page := range entry.pages {
+			if current, ok := pageMatch[page]; !ok || cannibalMatchRank[entry.match] < cannibalMatchRank[current] {
+				pageMatch[page] = entry.match
+			}
+		}
+		groupWords[entry.pages[0]] = append(groupWords[entry.pages[0]], entry.words...)
+	}
+
+	members := make(map[int][]int)
+	words := make(map[int][]string)
+	for i := range pages {
+		if _, ok := pageMatch[i]; !ok {
+			continue
+		}
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+	for page, pageWords := range groupWords {
+		root := find(page)
+		for _, word := range pageWords {
+			if !containsString(words[root], word) {
+				words[root] = append(words[root], word)
+			}
+		}
+	}
+
+	groups := make([]CannibalGroup, 0, len(members))
+	for root, indexes := range members {
+		articles := make([]CannibalArticle, 0, len(indexes))
+		published := make(map[uint]time.Time, len(indexes))
+		match := CannibalMatchCluster
+		for _, i := range indexes {
+			page := pages[i]
+			keywords := make([]string, 0, len(page.Keywords))
+			for _, keyword := range page.Keywords {
+				keywords = append(keywords, keyword.Word)
+			}
+			articles = append(articles, CannibalArticle{
+				ArticleID:     page.ArticleID,
+				Slug:          page.Slug,
+				Title:         page.Title,
+				ViewCount:     page.ViewCount,
+				ContentLength: contentLength(page.Content),
+				Keywords:      keywords,
+				Match:         pageMatch[i],
+			})
+			published[page.ArticleID] = page.PublishedAt
+			if cannibalMatchRank[pageMatch[i]] < cannibalMatchRank[match] {
+				match = pageMatch[i]
+			}
+		}
+
+		sort.SliceStable(articles, func(i, j int) bool {
+			a, b := articles[i], articles[j]
+			if a.ViewCount != b.ViewCount {
+				return a.ViewCount > b.ViewCount
+			}
+			if a.ContentLength != b.ContentLength {
+				return a.ContentLength > b.ContentLength
+			}
+			if !published[a.ArticleID].Equal(published[b.ArticleID]) {
+				return published[a.ArticleID].Before(published[b.ArticleID])
+			}
+			return a.ArticleID < b.ArticleID
+		})
+
+		winner := articles[0]
+		articles[0].Match = ""
+		for i := 1; i < len(articles); i++ {
+			articles[i].Action, articles[i].Reason = cannibalAction(winner, articles[i])
+		}
+
+		sort.Strings(words[root])
+		groups = append(groups, CannibalGroup{
+			Match:    match,
+			Keywords: words[root],
+			WinnerID: winner.ArticleID,
+			Articles: articles,
+		})
+	}
+
+	// 匹配越强、涉及文章越多的分组越靠前
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Match != groups[j].Match {
+			return cannibalMatchRank[groups[i].Match] < cannibalMatchRank[groups[j].Match]
+		}
+		if len(groups[i].Articles) != len(groups[j].Articles) {
+			return len(groups[i].Articles) > len(groups[j].Articles)
+		}
+		return groups[i].WinnerID < groups[j].WinnerID
+	})
+	return groups
+}
+
+// cannibalAction 被替换文章的处理建议
+func cannibalAction(winner, loser CannibalArticle) (string, string) {
+	if loser.Match == CannibalMatchCluster {
+		return CannibalActionCanonical, "关键词属于同一聚类但不完全相同，建议保留页面并将规范URL指向《" + winner.Title + "》"
+	}
+	if loser.ViewCount > 0 && float64(loser.ViewCount) >= float64(winner.ViewCount)*cannibalMergeViewRatio {
+		return CannibalActionMerge, "与《" + winner.Title + "》争夺相同关键词且有一定流量，建议将有价值的内容合并过去后301跳转"
+	}
+	return CannibalActionRedirect, "与《" + winner.Title + "》争夺相同关键词，建议301跳转到保留文章并归档"
+}
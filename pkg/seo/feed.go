@@ -0,0 +1,268 @@
+package seo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+const (
+	// maxFeedSummaryLength 没有摘要时从正文截取的长度
+	maxFeedSummaryLength = 200
+
+	atomXMLNS    = "http://www.w3.org/2005/Atom"
+	contentXMLNS = "http://purl.org/rss/1.0/modules/content/"
+)
+
+// RSS RSS 2.0订阅源
+type RSS struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSAtom    string     `xml:"xmlns:atom,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr,omitempty"`
+	Channel      RSSChannel `xml:"channel"`
+}
+
+// RSSChannel RSS频道
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      AtomLink  `xml:"atom:link"`
+	Items         []RSSItem `xml:"item"`
+}
+
+// RSSItem RSS条目
+type RSSItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        RSSGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+// RSSGUID RSS条目唯一标识
+type RSSGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// AtomFeed Atom订阅源
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []AtomLink  `xml:"link"`
+	Author   AtomPerson  `xml:"author"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+// AtomLink Atom链接
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomPerson Atom作者
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomEntry Atom条目
+type AtomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       AtomLink       `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *AtomPerson    `xml:"author,omitempty"`
+	Summary    string         `xml:"summary"`
+	Content    *AtomContent   `xml:"content,omitempty"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+// AtomContent Atom条目正文
+type AtomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// AtomCategory Atom条目分类
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// CategoryPath 分类页的站内路径
+func CategoryPath(name string) string {
+	return "/categories/" + url.PathEscape(name)
+}
+
+// FeedPath 订阅源的站内路径，category为空时为全站订阅源，name为feed.xml或atom.xml
+func FeedPath(category *models.Category, name string) string {
+	if category == nil {
+		return "/" + name
+	}
+	return CategoryPath(category.Name) + "/" + name
+}
+
+// feedTitle 订阅源标题和对应的页面地址，分类订阅源指向分类页
+func (s *SEOService) feedTitle(category *models.Category) (string, string) {
+	if category == nil {
+		return s.config.SEO.SiteName, s.config.SEO.SiteURL
+	}
+	return category.Name + " - " + s.config.SEO.SiteName, s.CategoryURL(category.Name)
+}
+
+// feedSummary 条目摘要，依次使用摘要、Meta描述和正文开头
+func feedSummary(article *models.Article) string {
+	if article.Summary != "" {
+		return article.Summary
+	}
+	if article.MetaDesc != "" {
+		return article.MetaDesc
+	}
+
+	text := strings.Join(strings.Fields(StripMarkdown(article.Content)), " ")
+	if utf8.RuneCountInString(text) <= maxFeedSummaryLength {
+		return text
+	}
+	return string([]rune(text)[:maxFeedSummaryLength]) + "..."
+}
+
+// feedContent 全文模式下的HTML正文，站内链接和图片改为完整地址
+func (s *SEOService) feedContent(article *models.Article) string {
+	site := strings.TrimRight(s.config.SEO.SiteURL, "/")
+	return RenderMarkdown(strings.ReplaceAll(article.Content, "](/", "]("+site+"/"))
+}
+
+// categoryNames 文章的分类名称
+func categoryNames(article *models.Article) []string {
+	names := make([]string, 0, len(article.Categories))
+	for _, category := range article.Categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+// GenerateRSS 生成RSS 2.0订阅源，full为true时输出全文
+func (s *SEOService) GenerateRSS(articles []models.Article, category *models.Category, full bool) (string, error) {
+	title, link := s.feedTitle(category)
+
+	rss := RSS{
+		Version:   "2.0",
+		XMLNSAtom: atomXMLNS,
+		Channel: RSSChannel{
+			Title:       title,
+			Link:        link,
+			Description: s.config.SEO.SiteDescription,
+			Language:    "zh-CN",
+			AtomLink: AtomLink{
+				Href: s.absoluteURL(FeedPath(category, "feed.xml")),
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+		},
+	}
+	if full {
+		rss.XMLNSContent = contentXMLNS
+	}
+	if lastMod := LatestLastMod(articles); !lastMod.IsZero() {
+		rss.Channel.LastBuildDate = lastMod.Format(time.RFC1123Z)
+	}
+
+	for i := range articles {
+		article := &articles[i]
+		item := RSSItem{
+			Title:       article.Title,
+			Link:        s.GenerateCanonicalURL(article.Slug),
+			GUID:        RSSGUID{IsPermaLink: true, Value: s.GenerateCanonicalURL(article.Slug)},
+			Description: feedSummary(article),
+			Categories:  categoryNames(article),
+		}
+		if article.PublishedAt != nil {
+			item.PubDate = article.PublishedAt.Format(time.RFC1123Z)
+		}
+		if full {
+			item.Content = s.feedContent(article)
+		}
+		rss.Channel.Items = append(rss.Channel.Items, item)
+	}
+
+	// 生成XML
+	output, err := xml.MarshalIndent(rss, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成RSS XML失败: %w", err)
+	}
+
+	return xml.Header + string(output), nil
+}
+
+// GenerateAtom 生成Atom订阅源，full为true时输出全文
+func (s *SEOService) GenerateAtom(articles []models.Article, category *models.Category, full bool) (string, error) {
+	title, link := s.feedTitle(category)
+	self := s.absoluteURL(FeedPath(category, "atom.xml"))
+
+	// Atom要求updated必填，没有文章时使用当前时间
+	updated := LatestLastMod(articles)
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	feed := AtomFeed{
+		XMLNS:    atomXMLNS,
+		Title:    title,
+		Subtitle: s.config.SEO.SiteDescription,
+		ID:       self,
+		Updated:  updated.Format(time.RFC3339),
+		Links: []AtomLink{
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+			{Href: link, Rel: "alternate", Type: "text/html"},
+		},
+		Author: AtomPerson{Name: s.config.SEO.SiteName},
+	}
+
+	for i := range articles {
+		article := &articles[i]
+		entry := AtomEntry{
+			Title:   article.Title,
+			ID:      s.GenerateCanonicalURL(article.Slug),
+			Link:    AtomLink{Href: s.GenerateCanonicalURL(article.Slug), Rel: "alternate", Type: "text/html"},
+			Updated: ArticleLastMod(article).Format(time.RFC3339),
+			Summary: feedSummary(article),
+		}
+		if article.PublishedAt != nil {
+			entry.Published = article.PublishedAt.Format(time.RFC3339)
+		}
+		if article.User != nil && article.User.DisplayName != "" {
+			entry.Author = &AtomPerson{Name: article.User.DisplayName}
+		}
+		if full {
+			entry.Content = &AtomContent{Type: "html", Body: s.feedContent(article)}
+		}
+		for _, name := range categoryNames(article) {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: name})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	// 生成XML
+	output, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成Atom XML失败: %w", err)
+	}
+
+	return xml.Header + string(output), nil
+}
@@ -0,0 +1,130 @@
+package seo
+
+import (
+	"strings"
+)
+
+// 搜索意图
+const (
+	IntentInformational = "informational" // 了解知识
+	IntentCommercial    = "commercial"    // 比较、挑选
+	IntentNavigational  = "navigational"  // 寻找特定网站或机构
+	IntentTransactional = "transactional" // 购买、预约、下载
+)
+
+// 养生领域的内容意图
+const (
+	DomainIntentSymptom  = "symptom"  // 症状、病因和调理
+	DomainIntentRecipe   = "recipe"   // 食谱、药膳、茶饮
+	DomainIntentExercise = "exercise" // 功法、运动、练习
+	DomainIntentTheory   = "theory"   // 中医理论、概念、经典
+)
+
+// 规则分类的置信度
+const (
+	// intentConfidenceMatched 命中明确的意图词
+	intentConfidenceMatched = 0.9
+	// intentConfidenceQuestion 包含疑问词或领域内容词，判断为了解知识
+	intentConfidenceQuestion = 0.7
+	// intentConfidenceDefault 没有命中任何规则，默认为了解知识
+	intentConfidenceDefault = 0.4
+)
+
+// Intents 所有搜索意图
+var Intents = []string{IntentInformational, IntentCommercial, IntentNavigational, IntentTransactional}
+
+// DomainIntents 所有领域内容意图
+var DomainIntents = []string{DomainIntentSymptom, DomainIntentRecipe, DomainIntentExercise, DomainIntentTheory}
+
+// intentRule 意图规则，按顺序匹配，先命中的优先
+type intentRule struct {
+	intent string
+	terms  []string
+}
+
+// searchIntentRules 搜索意图规则，交易和导航意图的用词更明确，优先匹配
+var searchIntentRules = []intentRule{
+	{IntentTransactional, []string{"购买", "哪里买", "在哪买", "多少钱", "价格表", "团购", "优惠", "下单", "包邮", "预约", "挂号", "报名", "下载", "批发", "代购"}},
+	{IntentNavigational, []string{"官网", "官方网站", "官方", "登录", "入口", "地址", "电话", "app", "公众号", "怎么走"}},
+	{IntentCommercial, []string{"推荐", "哪个好", "哪家好", "哪种好", "排行", "排名", "十大", "品牌", "牌子", "测评", "评测", "对比", "值得买", "好不好", "价格", "性价比", "口碑"}},
+}
+
+// questionTerms 疑问词，包含时判断为了解知识
+var questionTerms = []string{"什么", "怎么", "如何", "为什么", "为何", "吗", "能否", "可以", "多久", "哪些", "几", "方法", "作用", "功效", "好处", "区别", "禁忌", "注意"}
+
+// domainIntentRules 领域内容意图规则，按命中数量判断，数量相同时按顺序优先
+var domainIntentRules = []intentRule{
+	{DomainIntentRecipe, []string{"食谱", "菜谱", "做法", "怎么做", "怎么煮", "怎么泡", "汤", "粥", "茶", "煲", "炖", "药膳", "食疗", "吃什么", "喝什么", "配方", "泡水", "泡酒"}},
+	{DomainIntentExercise, []string{"八段锦", "太极", "五禽戏", "易筋经", "六字诀", "站桩", "瑜伽", "冥想", "打坐", "禅修", "功法", "动作", "教学", "练习", "锻炼", "拉伸", "按摩手法", "口诀"}},
+	{DomainIntentSymptom, []string{"症状", "表现", "原因", "怎么回事", "怎么办", "调理", "失眠", "头晕", "头痛", "便秘", "上火", "湿气重", "乏力", "出汗", "疼", "痛", "肿", "咳嗽", "虚", "不调"}},
+	{DomainIntentTheory, []string{"阴阳", "五行", "经络", "穴位", "气血", "脏腑", "体质", "节气", "黄帝内经", "伤寒论", "本草纲目", "理论", "原理", "是什么意思", "含义", "辨证"}},
+}
+
+// IntentResult 关键词的意图分类结果
+type IntentResult struct {
+	Keyword      string  `json:"keyword"`
+	Intent       string  `json:"intent"`
+	DomainIntent string  `json:"domain_intent"` // 不属于任何领域意图时为空
+	Confidence   float64 `json:"confidence"`    // 搜索意图的置信度，0-1
+}
+
+// ValidIntent 是否为有效的搜索意图
+func ValidIntent(intent string) bool {
+	return containsString(Intents, intent)
+}
+
+// ValidDomainIntent 是否为有效的领域内容意图，空字符串表示没有领域意图
+func ValidDomainIntent(intent string) bool {
+	return intent == "" || containsString(DomainIntents, intent)
+}
+
+// ClassifyIntent 按规则判断关键词的搜索意图和领域内容意图
+func ClassifyIntent(word string) IntentResult {
+	text := strings.ToLower(strings.TrimSpace(word))
+	result := IntentResult{Keyword: word, Intent: IntentInformational, Confidence: intentConfidenceDefault}
+
+	matched := false
+	for _, rule := range searchIntentRules {
+		if countTerms(text, rule.terms) > 0 {
+			result.Intent = rule.intent
+			result.Confidence = intentConfidenceMatched
+			matched = true
+			break
+		}
+	}
+
+	best := 0
+	for _, rule := range domainIntentRules {
+		if n := countTerms(text, rule.terms); n > best {
+			best = n
+			result.DomainIntent = rule.intent
+		}
+	}
+
+	if !matched && (best > 0 || countTerms(text, questionTerms) > 0) {
+		result.Confidence = intentConfidenceQuestion
+	}
+
+	return result
+}
+
+// countTerms 文本中出现的规则词数量
+func countTerms(text string, terms []string) int {
+	count := 0
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			count++
+		}
+	}
+	return count
+}
+
+// containsString 字符串列表中是否包含指定值
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
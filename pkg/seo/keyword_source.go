@@ -0,0 +1,49 @@
+package seo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+// 关键词来源名称
+const (
+	KeywordSource5118          = "5118"
+	KeywordSourceBaiduSuggest  = "baidu_suggest"
+	KeywordSourceRelatedSearch = "related_search"
+	KeywordSourceLLM           = "llm"
+)
+
+// ErrInvalidKeywordQuery 获取请求缺少来源需要的参数（种子词或HTML）
+var ErrInvalidKeywordQuery = errors.New("无效的关键词获取请求")
+
+// KeywordQuery 关键词获取请求
+type KeywordQuery struct {
+	Seed        string // 种子词（如分类名称）
+	Limit       int    // 最多返回的关键词数量
+	HTML        string // 搜索结果页的HTML快照，相关搜索来源使用
+	MaxRequests int    // 最多请求外部接口的次数，0表示不限制
+}
+
+// KeywordFetchResult 关键词获取结果
+type KeywordFetchResult struct {
+	Keywords []models.Keyword     // 按来源返回的顺序排列
+	Requests int                  // 实际请求外部接口的次数，用于统计配额
+	Metrics  []Keyword5118Metrics // 来源同时返回的关键词指标（如5118长尾词挖掘）
+}
+
+// KeywordSource 关键词来源接口
+type KeywordSource interface {
+	// Name 来源名称，保存在关键词的Source字段中
+	Name() string
+	// Enabled 是否已配置可用
+	Enabled() bool
+	// Fetch 获取关键词，请求已发出时即使失败也返回结果，便于统计配额
+	Fetch(ctx context.Context, query KeywordQuery) (*KeywordFetchResult, error)
+}
+
+// requestsLeft 是否还可以继续请求
+func (q KeywordQuery) requestsLeft(requests int) bool {
+	return q.MaxRequests <= 0 || requests < q.MaxRequests
+}
@@ -0,0 +1,171 @@
+package seo
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxInternalLinks 未配置时每篇文章自动添加的最多站内链接数
+const DefaultMaxInternalLinks = 5
+
+var markdownHTMLTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+// LinkTarget 可被链接的文章及其主关键词
+type LinkTarget struct {
+	ArticleID uint
+	Slug      string
+	Keyword   string
+}
+
+// InternalLink 自动添加的站内链接
+type InternalLink struct {
+	TargetID uint   `json:"target_id"`
+	Keyword  string `json:"keyword"`
+	URL      string `json:"url"`
+}
+
+// ArticlePath 文章页的站内路径
+func ArticlePath(slug string) string {
+	return "/health/" + slug
+}
+
+// markdown Markdown形式的站内链接
+func (l InternalLink) markdown() string {
+	return "[" + l.Keyword + "](" + l.URL + ")"
+}
+
+// InsertInternalLinks 将正文中其他文章主关键词的首次出现替换为链接：
+// 跳过标题、代码块、已有链接和图片，不链接到自身或已经链接过的文章，
+// 已有的站内链接计入maxLinks上限，关键词较长的文章优先
+func (s *SEOService) InsertInternalLinks(content, selfSlug string, targets []LinkTarget, maxLinks int) (string, []InternalLink) {
+	if maxLinks <= 0 {
+		maxLinks = DefaultMaxInternalLinks
+	}
+
+	linked := make(map[string]bool)
+	for _, slug := range s.InternalLinkSlugs(content) {
+		linked[slug] = true
+	}
+	count := 0
+	for slug := range linked {
+		if slug != selfSlug {
+			count++
+		}
+	}
+
+	targets = append([]LinkTarget(nil), targets...)
+	sort.SliceStable(targets, func(i, j int) bool {
+		return utf8.RuneCountInString(targets[i].Keyword) > utf8.RuneCountInString(targets[j].Keyword)
+	})
+
+	lines := strings.Split(content, "\n")
+	var links []InternalLink
+	for _, target := range targets {
+		if count >= maxLinks {
+			break
+		}
+		if target.Slug == "" || target.Slug == selfSlug || linked[target.Slug] || strings.TrimSpace(target.Keyword) == "" {
+			continue
+		}
+
+		link := InternalLink{TargetID: target.ArticleID, Keyword: target.Keyword, URL: ArticlePath(target.Slug)}
+		if insertFirstMention(lines, link) {
+			linked[target.Slug] = true
+			links = append(links, link)
+			count++
+		}
+	}
+
+	return strings.Join(lines, "\n"), links
+}
+
+// insertFirstMention 在第一个可链接的位置插入链接
+func insertFirstMention(lines []string, link InternalLink) bool {
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if pos := findLinkable(line, link.Keyword); pos >= 0 {
+			lines[i] = line[:pos] + link.markdown() + line[pos+len(link.Keyword):]
+			return true
+		}
+	}
+	return false
+}
+
+// findLinkable 查找关键词在行中第一个不在链接、图片、行内代码或HTML标签内的位置
+func findLinkable(line, keyword string) int {
+	var protected [][]int
+	protected = append(protected, markdownInlineImagePattern.FindAllStringIndex(line, -1)...)
+	protected = append(protected, markdownLinkPattern.FindAllStringIndex(line, -1)...)
+	protected = append(protected, markdownCodePattern.FindAllStringIndex(line, -1)...)
+	protected = append(protected, markdownHTMLTagPattern.FindAllStringIndex(line, -1)...)
+
+	for start := 0; start < len(line); {
+		i := strings.Index(line[start:], keyword)
+		if i < 0 {
+			return -1
+		}
+		pos := start + i
+		end := pos + len(keyword)
+		start = pos + 1
+
+		if overlaps(protected, pos, end) || !wordBoundary(line, pos, end) {
+			continue
+		}
+		return pos
+	}
+	return -1
+}
+
+// overlaps 区间是否与任一受保护的区间重叠
+func overlaps(spans [][]int, start, end int) bool {
+	for _, span := range spans {
+		if start < span[1] && end > span[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// wordBoundary 英文和数字关键词两侧不能紧接字母或数字，避免匹配到单词的一部分
+func wordBoundary(line string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(line[:start])
+		first, _ := utf8.DecodeRuneInString(line[start:])
+		if isASCIIWord(before) && isASCIIWord(first) {
+			return false
+		}
+	}
+	if end < len(line) {
+		after, _ := utf8.DecodeRuneInString(line[end:])
+		last, _ := utf8.DecodeLastRuneInString(line[:end])
+		if isASCIIWord(after) && isASCIIWord(last) {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCIIWord 是否为ASCII字母或数字
+func isASCIIWord(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// RemoveInternalLinks 将自动添加的链接还原为关键词文本，编辑已修改的链接保持不变
+func RemoveInternalLinks(content string, links []InternalLink) string {
+	for _, link := range links {
+		content = strings.Replace(content, link.markdown(), link.Keyword, 1)
+	}
+	return content
+}
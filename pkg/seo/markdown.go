@@ -0,0 +1,99 @@
+package seo
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeadingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	markdownUnorderedPattern = regexp.MustCompile(`^[-*+]\s+(.+)$`)
+	markdownOrderedPattern   = regexp.MustCompile(`^\d+[.)]\s+(.+)$`)
+
+	markdownInlineImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownLinkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBoldPattern        = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownItalicPattern      = regexp.MustCompile(`\*(.+?)\*`)
+	markdownCodePattern        = regexp.MustCompile("`([^`]+)`")
+)
+
+// StripMarkdown 去掉图片和Markdown标记，保留链接文字
+func StripMarkdown(content string) string {
+	content = markdownInlineImagePattern.ReplaceAllString(content, "")
+	content = markdownLinkPattern.ReplaceAllString(content, "$1")
+	return strings.NewReplacer("#", "", "*", "", "`", "", ">", "").Replace(content)
+}
+
+// RenderMarkdown 将文章的Markdown转换为HTML，支持标题、列表、引用、图片、链接、粗体、斜体和行内代码
+func RenderMarkdown(markdown string) string {
+	var out strings.Builder
+	var paragraph []string
+	list := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+			flushParagraph()
+			closeList()
+		case markdownHeadingPattern.MatchString(line):
+			flushParagraph()
+			closeList()
+			m := markdownHeadingPattern.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+		case markdownUnorderedPattern.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInline(markdownUnorderedPattern.FindStringSubmatch(line)[1]) + "</li>\n")
+		case markdownOrderedPattern.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInline(markdownOrderedPattern.FindStringSubmatch(line)[1]) + "</li>\n")
+		case strings.HasPrefix(line, ">"):
+			flushParagraph()
+			closeList()
+			out.WriteString("<blockquote>" + renderInline(strings.TrimSpace(strings.TrimPrefix(line, ">"))) + "</blockquote>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderInline(line))
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return out.String()
+}
+
+// renderInline 转换行内格式，先转义HTML再替换Markdown标记
+func renderInline(text string) string {
+	text = html.EscapeString(text)
+	text = markdownCodePattern.ReplaceAllString(text, "<code>$1</code>")
+	text = markdownInlineImagePattern.ReplaceAllString(text, `<img src="$2" alt="$1">`)
+	text = markdownLinkPattern.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = markdownBoldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	text = markdownItalicPattern.ReplaceAllString(text, "<em>$1</em>")
+	return text
+}
@@ -0,0 +1,89 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+// relatedSearchWindow 相关搜索区块开始后扫描链接的字节数
+const relatedSearchWindow = 20000
+
+var (
+	// relatedSearchBlockPattern 百度、360、搜狗搜索结果页中相关搜索区块的标记
+	relatedSearchBlockPattern = regexp.MustCompile(`(?i)id=["'](rs|rs_new|rs_top_new|hint_container)["']|class=["'][^"']*\brs-[^"']*["']|相关搜索|大家还在搜`)
+	// relatedSearchLinkPattern 区块中的链接
+	relatedSearchLinkPattern = regexp.MustCompile(`(?is)<a\b[^>]*>(.*?)</a>`)
+	// htmlTagPattern HTML标签
+	htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// RelatedSearchParser 从搜索结果页的HTML快照中提取"相关搜索"词，不发出网络请求
+type RelatedSearchParser struct{}
+
+// NewRelatedSearchParser 创建相关搜索解析器
+func NewRelatedSearchParser() *RelatedSearchParser {
+	return &RelatedSearchParser{}
+}
+
+// Name 来源名称
+func (p *RelatedSearchParser) Name() string {
+	return KeywordSourceRelatedSearch
+}
+
+// Enabled 始终可用
+func (p *RelatedSearchParser) Enabled() bool {
+	return true
+}
+
+// Fetch 解析请求中的HTML快照
+func (p *RelatedSearchParser) Fetch(ctx context.Context, query KeywordQuery) (*KeywordFetchResult, error) {
+	if strings.TrimSpace(query.HTML) == "" {
+		return nil, fmt.Errorf("%w: 相关搜索需要提供搜索结果页的HTML", ErrInvalidKeywordQuery)
+	}
+
+	words := ParseRelatedSearches(query.HTML)
+	if query.Limit > 0 && len(words) > query.Limit {
+		words = words[:query.Limit]
+	}
+
+	result := &KeywordFetchResult{Keywords: make([]models.Keyword, 0, len(words))}
+	for _, word := range words {
+		result.Keywords = append(result.Keywords, models.Keyword{
+			Word:   word,
+			Source: KeywordSourceRelatedSearch,
+		})
+	}
+	return result, nil
+}
+
+// ParseRelatedSearches 提取所有相关搜索区块中的链接文本，按出现顺序去重
+func ParseRelatedSearches(page string) []string {
+	var words []string
+	seen := make(map[string]bool)
+
+	end := 0
+	for _, loc := range relatedSearchBlockPattern.FindAllStringIndex(page, -1) {
+		// 已扫描过的区域不重复扫描
+		start := max(loc[0], end)
+		end = min(loc[0]+relatedSearchWindow, len(page))
+		if start >= end {
+			continue
+		}
+
+		for _, match := range relatedSearchLinkPattern.FindAllStringSubmatch(page[start:end], -1) {
+			word := strings.Join(strings.Fields(html.UnescapeString(htmlTagPattern.ReplaceAllString(match[1], ""))), " ")
+			if !ValidKeyword(word) || seen[word] {
+				continue
+			}
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
@@ -0,0 +1,233 @@
+package seo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// robots.txt指令
+const (
+	RobotsAllow      = "allow"
+	RobotsDisallow   = "disallow"
+	RobotsCrawlDelay = "crawl-delay"
+)
+
+// ErrInvalidRobotsRule robots规则无效
+var ErrInvalidRobotsRule = errors.New("无效的robots规则")
+
+// RobotsRule robots.txt规则，同一UserAgent的规则组成一组
+type RobotsRule struct {
+	ID        uint   `json:"id"`
+	UserAgent string `json:"user_agent"`
+	Directive string `json:"directive"`
+	Value     string `json:"value"`
+}
+
+// RobotsGroup 同一User-agent的规则组
+type RobotsGroup struct {
+	UserAgent  string       `json:"user_agent"`
+	Rules      []RobotsRule `json:"rules"`
+	CrawlDelay string       `json:"crawl_delay,omitempty"`
+}
+
+// RobotsTestResult URL的抓取规则检测结果
+type RobotsTestResult struct {
+	UserAgent  string      `json:"user_agent"`
+	Path       string      `json:"path"`
+	Allowed    bool        `json:"allowed"`
+	Group      string      `json:"group,omitempty"` // 匹配的规则组，为空表示没有适用的规则组
+	Rule       *RobotsRule `json:"rule,omitempty"`  // 起决定作用的规则，为空表示没有规则匹配
+	CrawlDelay string      `json:"crawl_delay,omitempty"`
+}
+
+// Robots robots.txt规则集
+type Robots struct {
+	groups   []RobotsGroup
+	sitemaps []string
+}
+
+// DefaultRobotsRules 默认规则：允许抓取页面，禁止抓取API
+func DefaultRobotsRules() []RobotsRule {
+	return []RobotsRule{
+		{UserAgent: "*", Directive: RobotsDisallow, Value: "/api/"},
+		{UserAgent: "*", Directive: RobotsAllow, Value: "/"},
+	}
+}
+
+// ValidateRobotsRule 校验robots规则
+func ValidateRobotsRule(rule RobotsRule) error {
+	if strings.TrimSpace(rule.UserAgent) == "" {
+		return fmt.Errorf("%w: User-agent不能为空", ErrInvalidRobotsRule)
+	}
+	if strings.ContainsAny(rule.UserAgent+rule.Value, "\r\n#") {
+		return fmt.Errorf("%w: 规则不能包含换行或#", ErrInvalidRobotsRule)
+	}
+
+	switch rule.Directive {
+	case RobotsAllow, RobotsDisallow:
+		// Disallow为空表示不限制
+		if rule.Value != "" && !strings.HasPrefix(rule.Value, "/") && !strings.HasPrefix(rule.Value, "*") {
+			return fmt.Errorf("%w: 路径必须以/或*开头: %s", ErrInvalidRobotsRule, rule.Value)
+		}
+		if rule.Directive == RobotsAllow && rule.Value == "" {
+			return fmt.Errorf("%w: Allow的路径不能为空", ErrInvalidRobotsRule)
+		}
+	case RobotsCrawlDelay:
+		delay, err := strconv.ParseFloat(rule.Value, 64)
+		if err != nil || delay <= 0 {
+			return fmt.Errorf("%w: Crawl-delay必须是正数: %s", ErrInvalidRobotsRule, rule.Value)
+		}
+	default:
+		return fmt.Errorf("%w: 无效的指令: %s", ErrInvalidRobotsRule, rule.Directive)
+	}
+	return nil
+}
+
+// NewRobots 按User-agent首次出现的顺序将规则分组
+func NewRobots(rules []RobotsRule, sitemaps ...string) *Robots {
+	robots := &Robots{sitemaps: sitemaps}
+	index := make(map[string]int)
+
+	for _, rule := range rules {
+		key := strings.ToLower(strings.TrimSpace(rule.UserAgent))
+		i, ok := index[key]
+		if !ok {
+			i = len(robots.groups)
+			index[key] = i
+			robots.groups = append(robots.groups, RobotsGroup{UserAgent: strings.TrimSpace(rule.UserAgent)})
+		}
+
+		if rule.Directive == RobotsCrawlDelay {
+			robots.groups[i].CrawlDelay = rule.Value
+			continue
+		}
+		robots.groups[i].Rules = append(robots.groups[i].Rules, rule)
+	}
+
+	return robots
+}
+
+// Groups 规则组
+func (r *Robots) Groups() []RobotsGroup {
+	return r.groups
+}
+
+// String 输出robots.txt
+func (r *Robots) String() string {
+	var b strings.Builder
+	for i, group := range r.groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("User-agent: " + group.UserAgent + "\n")
+		for _, rule := range group.Rules {
+			if rule.Directive == RobotsAllow {
+				b.WriteString("Allow: " + rule.Value + "\n")
+			} else {
+				b.WriteString("Disallow: " + rule.Value + "\n")
+			}
+		}
+		if group.CrawlDelay != "" {
+			b.WriteString("Crawl-delay: " + group.CrawlDelay + "\n")
+		}
+	}
+
+	if len(r.sitemaps) > 0 {
+		b.WriteString("\n")
+		for _, sitemap := range r.sitemaps {
+			b.WriteString("Sitemap: " + sitemap + "\n")
+		}
+	}
+	return b.String()
+}
+
+// group 选择适用的规则组：优先使用名称最长的匹配组，没有时使用*组
+func (r *Robots) group(userAgent string) *RobotsGroup {
+	userAgent = strings.ToLower(userAgent)
+
+	var matched, wildcard *RobotsGroup
+	for i := range r.groups {
+		name := strings.ToLower(r.groups[i].UserAgent)
+		if name == "*" {
+			wildcard = &r.groups[i]
+			continue
+		}
+		if strings.Contains(userAgent, name) && (matched == nil || len(name) > len(matched.UserAgent)) {
+			matched = &r.groups[i]
+		}
+	}
+
+	if matched != nil {
+		return matched
+	}
+	return wildcard
+}
+
+// Test 检测爬虫能否抓取URL，路径最长的匹配规则生效，长度相同时Allow优先
+func (r *Robots) Test(userAgent, rawURL string) (*RobotsTestResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析URL失败: %w", err)
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	result := &RobotsTestResult{UserAgent: userAgent, Path: path, Allowed: true}
+
+	group := r.group(userAgent)
+	if group == nil {
+		return result, nil
+	}
+	result.Group = group.UserAgent
+	result.CrawlDelay = group.CrawlDelay
+
+	best := -1
+	for i, rule := range group.Rules {
+		if rule.Value == "" || !matchRobotsPath(rule.Value, path) {
+			continue
+		}
+
+		length := len(rule.Value)
+		if length > best || (length == best && rule.Directive == RobotsAllow) {
+			best = length
+			result.Rule = &group.Rules[i]
+		}
+	}
+
+	if result.Rule != nil {
+		result.Allowed = result.Rule.Directive == RobotsAllow
+	}
+	return result, nil
+}
+
+// matchRobotsPath 匹配robots路径规则，支持*通配符和$结尾锚点
+func matchRobotsPath(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path)
+}
@@ -0,0 +1,124 @@
+package seo
+
+import (
+	"math"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+// 季节性判断的参数
+const (
+	// seasonalStrength 月度指数的变异系数达到该值时认为搜索量有季节性
+	seasonalStrength = 0.2
+	// peakMonthIndex 月度指数达到该值的月份为高峰月
+	peakMonthIndex = 1.2
+	// lowMonthIndex 月度指数不超过该值的月份为低谷月
+	lowMonthIndex = 0.8
+	// upcomingMonths 高峰在未来几个月内到来时标记为即将进入高峰，便于提前准备内容
+	upcomingMonths = 2
+)
+
+// Seasonality 关键词搜索量的季节性，按自然月汇总，需要覆盖全部12个月才能判断
+type Seasonality struct {
+	Seasonal     bool      `json:"seasonal"`
+	Months       int       `json:"months"`        // 有数据的自然月数
+	Strength     float64   `json:"strength"`      // 月度指数的变异系数
+	MonthlyIndex []float64 `json:"monthly_index"` // 1-12月平均搜索量与全年平均值之比，没有数据的月份为0
+	PeakMonths   []int     `json:"peak_months"`
+	LowMonths    []int     `json:"low_months"`
+	PeakSeason   string    `json:"peak_season,omitempty"` // 搜索量最高的季节：春、夏、秋、冬
+	InSeason     bool      `json:"in_season"`             // 当前月份是高峰月
+	Upcoming     bool      `json:"upcoming"`              // 未来两个月内进入高峰月
+}
+
+// SeasonOf 月份所在的季节，按气象划分：春季3-5月，夏季6-8月，秋季9-11月，冬季12-2月
+func SeasonOf(month time.Month) string {
+	switch month {
+	case time.March, time.April, time.May:
+		return "春"
+	case time.June, time.July, time.August:
+		return "夏"
+	case time.September, time.October, time.November:
+		return "秋"
+	}
+	return "冬"
+}
+
+// AnalyzeSeasonality 根据月度搜索量计算季节性，同一自然月有多年数据时取平均值
+func AnalyzeSeasonality(points []models.TrendPoint, now time.Time) Seasonality {
+	result := Seasonality{
+		MonthlyIndex: make([]float64, 12),
+		PeakMonths:   []int{},
+		LowMonths:    []int{},
+	}
+
+	var sums [12]float64
+	var counts [12]int
+	for _, point := range points {
+		month, err := time.Parse("2006-01", point.Month)
+		if err != nil || point.Volume < 0 {
+			continue
+		}
+		sums[month.Month()-1] += float64(point.Volume)
+		counts[month.Month()-1]++
+	}
+
+	var averages [12]float64
+	var total float64
+	for i := range averages {
+		if counts[i] == 0 {
+			continue
+		}
+		result.Months++
+		averages[i] = sums[i] / float64(counts[i])
+		total += averages[i]
+	}
+	if result.Months < 12 || total <= 0 {
+		return result
+	}
+
+	mean := total / 12
+	var variance float64
+	for i, average := range averages {
+		index := average / mean
+		result.MonthlyIndex[i] = math.Round(index*100) / 100
+		variance += (index - 1) * (index - 1)
+	}
+	result.Strength = math.Round(math.Sqrt(variance/12)*100) / 100
+	result.Seasonal = result.Strength >= seasonalStrength
+	if !result.Seasonal {
+		return result
+	}
+
+	for i, index := range result.MonthlyIndex {
+		if index >= peakMonthIndex {
+			result.PeakMonths = append(result.PeakMonths, i+1)
+		} else if index <= lowMonthIndex {
+			result.LowMonths = append(result.LowMonths, i+1)
+		}
+	}
+
+	// 季节指数取三个月的平均值
+	seasons := make(map[string]float64, 4)
+	for i, index := range result.MonthlyIndex {
+		seasons[SeasonOf(time.Month(i+1))] += index / 3
+	}
+	for _, season := range []string{"春", "夏", "秋", "冬"} {
+		if result.PeakSeason == "" || seasons[season] > seasons[result.PeakSeason] {
+			result.PeakSeason = season
+		}
+	}
+
+	current := int(now.Month())
+	for _, month := range result.PeakMonths {
+		if month == current {
+			result.InSeason = true
+		}
+		if ahead := (month - current + 12) % 12; ahead > 0 && ahead <= upcomingMonths {
+			result.Upcoming = true
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,199 @@
+package seo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+)
+
+const (
+	// MaxSitemapURLs 单个Sitemap文件最多包含的URL数量（协议限制）
+	MaxSitemapURLs = 50000
+	// maxSitemapImages 每个URL最多包含的图片数量
+	maxSitemapImages = 1000
+
+	sitemapXMLNS      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapImageXMLNS = "http://www.google.com/schemas/sitemap-image/1.1"
+)
+
+// URLSet XML Sitemap URL集合
+type URLSet struct {
+	XMLName    xml.Name `xml:"urlset"`
+	XMLNS      string   `xml:"xmlns,attr"`
+	XMLNSImage string   `xml:"xmlns:image,attr,omitempty"`
+	URLs       []URL    `xml:"url"`
+}
+
+// URL XML Sitemap URL
+type URL struct {
+	Loc        string         `xml:"loc"`
+	LastMod    string         `xml:"lastmod,omitempty"`
+	ChangeFreq string         `xml:"changefreq,omitempty"`
+	Priority   float64        `xml:"priority,omitempty"`
+	Images     []SitemapImage `xml:"image:image,omitempty"`
+}
+
+// SitemapImage 图片Sitemap中的图片
+type SitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+// SitemapIndex Sitemap索引
+type SitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	XMLNS    string         `xml:"xmlns,attr"`
+	Sitemaps []SitemapEntry `xml:"sitemap"`
+}
+
+// SitemapEntry Sitemap索引中的一项
+type SitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SitemapCategory 分类Sitemap中的分类，LastMod为分类下最近更新的文章时间
+type SitemapCategory struct {
+	Name    string
+	LastMod time.Time
+}
+
+// ArticleLastMod 文章的最后修改时间，取更新时间和发布时间中较晚的一个
+func ArticleLastMod(article *models.Article) time.Time {
+	lastMod := article.UpdatedAt
+	if article.PublishedAt != nil && article.PublishedAt.After(lastMod) {
+		lastMod = *article.PublishedAt
+	}
+	return lastMod
+}
+
+// LatestLastMod 文章中最新的修改时间
+func LatestLastMod(articles []models.Article) time.Time {
+	var latest time.Time
+	for i := range articles {
+		if lastMod := ArticleLastMod(&articles[i]); lastMod.After(latest) {
+			latest = lastMod
+		}
+	}
+	return latest
+}
+
+// FormatLastMod 按W3C Datetime格式输出时间，零值时不输出
+func FormatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// SitemapURL Sitemap文件的完整地址
+func (s *SEOService) SitemapURL(name string) string {
+	return s.absoluteURL("/" + name)
+}
+
+// GenerateSitemapIndex 生成Sitemap索引
+func (s *SEOService) GenerateSitemapIndex(entries []SitemapEntry) (string, error) {
+	index := SitemapIndex{
+		XMLNS:    sitemapXMLNS,
+		Sitemaps: entries,
+	}
+
+	output, err := xml.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成Sitemap索引XML失败: %w", err)
+	}
+
+	return xml.Header + string(output), nil
+}
+
+// GenerateArticleSitemap 生成文章Sitemap，调用方需保证文章数量不超过MaxSitemapURLs
+func (s *SEOService) GenerateArticleSitemap(articles []models.Article) (string, error) {
+	urlSet := URLSet{
+		XMLNS: sitemapXMLNS,
+	}
+
+	for i := range articles {
+		urlSet.URLs = append(urlSet.URLs, URL{
+			Loc:        s.GenerateCanonicalURL(articles[i].Slug),
+			LastMod:    FormatLastMod(ArticleLastMod(&articles[i])),
+			ChangeFreq: "weekly",
+			Priority:   0.8,
+		})
+	}
+
+	return marshalURLSet(urlSet)
+}
+
+// GenerateImageSitemap 生成图片Sitemap，只包含正文中有图片的文章
+func (s *SEOService) GenerateImageSitemap(articles []models.Article) (string, error) {
+	urlSet := URLSet{
+		XMLNS:      sitemapXMLNS,
+		XMLNSImage: sitemapImageXMLNS,
+	}
+
+	for i := range articles {
+		matches := markdownImagePattern.FindAllStringSubmatch(articles[i].Content, maxSitemapImages)
+		if len(matches) == 0 {
+			continue
+		}
+
+		images := make([]SitemapImage, 0, len(matches))
+		for _, m := range matches {
+			images = append(images, SitemapImage{Loc: s.absoluteURL(m[1])})
+		}
+
+		urlSet.URLs = append(urlSet.URLs, URL{
+			Loc:     s.GenerateCanonicalURL(articles[i].Slug),
+			LastMod: FormatLastMod(ArticleLastMod(&articles[i])),
+			Images:  images,
+		})
+	}
+
+	return marshalURLSet(urlSet)
+}
+
+// GeneratePagesSitemap 生成首页的Sitemap
+func (s *SEOService) GeneratePagesSitemap(homeLastMod time.Time) (string, error) {
+	urlSet := URLSet{
+		XMLNS: sitemapXMLNS,
+	}
+
+	// 添加首页
+	urlSet.URLs = append(urlSet.URLs, URL{
+		Loc:        s.config.SEO.SiteURL,
+		LastMod:    FormatLastMod(homeLastMod),
+		ChangeFreq: "daily",
+		Priority:   1.0,
+	})
+
+	return marshalURLSet(urlSet)
+}
+
+// GenerateCategorySitemap 生成分类页的Sitemap，调用方需保证分类数量不超过MaxSitemapURLs
+func (s *SEOService) GenerateCategorySitemap(categories []SitemapCategory) (string, error) {
+	urlSet := URLSet{
+		XMLNS: sitemapXMLNS,
+	}
+
+	for _, category := range categories {
+		urlSet.URLs = append(urlSet.URLs, URL{
+			Loc:        s.CategoryURL(category.Name),
+			LastMod:    FormatLastMod(category.LastMod),
+			ChangeFreq: "daily",
+			Priority:   0.6,
+		})
+	}
+
+	return marshalURLSet(urlSet)
+}
+
+// marshalURLSet 输出URL集合的XML
+func marshalURLSet(urlSet URLSet) (string, error) {
+	output, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("生成Sitemap XML失败: %w", err)
+	}
+
+	return xml.Header + string(output), nil
+}
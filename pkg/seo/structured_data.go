@@ -0,0 +1,209 @@
+package seo
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/internal/models"
+	"github.com/NietzscheX/seo-generate/pkg/schema"
+)
+
+// markdownImagePattern 匹配Markdown图片
+var markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)`)
+
+// orderedStepPattern 匹配有序列表项，例如"1. "、"2、"
+var orderedStepPattern = regexp.MustCompile(`^\d+[.、．]\s*(.+)$`)
+
+// howToHeadingPattern 判断小标题是否是操作步骤
+var howToHeadingPattern = regexp.MustCompile(`步骤|方法|动作|做法|练法|教程|怎么做|如何|要领`)
+
+// minHowToSteps 识别为操作指南的最少步骤数
+const minHowToSteps = 3
+
+// siteOrganization 网站运营组织
+func (s *SEOService) siteOrganization() *schema.Organization {
+	return &schema.Organization{
+		Name: s.config.SEO.SiteName,
+		URL:  s.config.SEO.SiteURL,
+		Logo: &schema.ImageObject{URL: s.absoluteURL("/static/images/logo.png")},
+	}
+}
+
+// absoluteURL 将站内路径转换为完整地址
+func (s *SEOService) absoluteURL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimRight(s.config.SEO.SiteURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// CategoryURL 分类页地址
+func (s *SEOService) CategoryURL(name string) string {
+	return s.absoluteURL(CategoryPath(name))
+}
+
+// GenerateOrganizationSchema 生成网站运营组织的结构化数据
+func (s *SEOService) GenerateOrganizationSchema() *schema.Organization {
+	return s.siteOrganization()
+}
+
+// GenerateWebSiteSchema 生成带站内搜索的网站结构化数据
+func (s *SEOService) GenerateWebSiteSchema() *schema.WebSite {
+	return &schema.WebSite{
+		Name:            s.config.SEO.SiteName,
+		URL:             s.config.SEO.SiteURL,
+		Publisher:       s.siteOrganization(),
+		PotentialAction: schema.NewSearchAction(s.absoluteURL("/search?q={search_term_string}")),
+	}
+}
+
+// GenerateArticleSchema 生成文章结构化数据，作者设置了署名时使用Person，否则使用网站组织
+func (s *SEOService) GenerateArticleSchema(article *models.Article) *schema.Article {
+	publishedAt := time.Now().Format(time.RFC3339)
+	if article.PublishedAt != nil {
+		publishedAt = article.PublishedAt.Format(time.RFC3339)
+	}
+
+	var author schema.Author = s.siteOrganization()
+	if article.User != nil && article.User.DisplayName != "" {
+		author = schema.Person{Name: article.User.DisplayName}
+	}
+
+	// 使用正文中的第一张图片，没有图片时使用网站Logo
+	image := s.absoluteURL("/static/images/logo.png")
+	if m := markdownImagePattern.FindStringSubmatch(article.Content); m != nil {
+		image = s.absoluteURL(m[1])
+	}
+
+	keywords := make([]string, 0, len(article.Keywords))
+	for _, keyword := range article.Keywords {
+		keywords = append(keywords, keyword.Word)
+	}
+
+	sections := make([]string, 0, len(article.Categories))
+	for _, category := range article.Categories {
+		sections = append(sections, category.Name)
+	}
+
+	return &schema.Article{
+		Headline:         article.Title,
+		Description:      article.MetaDesc,
+		Image:            []string{image},
+		Author:           author,
+		Publisher:        s.siteOrganization(),
+		DatePublished:    publishedAt,
+		DateModified:     article.UpdatedAt.Format(time.RFC3339),
+		MainEntityOfPage: &schema.WebPage{ID: s.ArticleCanonicalURL(article)},
+		Keywords:         strings.Join(keywords, ","),
+		ArticleSection:   sections,
+	}
+}
+
+// GenerateBreadcrumbSchema 根据分类路径（从根分类到文章所在分类）生成面包屑
+func (s *SEOService) GenerateBreadcrumbSchema(article *models.Article, categoryPath []models.Category) *schema.BreadcrumbList {
+	items := []schema.ListItem{{Name: "首页", Item: s.config.SEO.SiteURL}}
+	for _, category := range categoryPath {
+		items = append(items, schema.ListItem{Name: category.Name, Item: s.CategoryURL(category.Name)})
+	}
+	items = append(items, schema.ListItem{Name: article.Title, Item: s.GenerateCanonicalURL(article.Slug)})
+
+	return schema.NewBreadcrumbList(items...)
+}
+
+// GenerateHowToSchema 识别正文中的操作步骤列表并生成操作指南，不是步骤类文章时返回nil
+func (s *SEOService) GenerateHowToSchema(article *models.Article) *schema.HowTo {
+	// 运动养生等分类下的文章，任意有序列表都按步骤处理
+	stepCategory := false
+	for _, category := range article.Categories {
+		if strings.Contains(category.Name, "运动") || strings.Contains(category.Name, "功法") {
+			stepCategory = true
+		}
+	}
+
+	var heading string
+	var steps []schema.HowToStep
+
+	flush := func() *schema.HowTo {
+		if len(steps) >= minHowToSteps && (stepCategory || howToHeadingPattern.MatchString(heading)) {
+			name := heading
+			if name == "" {
+				name = article.Title
+			}
+			return &schema.HowTo{
+				Name:        name,
+				Description: article.MetaDesc,
+				Step:        steps,
+			}
+		}
+		steps = nil
+		return nil
+	}
+
+	for _, line := range strings.Split(article.Content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "##") {
+			if howTo := flush(); howTo != nil {
+				return howTo
+			}
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+
+		if m := orderedStepPattern.FindStringSubmatch(line); m != nil {
+			text := strings.ReplaceAll(m[1], "**", "")
+			steps = append(steps, schema.HowToStep{Text: strings.TrimSpace(text)})
+		}
+	}
+
+	return flush()
+}
+
+// GenerateFAQPageSchema 生成常见问题结构化数据，没有常见问题时返回nil
+func (s *SEOService) GenerateFAQPageSchema(faqs []models.ArticleFAQ) *schema.FAQPage {
+	if len(faqs) == 0 {
+		return nil
+	}
+
+	page := &schema.FAQPage{MainEntity: make([]schema.Question, 0, len(faqs))}
+	for _, faq := range faqs {
+		page.MainEntity = append(page.MainEntity, schema.Question{
+			Name:           faq.Question,
+			AcceptedAnswer: schema.Answer{Text: faq.Answer},
+		})
+	}
+
+	return page
+}
+
+// GenerateArticleGraph 生成文章页的全部结构化数据，可选的类型校验失败时跳过
+func (s *SEOService) GenerateArticleGraph(article *models.Article, categoryPath []models.Category) (string, error) {
+	nodes := []schema.Node{s.GenerateArticleSchema(article)}
+	if err := nodes[0].Validate(); err != nil {
+		return "", err
+	}
+
+	optional := []schema.Node{s.GenerateBreadcrumbSchema(article, categoryPath)}
+	if faq := s.GenerateFAQPageSchema(article.FAQs); faq != nil {
+		optional = append(optional, faq)
+	}
+	if howTo := s.GenerateHowToSchema(article); howTo != nil {
+		optional = append(optional, howTo)
+	}
+
+	for _, node := range optional {
+		if err := node.Validate(); err != nil {
+			log.Printf("跳过无效的结构化数据: %v", err)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	data, err := schema.MarshalGraph(nodes...)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,354 @@
+package seo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NietzscheX/seo-generate/config"
+)
+
+// URL提交类型
+const (
+	SubmitActionPublish = "publish"
+	SubmitActionUpdate  = "update"
+	SubmitActionDelete  = "delete"
+)
+
+// 搜索引擎URL提交的默认地址
+const (
+	DefaultBaiduPushEndpoint  = "http://data.zz.baidu.com"
+	DefaultIndexNowEndpoint   = "https://api.indexnow.org/indexnow"
+	DefaultBingSubmitEndpoint = "https://ssl.bing.com/webmaster/api.svc/json"
+)
+
+// maxSubmitResponseLength 保存的响应内容最大长度
+const maxSubmitResponseLength = 2000
+
+// SubmitResult URL提交结果
+type SubmitResult struct {
+	StatusCode int
+	Accepted   int
+	Remaining  *int // 搜索引擎返回的当日剩余配额，未返回时为nil
+	Response   string
+}
+
+// URLSubmitter 搜索引擎URL提交接口
+type URLSubmitter interface {
+	// Name 搜索引擎名称
+	Name() string
+	// Enabled 是否已配置令牌或密钥
+	Enabled() bool
+	// Supports 是否支持该提交类型
+	Supports(action string) bool
+	// Submit 提交URL，请求已发出时即使失败也返回结果，便于记录日志
+	Submit(ctx context.Context, urls []string, action string) (*SubmitResult, error)
+}
+
+// doSubmit 发送提交请求并读取响应
+func doSubmit(ctx context.Context, client *http.Client, method, endpoint, contentType string, body []byte) (*SubmitResult, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	result := &SubmitResult{StatusCode: resp.StatusCode}
+	if err != nil {
+		return result, nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+
+	result.Response = string(respBody)
+	if len(result.Response) > maxSubmitResponseLength {
+		result.Response = result.Response[:maxSubmitResponseLength]
+	}
+	return result, respBody, nil
+}
+
+// endpointOrDefault 未配置地址时使用默认地址
+func endpointOrDefault(endpoint, fallback string) string {
+	if endpoint == "" {
+		return fallback
+	}
+	return strings.TrimRight(endpoint, "/")
+}
+
+// BaiduSubmitter 百度普通收录API
+type BaiduSubmitter struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// NewBaiduSubmitter 创建百度普通收录提交客户端
+func NewBaiduSubmitter(cfg *config.Config) *BaiduSubmitter {
+	return &BaiduSubmitter{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+}
+
+// BaiduPushResponse 百度普通收录响应
+type BaiduPushResponse struct {
+	Remain      *int     `json:"remain"`
+	Success     int      `json:"success"`
+	NotSameSite []string `json:"not_same_site"`
+	NotValid    []string `json:"not_valid"`
+	Error       int      `json:"error"`
+	Message     string   `json:"message"`
+}
+
+// Name 搜索引擎名称
+func (b *BaiduSubmitter) Name() string {
+	return "baidu"
+}
+
+// Enabled 是否已配置令牌
+func (b *BaiduSubmitter) Enabled() bool {
+	return b.config.Indexing.BaiduToken != ""
+}
+
+// Supports 普通收录只接收新增和更新的链接，删除的链接需要通过死链提交处理
+func (b *BaiduSubmitter) Supports(action string) bool {
+	return action == SubmitActionPublish || action == SubmitActionUpdate
+}
+
+// Submit 提交URL，请求体为每行一个URL的纯文本
+func (b *BaiduSubmitter) Submit(ctx context.Context, urls []string, action string) (*SubmitResult, error) {
+	site := b.config.Indexing.BaiduSite
+	if site == "" {
+		site = b.config.SEO.SiteURL
+	}
+	endpoint := fmt.Sprintf("%s/urls?site=%s&token=%s",
+		endpointOrDefault(b.config.Indexing.BaiduEndpoint, DefaultBaiduPushEndpoint),
+		url.QueryEscape(site), url.QueryEscape(b.config.Indexing.BaiduToken))
+
+	result, body, err := doSubmit(ctx, b.httpClient, http.MethodPost, endpoint, "text/plain", []byte(strings.Join(urls, "\n")))
+	if err != nil {
+		return result, err
+	}
+
+	// 解析响应
+	var response BaiduPushResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return result, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	result.Remaining = response.Remain
+	result.Accepted = response.Success
+	if result.StatusCode != http.StatusOK || response.Error != 0 {
+		return result, fmt.Errorf("百度普通收录错误(%d): %s", result.StatusCode, response.Message)
+	}
+	if response.Success < len(urls) {
+		return result, fmt.Errorf("百度普通收录未接收%d个URL，非本站%d个，无效%d个",
+			len(urls)-response.Success, len(response.NotSameSite), len(response.NotValid))
+	}
+	return result, nil
+}
+
+// IndexNowSubmitter IndexNow协议，提交后由参与的搜索引擎共享
+type IndexNowSubmitter struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// NewIndexNowSubmitter 创建IndexNow提交客户端
+func NewIndexNowSubmitter(cfg *config.Config) *IndexNowSubmitter {
+	return &IndexNowSubmitter{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+}
+
+// IndexNowRequest IndexNow请求
+type IndexNowRequest struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	URLList     []string `json:"urlList"`
+}
+
+// Name 搜索引擎名称
+func (i *IndexNowSubmitter) Name() string {
+	return "indexnow"
+}
+
+// Enabled 是否已配置密钥
+func (i *IndexNowSubmitter) Enabled() bool {
+	return i.config.Indexing.IndexNowKey != ""
+}
+
+// Supports IndexNow同样用于通知已删除的URL
+func (i *IndexNowSubmitter) Supports(action string) bool {
+	return true
+}
+
+// KeyLocation 密钥文件地址，默认为站点根目录下的{密钥}.txt
+func (i *IndexNowSubmitter) KeyLocation() string {
+	if i.config.Indexing.IndexNowKeyLocation != "" {
+		return i.config.Indexing.IndexNowKeyLocation
+	}
+	return strings.TrimRight(i.config.SEO.SiteURL, "/") + "/" + i.config.Indexing.IndexNowKey + ".txt"
+}
+
+// Submit 提交URL，返回200或202表示已接收
+func (i *IndexNowSubmitter) Submit(ctx context.Context, urls []string, action string) (*SubmitResult, error) {
+	site, err := url.Parse(i.config.SEO.SiteURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析站点地址失败: %w", err)
+	}
+
+	requestBody, err := json.Marshal(IndexNowRequest{
+		Host:        site.Host,
+		Key:         i.config.Indexing.IndexNowKey,
+		KeyLocation: i.KeyLocation(),
+		URLList:     urls,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	endpoint := endpointOrDefault(i.config.Indexing.IndexNowEndpoint, DefaultIndexNowEndpoint)
+	result, _, err := doSubmit(ctx, i.httpClient, http.MethodPost, endpoint, "application/json; charset=utf-8", requestBody)
+	if err != nil {
+		return result, err
+	}
+
+	if result.StatusCode != http.StatusOK && result.StatusCode != http.StatusAccepted {
+		return result, fmt.Errorf("IndexNow错误(%d): %s", result.StatusCode, result.Response)
+	}
+	result.Accepted = len(urls)
+	return result, nil
+}
+
+// BingSubmitter Bing网站管理员URL提交API
+type BingSubmitter struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// NewBingSubmitter 创建Bing URL提交客户端
+func NewBingSubmitter(cfg *config.Config) *BingSubmitter {
+	return &BingSubmitter{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+}
+
+// BingSubmitRequest Bing批量提交请求
+type BingSubmitRequest struct {
+	SiteURL string   `json:"siteUrl"`
+	URLList []string `json:"urlList"`
+}
+
+// BingQuotaResponse Bing提交配额响应
+type BingQuotaResponse struct {
+	D struct {
+		DailyQuota   int `json:"DailyQuota"`
+		MonthlyQuota int `json:"MonthlyQuota"`
+	} `json:"d"`
+}
+
+// BingErrorResponse Bing错误响应
+type BingErrorResponse struct {
+	ErrorCode int    `json:"ErrorCode"`
+	Message   string `json:"Message"`
+}
+
+// Name 搜索引擎名称
+func (b *BingSubmitter) Name() string {
+	return "bing"
+}
+
+// Enabled 是否已配置API密钥
+func (b *BingSubmitter) Enabled() bool {
+	return b.config.Indexing.BingAPIKey != ""
+}
+
+// Supports Bing会重新抓取提交的URL，删除的URL同样可以提交
+func (b *BingSubmitter) Supports(action string) bool {
+	return true
+}
+
+// endpoint 接口地址
+func (b *BingSubmitter) endpoint(method string) string {
+	return fmt.Sprintf("%s/%s?apikey=%s",
+		endpointOrDefault(b.config.Indexing.BingEndpoint, DefaultBingSubmitEndpoint),
+		method, url.QueryEscape(b.config.Indexing.BingAPIKey))
+}
+
+// Submit 批量提交URL，成功后查询剩余配额
+func (b *BingSubmitter) Submit(ctx context.Context, urls []string, action string) (*SubmitResult, error) {
+	requestBody, err := json.Marshal(BingSubmitRequest{
+		SiteURL: b.config.SEO.SiteURL,
+		URLList: urls,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	result, body, err := doSubmit(ctx, b.httpClient, http.MethodPost, b.endpoint("SubmitUrlbatch"), "application/json; charset=utf-8", requestBody)
+	if err != nil {
+		return result, err
+	}
+
+	if result.StatusCode != http.StatusOK {
+		var response BingErrorResponse
+		if err := json.Unmarshal(body, &response); err == nil && response.Message != "" {
+			return result, fmt.Errorf("Bing提交错误(%d): %s", result.StatusCode, response.Message)
+		}
+		return result, fmt.Errorf("Bing提交错误(%d): %s", result.StatusCode, result.Response)
+	}
+	result.Accepted = len(urls)
+
+	// 查询剩余配额，失败不影响提交结果
+	if remaining, err := b.Quota(ctx); err == nil {
+		result.Remaining = &remaining
+	}
+	return result, nil
+}
+
+// Quota 查询当日剩余的URL提交配额
+func (b *BingSubmitter) Quota(ctx context.Context) (int, error) {
+	endpoint := b.endpoint("GetUrlSubmissionQuota") + "&siteUrl=" + url.QueryEscape(b.config.SEO.SiteURL)
+	result, body, err := doSubmit(ctx, b.httpClient, http.MethodGet, endpoint, "", nil)
+	if err != nil {
+		return 0, err
+	}
+	if result.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("查询Bing配额失败(%d): %s", result.StatusCode, result.Response)
+	}
+
+	var response BingQuotaResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, fmt.Errorf("解析响应失败: %w", err)
+	}
+	return response.D.DailyQuota, nil
+}
+
+// 确保类型实现了URLSubmitter
+var (
+	_ URLSubmitter = (*BaiduSubmitter)(nil)
+	_ URLSubmitter = (*IndexNowSubmitter)(nil)
+	_ URLSubmitter = (*BingSubmitter)(nil)
+)
@@ -0,0 +1,256 @@
+package seo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NietzscheX/seo-generate/config"
+)
+
+// testConfig 指向本地替身服务器的配置
+func testConfig(endpoint string) *config.Config {
+	cfg := &config.Config{}
+	cfg.SEO.SiteURL = "https://www.example.com"
+	cfg.Indexing.BaiduEndpoint = endpoint
+	cfg.Indexing.BaiduToken = "baidu-token"
+	cfg.Indexing.IndexNowEndpoint = endpoint + "/indexnow"
+	cfg.Indexing.IndexNowKey = "0123456789abcdef"
+	cfg.Indexing.BingEndpoint = endpoint
+	cfg.Indexing.BingAPIKey = "bing-key"
+	return cfg
+}
+
+func TestBaiduSubmitter(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		response  string
+		wantErr   string
+		accepted  int
+		remaining int
+	}{
+		{
+			name:      "success",
+			status:    http.StatusOK,
+			response:  `{"remain":4998,"success":2}`,
+			accepted:  2,
+			remaining: 4998,
+		},
+		{
+			name:      "partially rejected",
+			status:    http.StatusOK,
+			response:  `{"remain":4999,"success":1,"not_same_site":["https://other.com/a"]}`,
+			wantErr:   "非本站1个",
+			accepted:  1,
+			remaining: 4999,
+		},
+		{
+			name:      "quota exhausted",
+			status:    http.StatusBadRequest,
+			response:  `{"error":400,"message":"over quota","remain":0}`,
+			wantErr:   "over quota",
+			remaining: 0,
+		},
+		{
+			name:      "invalid token",
+			status:    http.StatusUnauthorized,
+			response:  `{"error":401,"message":"token is not valid"}`,
+			wantErr:   "token is not valid",
+			remaining: -1,
+		},
+	}
+
+	urls := []string{"https://www.example.com/health/a", "https://www.example.com/health/b"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/urls" {
+					t.Errorf("请求错误: %s %s", r.Method, r.URL.Path)
+				}
+				if r.URL.Query().Get("site") != "https://www.example.com" || r.URL.Query().Get("token") != "baidu-token" {
+					t.Errorf("查询参数错误: %s", r.URL.RawQuery)
+				}
+				if r.Header.Get("Content-Type") != "text/plain" {
+					t.Errorf("Content-Type错误: %s", r.Header.Get("Content-Type"))
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != strings.Join(urls, "\n") {
+					t.Errorf("请求体错误: %q", body)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			result, err := NewBaiduSubmitter(testConfig(server.URL)).Submit(context.Background(), urls, SubmitActionPublish)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("提交失败: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("期望错误包含%q，实际为 %v", tt.wantErr, err)
+			}
+			if result == nil {
+				t.Fatal("请求已发出时应返回结果")
+			}
+			if result.StatusCode != tt.status {
+				t.Errorf("期望状态码%d，实际为%d", tt.status, result.StatusCode)
+			}
+			if result.Accepted != tt.accepted {
+				t.Errorf("期望接收%d个，实际为%d个", tt.accepted, result.Accepted)
+			}
+			if tt.remaining < 0 {
+				if result.Remaining != nil {
+					t.Errorf("未返回剩余配额时应为nil，实际为%d", *result.Remaining)
+				}
+			} else if result.Remaining == nil || *result.Remaining != tt.remaining {
+				t.Errorf("期望剩余配额%d，实际为%v", tt.remaining, result.Remaining)
+			}
+		})
+	}
+}
+
+func TestBaiduSubmitterSupports(t *testing.T) {
+	submitter := NewBaiduSubmitter(testConfig("http://127.0.0.1"))
+	if submitter.Supports(SubmitActionDelete) {
+		t.Error("百度普通收录不应提交删除的URL")
+	}
+	if !submitter.Supports(SubmitActionPublish) || !submitter.Supports(SubmitActionUpdate) {
+		t.Error("百度普通收录应提交新增和更新的URL")
+	}
+
+	cfg := testConfig("http://127.0.0.1")
+	cfg.Indexing.BaiduToken = ""
+	if NewBaiduSubmitter(cfg).Enabled() {
+		t.Error("未配置令牌时不应启用")
+	}
+}
+
+func TestIndexNowSubmitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "ok", status: http.StatusOK},
+		{name: "key not valid", status: http.StatusForbidden, wantErr: true},
+		{name: "too many requests", status: http.StatusTooManyRequests, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/indexnow" {
+					t.Errorf("请求错误: %s %s", r.Method, r.URL.Path)
+				}
+				var req IndexNowRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("解析请求体失败: %v", err)
+				}
+				if req.Host != "www.example.com" || req.Key != "0123456789abcdef" {
+					t.Errorf("请求体错误: %+v", req)
+				}
+				if req.KeyLocation != "https://www.example.com/0123456789abcdef.txt" {
+					t.Errorf("密钥文件地址错误: %s", req.KeyLocation)
+				}
+				if len(req.URLList) != 1 || req.URLList[0] != "https://www.example.com/health/a" {
+					t.Errorf("URL列表错误: %v", req.URLList)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			result, err := NewIndexNowSubmitter(testConfig(server.URL)).Submit(context.Background(), []string{"https://www.example.com/health/a"}, SubmitActionDelete)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("期望错误为%v，实际为 %v", tt.wantErr, err)
+			}
+			if result == nil || result.StatusCode != tt.status {
+				t.Fatalf("期望状态码%d，实际结果为 %+v", tt.status, result)
+			}
+			if !tt.wantErr && result.Accepted != 1 {
+				t.Errorf("期望接收1个，实际为%d个", result.Accepted)
+			}
+		})
+	}
+}
+
+func TestIndexNowKeyLocation(t *testing.T) {
+	cfg := testConfig("http://127.0.0.1")
+	cfg.Indexing.IndexNowKeyLocation = "https://www.example.com/keys/indexnow.txt"
+	if got := NewIndexNowSubmitter(cfg).KeyLocation(); got != cfg.Indexing.IndexNowKeyLocation {
+		t.Errorf("应使用配置的密钥文件地址，实际为 %s", got)
+	}
+}
+
+func TestBingSubmitter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/SubmitUrlbatch", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != "bing-key" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ErrorCode":3,"Message":"ERROR!!! InvalidApiKey"}`))
+			return
+		}
+		var req BingSubmitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("解析请求体失败: %v", err)
+		}
+		if req.SiteURL != "https://www.example.com" || len(req.URLList) != 2 {
+			t.Errorf("请求体错误: %+v", req)
+		}
+		w.Write([]byte(`{"d":null}`))
+	})
+	mux.HandleFunc("/GetUrlSubmissionQuota", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("siteUrl") != "https://www.example.com" {
+			t.Errorf("查询参数错误: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"d":{"__type":"UrlSubmissionQuota:#Microsoft.Bing.Webmaster.Api","DailyQuota":98,"MonthlyQuota":2798}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	urls := []string{"https://www.example.com/health/a", "https://www.example.com/health/b"}
+
+	t.Run("success", func(t *testing.T) {
+		result, err := NewBingSubmitter(testConfig(server.URL)).Submit(context.Background(), urls, SubmitActionUpdate)
+		if err != nil {
+			t.Fatalf("提交失败: %v", err)
+		}
+		if result.Accepted != 2 {
+			t.Errorf("期望接收2个，实际为%d个", result.Accepted)
+		}
+		if result.Remaining == nil || *result.Remaining != 98 {
+			t.Errorf("期望剩余配额98，实际为%v", result.Remaining)
+		}
+	})
+
+	t.Run("invalid api key", func(t *testing.T) {
+		cfg := testConfig(server.URL)
+		cfg.Indexing.BingAPIKey = "wrong"
+		result, err := NewBingSubmitter(cfg).Submit(context.Background(), urls, SubmitActionUpdate)
+		if err == nil || !strings.Contains(err.Error(), "InvalidApiKey") {
+			t.Fatalf("期望API密钥错误，实际为 %v", err)
+		}
+		if result == nil || result.StatusCode != http.StatusBadRequest {
+			t.Errorf("期望状态码400，实际结果为 %+v", result)
+		}
+	})
+}
+
+func TestSubmitUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	server.Close()
+
+	result, err := NewIndexNowSubmitter(testConfig(endpoint)).Submit(context.Background(), []string{"https://www.example.com/health/a"}, SubmitActionPublish)
+	if err == nil {
+		t.Fatal("服务器不可达时应返回错误")
+	}
+	if result != nil {
+		t.Errorf("请求未发出时不应返回结果，实际为 %+v", result)
+	}
+}
@@ -0,0 +1,72 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// utf8BOM Excel保存的UTF-8 CSV文件开头的BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadCSV 读取CSV，去掉UTF-8 BOM；不是合法UTF-8时按GBK解码（中文版Excel默认保存为GBK）
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("解码GBK文件失败: %w", err)
+		}
+		data = decoded
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析CSV失败: %w", err)
+		}
+		for i := range record {
+			record[i] = unescapeFormula(record[i])
+		}
+		rows = append(rows, record)
+		if err := checkRows(len(rows)); err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
+
+// WriteCSV 写出带BOM的UTF-8 CSV，便于Excel直接打开，以公式字符开头的文本会加单引号
+func WriteCSV(w io.Writer, rows [][]string) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return fmt.Errorf("写入CSV失败: %w", err)
+	}
+
+	escaped := make([][]string, len(rows))
+	for i, row := range rows {
+		escaped[i] = make([]string, len(row))
+		for j, value := range row {
+			escaped[i][j] = escapeFormula(value)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(escaped); err != nil {
+		return fmt.Errorf("写入CSV失败: %w", err)
+	}
+	return nil
+}
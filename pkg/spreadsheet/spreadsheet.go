@@ -0,0 +1,92 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// 支持的表格格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// MaxRows 读取表格的最大行数
+const MaxRows = 100000
+
+// ErrUnsupportedFormat 不支持的表格格式
+var ErrUnsupportedFormat = errors.New("不支持的文件格式，仅支持CSV和XLSX")
+
+// FormatOf 根据文件名判断表格格式
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".txt":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Read 按格式读取表格，返回所有行（第一个工作表）
+func Read(format string, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(data)
+	case FormatXLSX:
+		return ReadXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Write 按格式写出表格
+func Write(w io.Writer, format, sheetName string, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, rows)
+	case FormatXLSX:
+		return WriteXLSX(w, sheetName, rows)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// ContentType 表格格式对应的MIME类型
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// formulaPrefixes 表格软件会当作公式执行的开头字符
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula 以公式字符开头的文本前加单引号，防止导出的表格被打开时执行公式；整数写为数值，不需要转义
+func escapeFormula(value string) string {
+	if value == "" || isInteger(value) || !strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return value
+	}
+	return "'" + value
+}
+
+// unescapeFormula 去掉导出时加在公式字符前的单引号，使导出的表格可以原样导入
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// checkRows 检查行数是否超出限制
+func checkRows(n int) error {
+	if n > MaxRows {
+		return fmt.Errorf("表格超过%d行", MaxRows)
+	}
+	return nil
+}
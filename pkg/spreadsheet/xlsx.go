@@ -0,0 +1,350 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize 解压单个XML文件的最大字节数，防止压缩炸弹
+const maxXLSXPartSize = 64 << 20
+
+// maxColumns XLSX支持的最大列数（XFD列）
+const maxColumns = 16384
+
+// xlsxWorkbook 工作簿中的工作表列表
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships 工作簿的关系文件
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 共享字符串或内联字符串，可能由多个格式片段组成
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String 拼接后的文本
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+// xlsxSharedStrings 共享字符串表
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxWorksheet 工作表数据
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string    `xml:"r,attr"`
+			Type   string    `xml:"t,attr"`
+			Value  string    `xml:"v"`
+			Inline *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取XLSX文件第一个工作表的所有行
+func ReadXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("打开XLSX文件失败: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("XLSX文件中缺少工作表%s", sheetPath)
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeXLSXPart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		// 跳过的空行按行号补齐，保证行号与Excel中一致
+		index := len(rows)
+		if row.R > 0 {
+			index = row.R - 1
+		}
+		if err := checkRows(index + 1); err != nil {
+			return nil, err
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				n, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("单元格%s引用了无效的共享字符串", cell.Ref)
+				}
+				cells[column] = shared.Items[n].String()
+			case "inlineStr":
+				if cell.Inline != nil {
+					cells[column] = cell.Inline.String()
+				}
+			case "b":
+				cells[column] = "FALSE"
+				if cell.Value == "1" {
+					cells[column] = "TRUE"
+				}
+			default:
+				cells[column] = cell.Value
+			}
+			cells[column] = unescapeFormula(cells[column])
+		}
+		rows[index] = cells
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath 查找工作簿中第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return fallback, nil
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("XLSX文件中没有工作表")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXLSXPart 解压并解析XLSX中的XML文件
+func decodeXLSXPart(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("读取%s失败: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("解析%s失败: %w", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex 将单元格引用（如"B12"）转换为从0开始的列号
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for _, r := range ref {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A') + 1
+		if column > maxColumns {
+			return 0, fmt.Errorf("无效的单元格引用%s", ref)
+		}
+	}
+	if column == 0 {
+		return 0, fmt.Errorf("无效的单元格引用%s", ref)
+	}
+	return column - 1, nil
+}
+
+// ColumnIndex 将列名（如"A"、"AB"）转换为从0开始的列号，不是列名时返回false
+func ColumnIndex(name string) (int, bool) {
+	if name == "" || len(name) > 3 {
+		return 0, false
+	}
+	for _, r := range name {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z') {
+			return 0, false
+		}
+	}
+	column, err := columnIndex(name)
+	return column, err == nil
+}
+
+// columnName 将从0开始的列号转换为列名
+func columnName(index int) string {
+	var name []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = append([]byte{byte('A' + (index-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// xlsxStaticParts XLSX文件中固定的内容
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// WriteXLSX 写出只有一个工作表的XLSX文件，整数写为数值单元格，其他内容写为内联字符串，以公式字符开头的文本会加单引号
+func WriteXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	archive := zip.NewWriter(w)
+
+	for _, part := range xlsxStaticParts {
+		if err := writeXLSXPart(archive, part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	// 工作表名称最多31个字符
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	if name := []rune(sheetName); len(name) > 31 {
+		sheetName = string(name[:31])
+	}
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+		`<sheet name="` + escapeXML(sheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeXLSXPart(archive, "xl/workbook.xml", workbook); err != nil {
+		return err
+	}
+
+	var sheet strings.Builder
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			ref := columnName(j) + strconv.Itoa(i+1)
+			if isInteger(value) {
+				fmt.Fprintf(&sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(escapeFormula(value)))
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	if err := writeXLSXPart(archive, "xl/worksheets/sheet1.xml", sheet.String()); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("写入XLSX失败: %w", err)
+	}
+	return nil
+}
+
+// writeXLSXPart 向XLSX中写入一个文件
+func writeXLSXPart(archive *zip.Writer, name, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("写入%s失败: %w", name, err)
+	}
+	if _, err := io.WriteString(part, content); err != nil {
+		return fmt.Errorf("写入%s失败: %w", name, err)
+	}
+	return nil
+}
+
+// escapeXML 转义XML文本
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// isInteger 是否为不带前导零的整数，这样的值写为数值后读回时保持不变
+func isInteger(s string) bool {
+	if len(s) == 0 || len(s) > 15 {
+		return false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return err == nil && strconv.FormatInt(n, 10) == s
+}
@@ -0,0 +1,226 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"关键词", "搜索量", "分类"},
+		{"春季养生", "1200", "四季养生"},
+		{"007", "-5", ""},
+		{"a<b & c>\"d\"", "1.5", "  前后空格  "},
+		{"=HYPERLINK(\"http://evil\")", "+1", "@SUM(A1)"},
+		{"-感冒", "", "多行\n文本"},
+		{},
+		{"", "", "第三列"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, "关键词", rows); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+
+	got, err := ReadXLSX(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadXLSX: %v", err)
+	}
+
+	// 空行读回为nil，其余行应完全一致
+	want := make([][]string, len(rows))
+	for i, row := range rows {
+		if len(row) > 0 {
+			want[i] = row
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestWriteXLSXEscapesFormulas(t *testing.T) {
+	rows := [][]string{{"=1+1", "+1", "-感冒", "@SUM(A1)", "-5", "正常"}}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, "", rows); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+
+	for _, want := range []string{
+		`<t xml:space="preserve">&#39;=1+1</t>`,
+		`<t xml:space="preserve">&#39;+1</t>`,
+		`<t xml:space="preserve">&#39;-感冒</t>`,
+		`<t xml:space="preserve">&#39;@SUM(A1)</t>`,
+		`<c r="E1"><v>-5</v></c>`,
+		`<t xml:space="preserve">正常</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet missing %s:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, [][]string{{"=1+1", "-5", "@x", "正常"}}); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	got := strings.TrimPrefix(buf.String(), string(utf8BOM))
+	if want := "'=1+1,-5,'@x,正常\n"; got != want {
+		t.Errorf("WriteCSV = %q, want %q", got, want)
+	}
+
+	rows, err := ReadCSV(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	if want := [][]string{{"=1+1", "-5", "@x", "正常"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("ReadCSV = %q, want %q", rows, want)
+	}
+}
+
+// excelWorkbook 按Excel保存的结构构造工作簿：共享字符串（含格式片段）、工作表不在默认路径、
+// 第二个工作表排在前面、跳过的行和列、布尔值和内联字符串
+func excelWorkbook(t *testing.T) []byte {
+	t.Helper()
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="导入" sheetId="2" r:id="rId3"/><sheet name="说明" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/></Relationships>`},
+		{"xl/sharedStrings.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4"><si><t>关键词</t></si><si><t>搜索量</t></si><si><r><rPr><b/></rPr><t>秋季</t></r><r><t xml:space="preserve">润燥</t></r></si><si><t>'=1+1</t></si></sst>`},
+		{"xl/worksheets/sheet1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>说明页</t></is></c></row></sheetData></worksheet>`},
+		{"xl/worksheets/sheet2.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><dimension ref="A1:D4"/><sheetData><row r="1" spans="1:2"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row><row r="3" spans="1:4"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>3600</v></c><c r="D3" t="b"><v>1</v></c></row><row r="4"><c r="A4" t="s"><v>3</v></c><c r="C4" t="inlineStr"><is><t>内联</t></is></c></row></sheetData></worksheet>`},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		w, err := archive.Create(part.name)
+		if err != nil {
+			t.Fatalf("create %s: %v", part.name, err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			t.Fatalf("write %s: %v", part.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSXExcelWorkbook(t *testing.T) {
+	got, err := ReadXLSX(excelWorkbook(t))
+	if err != nil {
+		t.Fatalf("ReadXLSX: %v", err)
+	}
+
+	want := [][]string{
+		{"关键词", "搜索量"},
+		nil,
+		{"秋季润燥", "3600", "", "TRUE"},
+		{"=1+1", "", "内联"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadXLSX:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestReadXLSXInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"not zip", []byte("关键词,搜索量"), "打开XLSX文件失败"},
+		{"bad shared string", xlsxWithSheet(t, `<row r="1"><c r="A1" t="s"><v>5</v></c></row>`), "无效的共享字符串"},
+		{"bad cell ref", xlsxWithSheet(t, `<row r="1"><c r="1A"><v>1</v></c></row>`), "无效的单元格引用"},
+		{"too many rows", xlsxWithSheet(t, `<row r="100001"><c r="A100001"><v>1</v></c></row>`), "表格超过"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadXLSX(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadXLSX error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for _, tt := range []struct {
+		index int
+		name  string
+	}{{0, "A"}, {25, "Z"}, {26, "AA"}, {701, "ZZ"}, {702, "AAA"}, {maxColumns - 1, "XFD"}} {
+		if got := columnName(tt.index); got != tt.name {
+			t.Errorf("columnName(%d) = %s, want %s", tt.index, got, tt.name)
+		}
+		if got, ok := ColumnIndex(tt.name); !ok || got != tt.index {
+			t.Errorf("ColumnIndex(%s) = %d, %v, want %d", tt.name, got, ok, tt.index)
+		}
+	}
+}
+
+// xlsxWithSheet 只有默认路径工作表的最简XLSX
+func xlsxWithSheet(t *testing.T, sheetData string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatalf("create sheet: %v", err)
+	}
+	if _, err := w.Write([]byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		sheetData + `</sheetData></worksheet>`)); err != nil {
+		t.Fatalf("write sheet: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// readPart 读取XLSX中的文件内容
+func readPart(t *testing.T, data []byte, name string) string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(content)
+	}
+	t.Fatalf("%s not found", name)
+	return ""
+}